@@ -85,6 +85,11 @@ func (h *Headscale) LoadACLPolicy(path string) error {
 			return err
 		}
 
+		policy.locations, err = yamlLocations(policyBytes)
+		if err != nil {
+			return err
+		}
+
 		log.Trace().
 			Interface("policy", policy).
 			Msg("Loaded policy from YAML")
@@ -95,6 +100,8 @@ func (h *Headscale) LoadACLPolicy(path string) error {
 			return err
 		}
 
+		policy.locations = huJSONLocations(policyBytes, ast)
+
 		ast.Standardize()
 		policyBytes = ast.Pack()
 		err = json.Unmarshal(policyBytes, &policy)
@@ -182,6 +189,85 @@ func (h *Headscale) generateACLRules() ([]tailcfg.FilterRule, error) {
 	return rules, nil
 }
 
+// exitNodeOnlyFilter rewrites the destinations of rules so that the traffic
+// addressed to the machine itself is dropped, while the traffic the machine
+// forwards (to the internet or its subnet routes) is still accepted.
+func exitNodeOnlyFilter(
+	rules []tailcfg.FilterRule,
+	machine Machine,
+) []tailcfg.FilterRule {
+	filtered := []tailcfg.FilterRule{}
+
+	for _, rule := range rules {
+		destPorts := []tailcfg.NetPortRange{}
+		for _, dest := range rule.DstPorts {
+			for _, prefix := range excludeMachineIPs(dest.IP, machine) {
+				destPorts = append(destPorts, tailcfg.NetPortRange{
+					IP:    prefix.String(),
+					Ports: dest.Ports,
+				})
+			}
+		}
+
+		if len(destPorts) == 0 {
+			continue
+		}
+
+		filtered = append(filtered, tailcfg.FilterRule{
+			SrcIPs:   rule.SrcIPs,
+			DstPorts: destPorts,
+			IPProto:  rule.IPProto,
+		})
+	}
+
+	return filtered
+}
+
+// excludeMachineIPs returns the prefixes covering the destination of a
+// filter rule, minus the IP addresses of the machine.
+func excludeMachineIPs(dest string, machine Machine) []netaddr.IPPrefix {
+	var builder netaddr.IPSetBuilder
+
+	switch {
+	case dest == "*":
+		builder.AddPrefix(netaddr.MustParseIPPrefix("0.0.0.0/0"))
+		builder.AddPrefix(netaddr.MustParseIPPrefix("::/0"))
+	default:
+		if prefix, err := netaddr.ParseIPPrefix(dest); err == nil {
+			builder.AddPrefix(prefix)
+
+			break
+		}
+
+		ip, err := netaddr.ParseIP(dest)
+		if err != nil {
+			log.Warn().
+				Str("machine", machine.Hostname).
+				Str("destination", dest).
+				Msg("Dropping unparsable destination from exit node only filter")
+
+			return nil
+		}
+		builder.Add(ip)
+	}
+
+	for _, address := range machine.IPAddresses {
+		builder.Remove(address)
+	}
+
+	set, err := builder.IPSet()
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("machine", machine.Hostname).
+			Msg("Cannot build exit node only filter")
+
+		return nil
+	}
+
+	return set.Prefixes()
+}
+
 func (h *Headscale) generateACLPolicySrcIP(
 	machines []Machine,
 	aclPolicy ACLPolicy,
@@ -196,22 +282,9 @@ func (h *Headscale) generateACLPolicyDest(
 	dest string,
 	needsWildcard bool,
 ) ([]tailcfg.NetPortRange, error) {
-	tokens := strings.Split(dest, ":")
-	if len(tokens) < expectedTokenItems || len(tokens) > 3 {
-		return nil, errInvalidPortFormat
-	}
-
-	var alias string
-	// We can have here stuff like:
-	// git-server:*
-	// 192.168.1.0/24:22
-	// tag:montreal-webserver:80,443
-	// tag:api-server:443
-	// example-host-1:*
-	if len(tokens) == expectedTokenItems {
-		alias = tokens[0]
-	} else {
-		alias = fmt.Sprintf("%s:%s", tokens[0], tokens[1])
+	alias, portsStr, err := splitACLDestination(dest)
+	if err != nil {
+		return nil, err
 	}
 
 	expanded, err := expandAlias(
@@ -223,7 +296,7 @@ func (h *Headscale) generateACLPolicyDest(
 	if err != nil {
 		return nil, err
 	}
-	ports, err := expandPorts(tokens[len(tokens)-1], needsWildcard)
+	ports, err := expandPorts(portsStr, needsWildcard)
 	if err != nil {
 		return nil, err
 	}
@@ -242,6 +315,29 @@ func (h *Headscale) generateACLPolicyDest(
 	return dests, nil
 }
 
+// splitACLDestination splits an ACL destination into its alias and ports.
+func splitACLDestination(dest string) (string, string, error) {
+	tokens := strings.Split(dest, ":")
+	if len(tokens) < expectedTokenItems || len(tokens) > 3 {
+		return "", "", errInvalidPortFormat
+	}
+
+	var alias string
+	// We can have here stuff like:
+	// git-server:*
+	// 192.168.1.0/24:22
+	// tag:montreal-webserver:80,443
+	// tag:api-server:443
+	// example-host-1:*
+	if len(tokens) == expectedTokenItems {
+		alias = tokens[0]
+	} else {
+		alias = fmt.Sprintf("%s:%s", tokens[0], tokens[1])
+	}
+
+	return alias, tokens[len(tokens)-1], nil
+}
+
 // parseProtocol reads the proto field of the ACL and generates a list of
 // protocols that will be allowed, following the IANA IP protocol number
 // https://www.iana.org/assignments/protocol-numbers/protocol-numbers.xhtml
@@ -0,0 +1,210 @@
+package headscale
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+)
+
+// ACLAliasTrace is the expansion of an alias of an ACL rule.
+type ACLAliasTrace struct {
+	Alias string
+	// Namespaces are the namespaces a group, or the owners of a tag,
+	// expanded to.
+	Namespaces []string
+	IPs        []string
+	// Ports are the ports of a destination.
+	Ports   string
+	Matched bool
+	// Error is set when the alias could not be expanded.
+	Error string
+}
+
+// ACLRuleTrace explains how an ACL rule was evaluated.
+type ACLRuleTrace struct {
+	Rule         int
+	Protocol     string
+	Sources      []ACLAliasTrace
+	Destinations []ACLAliasTrace
+	Accepted     bool
+	Reason       string
+}
+
+// ACLCheckResult is the decision of the ACL policy for a connection
+// between two machines, with the evaluation trace of each rule.
+type ACLCheckResult struct {
+	Allowed bool
+	Reason  string
+	Rules   []ACLRuleTrace
+}
+
+// CheckACL evaluates the ACL policy for a connection from src to dst on
+// port, 0 matching any port. Every rule is evaluated, so the trace explains
+// why each of them matched or not.
+func (h *Headscale) CheckACL(src *Machine, dst *Machine, port uint16) (*ACLCheckResult, error) {
+	if h.aclPolicy == nil {
+		return &ACLCheckResult{
+			Allowed: true,
+			Reason:  "no ACL policy is loaded, all the traffic is allowed",
+			Rules:   []ACLRuleTrace{},
+		}, nil
+	}
+
+	machines, err := h.ListMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ACLCheckResult{Rules: []ACLRuleTrace{}}
+	for index, acl := range h.aclPolicy.ACLs {
+		rule := h.traceACLRule(machines, index, acl, src, dst, port)
+		if rule.Accepted && !result.Allowed {
+			result.Allowed = true
+			result.Reason = fmt.Sprintf("accepted by rule %d", index)
+		}
+		result.Rules = append(result.Rules, rule)
+	}
+
+	if !result.Allowed {
+		result.Reason = "no rule accepts the connection, denied by default"
+	}
+
+	return result, nil
+}
+
+func (h *Headscale) traceACLRule(
+	machines []Machine,
+	index int,
+	acl ACL,
+	src *Machine,
+	dst *Machine,
+	port uint16,
+) ACLRuleTrace {
+	rule := ACLRuleTrace{
+		Rule:         index,
+		Protocol:     acl.Protocol,
+		Sources:      []ACLAliasTrace{},
+		Destinations: []ACLAliasTrace{},
+	}
+
+	sourceMatched := false
+	for _, alias := range acl.Sources {
+		trace := h.traceACLAlias(machines, alias, src)
+		sourceMatched = sourceMatched || trace.Matched
+		rule.Sources = append(rule.Sources, trace)
+	}
+
+	_, needsWildcard, err := parseProtocol(acl.Protocol)
+	if err != nil {
+		rule.Reason = fmt.Sprintf("invalid protocol %q: %s", acl.Protocol, err)
+
+		return rule
+	}
+
+	destinationMatched := false
+	portMatched := false
+	for _, dest := range acl.Destinations {
+		alias, portsStr, err := splitACLDestination(dest)
+		if err != nil {
+			rule.Destinations = append(rule.Destinations, ACLAliasTrace{
+				Alias: dest,
+				Error: err.Error(),
+			})
+
+			continue
+		}
+
+		trace := h.traceACLAlias(machines, alias, dst)
+		trace.Ports = portsStr
+		rule.Destinations = append(rule.Destinations, trace)
+		if !trace.Matched {
+			continue
+		}
+		destinationMatched = true
+
+		ports, err := expandPorts(portsStr, needsWildcard)
+		if err != nil {
+			continue
+		}
+		for _, portRange := range *ports {
+			if port == 0 || (portRange.First <= port && port <= portRange.Last) {
+				portMatched = true
+			}
+		}
+	}
+
+	switch {
+	case !sourceMatched:
+		rule.Reason = fmt.Sprintf("source %s is not in %s", src.Hostname, strings.Join(acl.Sources, ", "))
+	case !destinationMatched:
+		rule.Reason = fmt.Sprintf(
+			"destination %s is not in %s",
+			dst.Hostname,
+			strings.Join(acl.Destinations, ", "),
+		)
+	case !portMatched:
+		rule.Reason = fmt.Sprintf("port %d is not allowed", port)
+	default:
+		rule.Accepted = true
+		rule.Reason = "accepted"
+	}
+
+	return rule
+}
+
+// traceACLAlias expands an alias and tells if it covers the machine.
+func (h *Headscale) traceACLAlias(machines []Machine, alias string, machine *Machine) ACLAliasTrace {
+	trace := ACLAliasTrace{Alias: alias}
+
+	switch {
+	case strings.HasPrefix(alias, "group:"):
+		namespaces, err := expandGroup(*h.aclPolicy, alias, h.cfg.OIDC.StripEmaildomain)
+		if err == nil {
+			trace.Namespaces = namespaces
+		}
+	case strings.HasPrefix(alias, "tag:"):
+		owners, err := expandTagOwners(*h.aclPolicy, alias, h.cfg.OIDC.StripEmaildomain)
+		if err == nil {
+			trace.Namespaces = owners
+		}
+	}
+
+	ips, err := expandAlias(machines, *h.aclPolicy, alias, h.cfg.OIDC.StripEmaildomain)
+	if err != nil && !errors.Is(err, errInvalidTag) {
+		trace.Error = err.Error()
+	}
+	trace.IPs = ips
+	trace.Matched = aliasContainsMachine(ips, machine)
+
+	return trace
+}
+
+func (trace ACLAliasTrace) toProto() *v1.ACLAliasTrace {
+	return &v1.ACLAliasTrace{
+		Alias:      trace.Alias,
+		Namespaces: trace.Namespaces,
+		Ips:        trace.IPs,
+		Ports:      trace.Ports,
+		Matched:    trace.Matched,
+		Error:      trace.Error,
+	}
+}
+
+func (rule ACLRuleTrace) toProto() *v1.ACLRuleTrace {
+	ruleProto := &v1.ACLRuleTrace{
+		Rule:     uint32(rule.Rule),
+		Protocol: rule.Protocol,
+		Accepted: rule.Accepted,
+		Reason:   rule.Reason,
+	}
+	for _, source := range rule.Sources {
+		ruleProto.Sources = append(ruleProto.Sources, source.toProto())
+	}
+	for _, destination := range rule.Destinations {
+		ruleProto.Destinations = append(ruleProto.Destinations, destination.toProto())
+	}
+
+	return ruleProto
+}
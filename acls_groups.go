@@ -0,0 +1,123 @@
+package headscale
+
+import (
+	"sort"
+	"strings"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+)
+
+const errACLGroupNotFound = Error("group not found in the ACL policy")
+
+// ACLGroup is a group of the ACL policy, with the machines it currently
+// covers.
+type ACLGroup struct {
+	Name string
+	// Members are the entries of the group in the policy.
+	Members []string
+	// Namespaces are the members once normalized, as matched against the
+	// namespaces of the machines.
+	Namespaces []string
+	// Tags are the tags the group owns in the tagOwners section.
+	Tags     []string
+	Machines []Machine
+	// Error is set when the group could not be expanded.
+	Error string
+}
+
+// ListACLGroups returns the groups of the loaded ACL policy, sorted by name.
+func (h *Headscale) ListACLGroups() ([]ACLGroup, error) {
+	if h.aclPolicy == nil {
+		return nil, errEmptyPolicy
+	}
+
+	machines, err := h.ListMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(h.aclPolicy.Groups))
+	for name := range h.aclPolicy.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	groups := make([]ACLGroup, 0, len(names))
+	for _, name := range names {
+		groups = append(groups, h.expandACLGroup(machines, name))
+	}
+
+	return groups, nil
+}
+
+// GetACLGroup returns a group of the loaded ACL policy. The "group:"
+// prefix of the name is optional.
+func (h *Headscale) GetACLGroup(name string) (*ACLGroup, error) {
+	if h.aclPolicy == nil {
+		return nil, errEmptyPolicy
+	}
+
+	if !strings.HasPrefix(name, "group:") {
+		name = "group:" + name
+	}
+	if _, ok := h.aclPolicy.Groups[name]; !ok {
+		return nil, errACLGroupNotFound
+	}
+
+	machines, err := h.ListMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	group := h.expandACLGroup(machines, name)
+
+	return &group, nil
+}
+
+// expandACLGroup resolves the namespaces of a group to their machines,
+// the same way the group is expanded in the ACL rules.
+func (h *Headscale) expandACLGroup(machines []Machine, name string) ACLGroup {
+	group := ACLGroup{
+		Name:       name,
+		Members:    h.aclPolicy.Groups[name],
+		Namespaces: []string{},
+		Tags:       []string{},
+		Machines:   []Machine{},
+	}
+
+	for tag, owners := range h.aclPolicy.TagOwners {
+		if contains(owners, name) {
+			group.Tags = append(group.Tags, tag)
+		}
+	}
+	sort.Strings(group.Tags)
+
+	namespaces, err := expandGroup(*h.aclPolicy, name, h.cfg.OIDC.StripEmaildomain)
+	if err != nil {
+		group.Error = err.Error()
+
+		return group
+	}
+	group.Namespaces = namespaces
+
+	for _, namespace := range namespaces {
+		group.Machines = append(group.Machines, filterMachinesByNamespace(machines, namespace)...)
+	}
+
+	return group
+}
+
+func (group ACLGroup) toProto() *v1.ACLGroup {
+	groupProto := &v1.ACLGroup{
+		Name:       group.Name,
+		Members:    group.Members,
+		Namespaces: group.Namespaces,
+		Tags:       group.Tags,
+		Error:      group.Error,
+	}
+	for _, machine := range group.Machines {
+		groupProto.Machines = append(groupProto.Machines, machine.toProto())
+	}
+
+	return groupProto
+}
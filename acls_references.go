@@ -0,0 +1,269 @@
+package headscale
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/tailscale/hujson"
+	"gopkg.in/yaml.v3"
+	"inet.af/netaddr"
+)
+
+const (
+	aclSectionACLs      = "acls"
+	aclSectionTagOwners = "tagOwners"
+	aclFieldSource      = "src"
+	aclFieldDestination = "dst"
+)
+
+// ACLReference is a place in the ACL policy where a tag or a machine is referenced.
+type ACLReference struct {
+	// Section is the top level section of the policy (acls or tagOwners).
+	Section string
+	// Rule is the index of the rule in the acls section.
+	Rule int
+	// Field is src or dst for the acls section, the tag for tagOwners.
+	Field string
+	Entry string
+	// Line is the line of the entry in the policy file, 0 if unknown.
+	Line int
+}
+
+// ListACLReferencesForTag returns every place in the ACL policy referencing tag.
+func (h *Headscale) ListACLReferencesForTag(tag string) ([]ACLReference, error) {
+	if h.aclPolicy == nil {
+		return nil, errEmptyPolicy
+	}
+
+	return h.aclPolicy.references(func(alias string) (bool, error) {
+		return alias == tag, nil
+	})
+}
+
+// ListACLReferencesForMachine returns every place in the ACL policy granting
+// or restricting access to machine, through any kind of alias.
+func (h *Headscale) ListACLReferencesForMachine(machine *Machine) ([]ACLReference, error) {
+	if h.aclPolicy == nil {
+		return nil, errEmptyPolicy
+	}
+
+	machines, err := h.ListMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	return h.aclPolicy.references(func(alias string) (bool, error) {
+		ips, err := expandAlias(
+			machines,
+			*h.aclPolicy,
+			alias,
+			h.cfg.OIDC.StripEmaildomain,
+		)
+		if err != nil && !errors.Is(err, errInvalidTag) {
+			return false, err
+		}
+
+		return aliasContainsMachine(ips, machine), nil
+	})
+}
+
+// references walks the policy and returns the entries for which match
+// returns true.
+func (policy ACLPolicy) references(
+	match func(alias string) (bool, error),
+) ([]ACLReference, error) {
+	references := []ACLReference{}
+
+	for index, acl := range policy.ACLs {
+		for innerIndex, src := range acl.Sources {
+			found, err := match(src)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				references = append(references, ACLReference{
+					Section: aclSectionACLs,
+					Rule:    index,
+					Field:   aclFieldSource,
+					Entry:   src,
+					Line: policy.locations[locationPath(
+						aclSectionACLs,
+						strconv.Itoa(index),
+						aclFieldSource,
+						strconv.Itoa(innerIndex),
+					)],
+				})
+			}
+		}
+
+		for innerIndex, dest := range acl.Destinations {
+			alias, _, err := splitACLDestination(dest)
+			if err != nil {
+				return nil, err
+			}
+			found, err := match(alias)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				references = append(references, ACLReference{
+					Section: aclSectionACLs,
+					Rule:    index,
+					Field:   aclFieldDestination,
+					Entry:   dest,
+					Line: policy.locations[locationPath(
+						aclSectionACLs,
+						strconv.Itoa(index),
+						aclFieldDestination,
+						strconv.Itoa(innerIndex),
+					)],
+				})
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(policy.TagOwners))
+	for tag := range policy.TagOwners {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	for _, tag := range tags {
+		found, err := match(tag)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			references = append(references, ACLReference{
+				Section: aclSectionTagOwners,
+				Field:   tag,
+				Entry:   strings.Join(policy.TagOwners[tag], ","),
+				Line:    policy.locations[locationPath(aclSectionTagOwners, tag)],
+			})
+		}
+	}
+
+	return references, nil
+}
+
+// aliasContainsMachine checks if one of the IPs or prefixes an alias
+// expanded to covers the machine.
+func aliasContainsMachine(ips []string, machine *Machine) bool {
+	for _, ipStr := range ips {
+		if ipStr == "*" {
+			return true
+		}
+
+		if prefix, err := netaddr.ParseIPPrefix(ipStr); err == nil {
+			for _, address := range machine.IPAddresses {
+				if prefix.Contains(address) {
+					return true
+				}
+			}
+
+			continue
+		}
+
+		if ip, err := netaddr.ParseIP(ipStr); err == nil {
+			for _, address := range machine.IPAddresses {
+				if ip == address {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func locationPath(elements ...string) string {
+	return strings.Join(elements, "/")
+}
+
+// huJSONLocations indexes the line of every value of a HuJSON document
+// by its path (e.g. acls/0/src/1).
+func huJSONLocations(data []byte, ast hujson.Value) map[string]int {
+	locations := make(map[string]int)
+
+	var walk func(path string, value hujson.Value)
+	walk = func(path string, value hujson.Value) {
+		if path != "" {
+			locations[path] = strings.Count(string(data[:value.StartOffset]), "\n") + 1
+		}
+
+		switch composite := value.Value.(type) {
+		case *hujson.Object:
+			for _, member := range composite.Members {
+				name, ok := member.Name.Value.(hujson.Literal)
+				if !ok {
+					continue
+				}
+				walk(childLocationPath(path, name.String()), member.Value)
+			}
+		case *hujson.Array:
+			for index, element := range composite.Elements {
+				walk(childLocationPath(path, strconv.Itoa(index)), element)
+			}
+		}
+	}
+	walk("", ast)
+
+	return locations
+}
+
+// yamlLocations indexes the line of every value of a YAML document
+// by its path (e.g. acls/0/src/1).
+func yamlLocations(data []byte) (map[string]int, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	locations := make(map[string]int)
+
+	var walk func(path string, node *yaml.Node)
+	walk = func(path string, node *yaml.Node) {
+		if path != "" {
+			locations[path] = node.Line
+		}
+
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, content := range node.Content {
+				walk(path, content)
+			}
+		case yaml.MappingNode:
+			for index := 0; index+1 < len(node.Content); index += 2 {
+				walk(childLocationPath(path, node.Content[index].Value), node.Content[index+1])
+			}
+		case yaml.SequenceNode:
+			for index, content := range node.Content {
+				walk(childLocationPath(path, strconv.Itoa(index)), content)
+			}
+		}
+	}
+	walk("", &document)
+
+	return locations, nil
+}
+
+func childLocationPath(path string, element string) string {
+	if path == "" {
+		return element
+	}
+
+	return locationPath(path, element)
+}
+
+func (reference ACLReference) toProto() *v1.ACLReference {
+	return &v1.ACLReference{
+		Section: reference.Section,
+		Rule:    uint32(reference.Rule),
+		Field:   reference.Field,
+		Entry:   reference.Entry,
+		Line:    uint32(reference.Line),
+	}
+}
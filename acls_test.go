@@ -367,6 +367,124 @@ func (s *Suite) TestPortWildcardYAML(c *check.C) {
 	c.Assert(rules[0].SrcIPs[0], check.Equals, "*")
 }
 
+func (s *Suite) TestACLReferences(c *check.C) {
+	namespace, err := app.CreateNamespace("testnamespace")
+	c.Assert(err, check.IsNil)
+
+	pak, err := app.CreatePreAuthKey(namespace.Name, false, false, nil)
+	c.Assert(err, check.IsNil)
+
+	machine := Machine{
+		ID:             1,
+		MachineKey:     "12345",
+		NodeKey:        "bar",
+		DiscoKey:       "faa",
+		Hostname:       "testmachine",
+		NamespaceID:    namespace.ID,
+		RegisterMethod: RegisterMethodAuthKey,
+		IPAddresses:    MachineAddresses{netaddr.MustParseIP("100.100.100.100")},
+		AuthKeyID:      uint(pak.ID),
+	}
+	app.db.Save(&machine)
+
+	for policyPath, lines := range map[string][]int{
+		"./tests/acls/acl_policy_basic_wildcards.hujson": {13, 16},
+		"./tests/acls/acl_policy_basic_wildcards.yaml":   {8, 10},
+	} {
+		err = app.LoadACLPolicy(policyPath)
+		c.Assert(err, check.IsNil)
+
+		references, err := app.ListACLReferencesForMachine(&machine)
+		c.Assert(err, check.IsNil)
+		c.Assert(references, check.DeepEquals, []ACLReference{
+			{Section: "acls", Rule: 0, Field: "src", Entry: "*", Line: lines[0]},
+			{Section: "acls", Rule: 0, Field: "dst", Entry: "host-1:*", Line: lines[1]},
+		})
+
+		references, err = app.ListACLReferencesForTag("tag:web")
+		c.Assert(err, check.IsNil)
+		c.Assert(references, check.HasLen, 0)
+	}
+
+	app.aclPolicy = &ACLPolicy{
+		TagOwners: TagOwners{"tag:web": []string{"testnamespace"}},
+		ACLs: []ACL{
+			{Action: "accept", Sources: []string{"tag:web"}, Destinations: []string{"tag:web:443"}},
+		},
+	}
+
+	references, err := app.ListACLReferencesForTag("tag:web")
+	c.Assert(err, check.IsNil)
+	c.Assert(references, check.DeepEquals, []ACLReference{
+		{Section: "acls", Rule: 0, Field: "src", Entry: "tag:web"},
+		{Section: "acls", Rule: 0, Field: "dst", Entry: "tag:web:443"},
+		{Section: "tagOwners", Field: "tag:web", Entry: "testnamespace"},
+	})
+}
+
+func (s *Suite) TestCheckACL(c *check.C) {
+	machines := []Machine{}
+	for index, namespaceName := range []string{"admins", "servers"} {
+		namespace, err := app.CreateNamespace(namespaceName)
+		c.Assert(err, check.IsNil)
+
+		machine := Machine{
+			ID:             uint64(index + 1),
+			MachineKey:     "foo" + namespaceName,
+			NodeKey:        "bar" + namespaceName,
+			DiscoKey:       "faa" + namespaceName,
+			Hostname:       "machine-" + namespaceName,
+			NamespaceID:    namespace.ID,
+			RegisterMethod: RegisterMethodAuthKey,
+			IPAddresses: MachineAddresses{
+				netaddr.IPv4(100, 64, 0, byte(index+1)),
+			},
+		}
+		app.db.Save(&machine)
+		machines = append(machines, machine)
+	}
+	admin, server := &machines[0], &machines[1]
+
+	result, err := app.CheckACL(admin, server, 22)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Allowed, check.Equals, true)
+
+	app.aclPolicy = &ACLPolicy{
+		Groups: Groups{"group:admins": []string{"admins"}},
+		ACLs: []ACL{
+			{Action: "accept", Sources: []string{"group:admins"}, Destinations: []string{"servers:22"}},
+			{Action: "accept", Sources: []string{"servers"}, Destinations: []string{"admins:*"}},
+		},
+	}
+
+	result, err = app.CheckACL(admin, server, 22)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Allowed, check.Equals, true)
+	c.Assert(result.Reason, check.Equals, "accepted by rule 0")
+	c.Assert(result.Rules, check.HasLen, 2)
+	c.Assert(result.Rules[0].Accepted, check.Equals, true)
+	c.Assert(result.Rules[0].Sources, check.DeepEquals, []ACLAliasTrace{
+		{
+			Alias:      "group:admins",
+			Namespaces: []string{"admins"},
+			IPs:        []string{"100.64.0.1"},
+			Matched:    true,
+		},
+	})
+	c.Assert(result.Rules[1].Accepted, check.Equals, false)
+	c.Assert(result.Rules[1].Reason, check.Equals, "source machine-admins is not in servers")
+
+	result, err = app.CheckACL(admin, server, 80)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Allowed, check.Equals, false)
+	c.Assert(result.Rules[0].Reason, check.Equals, "port 80 is not allowed")
+
+	result, err = app.CheckACL(server, admin, 80)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Allowed, check.Equals, true)
+	c.Assert(result.Reason, check.Equals, "accepted by rule 1")
+}
+
 func (s *Suite) TestPortNamespace(c *check.C) {
 	namespace, err := app.CreateNamespace("testnamespace")
 	c.Assert(err, check.IsNil)
@@ -1380,3 +1498,157 @@ func Test_excludeCorrectlyTaggedNodes(t *testing.T) {
 		})
 	}
 }
+
+func Test_exitNodeOnlyFilter(t *testing.T) {
+	machine := Machine{
+		Hostname: "exit",
+		IPAddresses: MachineAddresses{
+			netaddr.MustParseIP("100.64.0.1"),
+			netaddr.MustParseIP("fd7a:115c:a1e0::1"),
+		},
+	}
+	ports := tailcfg.PortRange{First: 22, Last: 22}
+
+	tests := []struct {
+		name  string
+		rules []tailcfg.FilterRule
+		want  []tailcfg.FilterRule
+	}{
+		{
+			name: "rule only targeting the machine is dropped",
+			rules: []tailcfg.FilterRule{
+				{
+					SrcIPs: []string{"100.64.0.2"},
+					DstPorts: []tailcfg.NetPortRange{
+						{IP: "100.64.0.1", Ports: ports},
+						{IP: "fd7a:115c:a1e0::1", Ports: ports},
+					},
+				},
+			},
+			want: []tailcfg.FilterRule{},
+		},
+		{
+			name: "prefix containing the machine is split",
+			rules: []tailcfg.FilterRule{
+				{
+					SrcIPs: []string{"100.64.0.2"},
+					DstPorts: []tailcfg.NetPortRange{
+						{IP: "100.64.0.0/30", Ports: ports},
+					},
+					IPProto: []int{protocolTCP},
+				},
+			},
+			want: []tailcfg.FilterRule{
+				{
+					SrcIPs: []string{"100.64.0.2"},
+					DstPorts: []tailcfg.NetPortRange{
+						{IP: "100.64.0.0/32", Ports: ports},
+						{IP: "100.64.0.2/31", Ports: ports},
+					},
+					IPProto: []int{protocolTCP},
+				},
+			},
+		},
+		{
+			name: "other destinations are kept",
+			rules: []tailcfg.FilterRule{
+				{
+					SrcIPs: []string{"*"},
+					DstPorts: []tailcfg.NetPortRange{
+						{IP: "100.64.0.1", Ports: ports},
+						{IP: "192.168.1.0/24", Ports: ports},
+					},
+				},
+			},
+			want: []tailcfg.FilterRule{
+				{
+					SrcIPs: []string{"*"},
+					DstPorts: []tailcfg.NetPortRange{
+						{IP: "192.168.1.0/24", Ports: ports},
+					},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := exitNodeOnlyFilter(test.rules, machine)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("exitNodeOnlyFilter() = %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	t.Run("allow all keeps the internet", func(t *testing.T) {
+		var builder netaddr.IPSetBuilder
+		for _, rule := range exitNodeOnlyFilter(tailcfg.FilterAllowAll, machine) {
+			for _, dest := range rule.DstPorts {
+				builder.AddPrefix(netaddr.MustParseIPPrefix(dest.IP))
+			}
+		}
+		set, err := builder.IPSet()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, address := range machine.IPAddresses {
+			if set.Contains(address) {
+				t.Errorf("exitNodeOnlyFilter() allows %s", address)
+			}
+		}
+		for _, address := range []string{"1.1.1.1", "100.64.0.2", "2001:db8::1"} {
+			if !set.Contains(netaddr.MustParseIP(address)) {
+				t.Errorf("exitNodeOnlyFilter() denies %s", address)
+			}
+		}
+	})
+}
+
+func (s *Suite) TestListACLGroups(c *check.C) {
+	_, err := app.ListACLGroups()
+	c.Assert(err, check.Equals, errEmptyPolicy)
+
+	for index, namespaceName := range []string{"admins", "servers"} {
+		namespace, err := app.CreateNamespace(namespaceName)
+		c.Assert(err, check.IsNil)
+
+		machine := Machine{
+			ID:             uint64(index + 1),
+			MachineKey:     "foo" + namespaceName,
+			NodeKey:        "bar" + namespaceName,
+			DiscoKey:       "faa" + namespaceName,
+			Hostname:       "machine-" + namespaceName,
+			NamespaceID:    namespace.ID,
+			RegisterMethod: RegisterMethodAuthKey,
+		}
+		app.db.Save(&machine)
+	}
+
+	app.aclPolicy = &ACLPolicy{
+		Groups: Groups{
+			"group:admins": []string{"admins"},
+			"group:broken": []string{"group:admins"},
+		},
+		TagOwners: TagOwners{"tag:web": []string{"group:admins"}},
+	}
+
+	groups, err := app.ListACLGroups()
+	c.Assert(err, check.IsNil)
+	c.Assert(groups, check.HasLen, 2)
+	c.Assert(groups[0].Name, check.Equals, "group:admins")
+	c.Assert(groups[0].Namespaces, check.DeepEquals, []string{"admins"})
+	c.Assert(groups[0].Tags, check.DeepEquals, []string{"tag:web"})
+	c.Assert(groups[0].Machines, check.HasLen, 1)
+	c.Assert(groups[0].Machines[0].Hostname, check.Equals, "machine-admins")
+	c.Assert(groups[1].Name, check.Equals, "group:broken")
+	c.Assert(groups[1].Machines, check.HasLen, 0)
+	c.Assert(groups[1].Error, check.Not(check.Equals), "")
+
+	group, err := app.GetACLGroup("admins")
+	c.Assert(err, check.IsNil)
+	c.Assert(group.Name, check.Equals, "group:admins")
+	c.Assert(group.Machines, check.HasLen, 1)
+
+	_, err = app.GetACLGroup("group:unknown")
+	c.Assert(err, check.Equals, errACLGroupNotFound)
+}
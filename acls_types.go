@@ -16,6 +16,10 @@ type ACLPolicy struct {
 	TagOwners TagOwners `json:"tagOwners" yaml:"tagOwners"`
 	ACLs      []ACL     `json:"acls"      yaml:"acls"`
 	Tests     []ACLTest `json:"tests"     yaml:"tests"`
+
+	// locations maps the path of the values of the policy file
+	// (e.g. acls/0/src/1) to their line.
+	locations map[string]int
 }
 
 // ACL is a basic rule for the ACL Policy.
@@ -204,6 +204,7 @@ func (h *Headscale) RegistrationHandler(
 	}
 
 	now := time.Now().UTC()
+	clockSkew := clientClockSkew(registerRequest, now)
 	machine, err := h.GetMachineByMachineKey(machineKey)
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Info().Str("machine", registerRequest.Hostinfo.Hostname).Msg("New machine")
@@ -214,6 +215,10 @@ func (h *Headscale) RegistrationHandler(
 		if registerRequest.Auth.AuthKey != "" {
 			h.handleAuthKey(writer, req, machineKey, registerRequest)
 
+			if machine, err := h.GetMachineByMachineKey(machineKey); err == nil {
+				h.recordClockSkew(machine, clockSkew)
+			}
+
 			return
 		}
 
@@ -239,6 +244,8 @@ func (h *Headscale) RegistrationHandler(
 			NodeKey:    NodePublicKeyStripPrefix(registerRequest.NodeKey),
 			LastSeen:   &now,
 			Expiry:     &time.Time{},
+			ClockSkew:  clockSkew,
+			Ephemeral:  registerRequest.Ephemeral,
 		}
 
 		if !registerRequest.Expiry.IsZero() {
@@ -263,6 +270,8 @@ func (h *Headscale) RegistrationHandler(
 
 	// The machine is already registered, so we need to pass through reauth or key update.
 	if machine != nil {
+		h.recordClockSkew(machine, clockSkew)
+
 		// If the NodeKey stored in headscale is the same as the key presented in a registration
 		// request, then we have a node that is either:
 		// - Trying to log out (sending a expiry in the past)
@@ -286,9 +295,10 @@ func (h *Headscale) RegistrationHandler(
 			}
 		}
 
-		// The NodeKey we have matches OldNodeKey, which means this is a refresh after a key expiration
+		// The NodeKey we have matches OldNodeKey, which means this is a refresh after a key expiration.
+		// A key expired by RequestMachineKeyRefresh is refreshed without reauthentication.
 		if machine.NodeKey == NodePublicKeyStripPrefix(registerRequest.OldNodeKey) &&
-			!machine.isExpired() {
+			(!machine.isExpired() || machine.keyRefreshRequested()) {
 			h.handleMachineRefreshKey(writer, req, machineKey, registerRequest, *machine)
 
 			return
@@ -310,11 +320,55 @@ func (h *Headscale) getMapResponse(
 		Str("func", "getMapResponse").
 		Str("machine", mapRequest.Hostinfo.Hostname).
 		Msg("Creating Map response")
+	resp, err := h.generateMapResponse(machine)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace().
+		Str("func", "getMapResponse").
+		Str("machine", mapRequest.Hostinfo.Hostname).
+		// Interface("payload", resp).
+		Msgf("Generated map response: %s", tailMapResponseToString(*resp))
+
+	var respBody []byte
+	if mapRequest.Compress == "zstd" {
+		src, err := json.Marshal(resp)
+		if err != nil {
+			log.Error().
+				Caller().
+				Str("func", "getMapResponse").
+				Err(err).
+				Msg("Failed to marshal response for the client")
+
+			return nil, err
+		}
+
+		encoder, _ := zstd.NewWriter(nil)
+		srcCompressed := encoder.EncodeAll(src, nil)
+		respBody = h.privateKey.SealTo(machineKey, srcCompressed)
+	} else {
+		respBody, err = encode(resp, &machineKey, h.privateKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// declare the incoming size on the first 4 bytes
+	data := make([]byte, reservedResponseHeaderSize)
+	binary.LittleEndian.PutUint32(data, uint32(len(respBody)))
+	data = append(data, respBody...)
+
+	return data, nil
+}
+
+// generateMapResponse builds the map response sent to a machine, with its
+// peers, packet filter and DNS configuration.
+func (h *Headscale) generateMapResponse(machine *Machine) (*tailcfg.MapResponse, error) {
 	node, err := machine.toNode(h.cfg.BaseDomain, h.cfg.DNSConfig, true)
 	if err != nil {
 		log.Error().
 			Caller().
-			Str("func", "getMapResponse").
+			Str("func", "generateMapResponse").
 			Err(err).
 			Msg("Cannot convert to node")
 
@@ -325,7 +379,7 @@ func (h *Headscale) getMapResponse(
 	if err != nil {
 		log.Error().
 			Caller().
-			Str("func", "getMapResponse").
+			Str("func", "generateMapResponse").
 			Err(err).
 			Msg("Cannot fetch peers")
 
@@ -338,7 +392,7 @@ func (h *Headscale) getMapResponse(
 	if err != nil {
 		log.Error().
 			Caller().
-			Str("func", "getMapResponse").
+			Str("func", "generateMapResponse").
 			Err(err).
 			Msg("Failed to convert peers to Tailscale nodes")
 
@@ -352,14 +406,24 @@ func (h *Headscale) getMapResponse(
 		peers,
 	)
 
-	resp := tailcfg.MapResponse{
+	packetFilter := h.aclRules
+	if machine.ExitNodeOnly {
+		packetFilter = exitNodeOnlyFilter(packetFilter, *machine)
+	}
+
+	derpMap := h.DERPMap
+	if machine.ForcedDERPRegion != 0 {
+		derpMap = forceDERPRegion(derpMap, machine.ForcedDERPRegion)
+	}
+
+	resp := &tailcfg.MapResponse{
 		KeepAlive:    false,
 		Node:         node,
 		Peers:        nodePeers,
 		DNSConfig:    dnsConfig,
 		Domain:       h.cfg.BaseDomain,
-		PacketFilter: h.aclRules,
-		DERPMap:      h.DERPMap,
+		PacketFilter: packetFilter,
+		DERPMap:      derpMap,
 		UserProfiles: profiles,
 		Debug: &tailcfg.Debug{
 			DisableLogTail:      !h.cfg.LogTail.Enabled,
@@ -367,40 +431,7 @@ func (h *Headscale) getMapResponse(
 		},
 	}
 
-	log.Trace().
-		Str("func", "getMapResponse").
-		Str("machine", mapRequest.Hostinfo.Hostname).
-		// Interface("payload", resp).
-		Msgf("Generated map response: %s", tailMapResponseToString(resp))
-
-	var respBody []byte
-	if mapRequest.Compress == "zstd" {
-		src, err := json.Marshal(resp)
-		if err != nil {
-			log.Error().
-				Caller().
-				Str("func", "getMapResponse").
-				Err(err).
-				Msg("Failed to marshal response for the client")
-
-			return nil, err
-		}
-
-		encoder, _ := zstd.NewWriter(nil)
-		srcCompressed := encoder.EncodeAll(src, nil)
-		respBody = h.privateKey.SealTo(machineKey, srcCompressed)
-	} else {
-		respBody, err = encode(resp, &machineKey, h.privateKey)
-		if err != nil {
-			return nil, err
-		}
-	}
-	// declare the incoming size on the first 4 bytes
-	data := make([]byte, reservedResponseHeaderSize)
-	binary.LittleEndian.PutUint32(data, uint32(len(respBody)))
-	data = append(data, respBody...)
-
-	return data, nil
+	return resp, nil
 }
 
 func (h *Headscale) getMapKeepAliveResponse(
@@ -599,6 +630,10 @@ func (h *Headscale) handleMachineRefreshKey(
 		Str("machine", machine.Hostname).
 		Msg("We have the OldNodeKey in the database. This is a key refresh")
 	machine.NodeKey = NodePublicKeyStripPrefix(registerRequest.NodeKey)
+	if machine.keyRefreshRequested() {
+		machine.completeKeyRefresh()
+		h.setLastStateChangeToNow(machine.Namespace.Name)
+	}
 
 	if err := h.db.Save(&machine).Error; err != nil {
 		log.Error().
@@ -775,6 +810,43 @@ func (h *Headscale) handleAuthKey(
 			return
 		}
 	} else {
+		if pak.Namespace.Locked {
+			log.Info().
+				Str("func", "handleAuthKey").
+				Str("machine", registerRequest.Hostinfo.Hostname).
+				Str("namespace", pak.Namespace.Name).
+				Msg("Refusing to register a new machine in a locked namespace")
+			machineRegistrations.WithLabelValues("new", RegisterMethodAuthKey, "error", pak.Namespace.Name).
+				Inc()
+
+			// The client shows the error of the response to the user.
+			resp.Error = errNamespaceLocked.Error()
+			respBody, err := encode(resp, &machineKey, h.privateKey)
+			if err != nil {
+				log.Error().
+					Caller().
+					Str("func", "handleAuthKey").
+					Str("machine", registerRequest.Hostinfo.Hostname).
+					Err(err).
+					Msg("Cannot encode message")
+				http.Error(writer, "Internal server error", http.StatusInternalServerError)
+
+				return
+			}
+
+			writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+			writer.WriteHeader(http.StatusOK)
+			_, err = writer.Write(respBody)
+			if err != nil {
+				log.Error().
+					Caller().
+					Err(err).
+					Msg("Failed to write response")
+			}
+
+			return
+		}
+
 		now := time.Now().UTC()
 
 		givenName, err := h.GenerateGivenName(registerRequest.Hostinfo.Hostname)
@@ -798,6 +870,7 @@ func (h *Headscale) handleAuthKey(
 			NodeKey:        nodeKey,
 			LastSeen:       &now,
 			AuthKeyID:      uint(pak.ID),
+			Ephemeral:      pak.Ephemeral || registerRequest.Ephemeral,
 		}
 
 		machine, err = h.RegisterMachine(
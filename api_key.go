@@ -2,6 +2,7 @@ package headscale
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -15,6 +16,13 @@ const (
 	apiKeyLength    = 32
 
 	errAPIKeyFailedToParse = Error("Failed to parse ApiKey")
+	errInvalidAPIKeyScope  = Error("invalid ApiKey scope, must be one of: full, read-only")
+
+	// APIKeyScopeFull keys can call every RPC.
+	APIKeyScopeFull = "full"
+	// APIKeyScopeReadOnly keys can only call the RPCs reading the state,
+	// the ones of readOnlyMethods.
+	APIKeyScopeReadOnly = "read-only"
 )
 
 // APIKey describes the datamodel for API keys used to remotely authenticate with
@@ -23,6 +31,9 @@ type APIKey struct {
 	ID     uint64 `gorm:"primary_key"`
 	Prefix string `gorm:"uniqueIndex"`
 	Hash   []byte
+	// Scope is APIKeyScopeFull or APIKeyScopeReadOnly, empty for the keys
+	// created before the scopes, which have a full scope.
+	Scope string
 
 	CreatedAt  *time.Time
 	Expiration *time.Time
@@ -33,6 +44,18 @@ type APIKey struct {
 func (h *Headscale) CreateAPIKey(
 	expiration *time.Time,
 ) (string, *APIKey, error) {
+	return h.CreateAPIKeyWithScope(expiration, APIKeyScopeFull)
+}
+
+// CreateAPIKeyWithScope creates a new ApiKey limited to scope.
+func (h *Headscale) CreateAPIKeyWithScope(
+	expiration *time.Time,
+	scope string,
+) (string, *APIKey, error) {
+	if scope != APIKeyScopeFull && scope != APIKeyScopeReadOnly {
+		return "", nil, errInvalidAPIKeyScope
+	}
+
 	prefix, err := GenerateRandomStringURLSafe(apiPrefixLength)
 	if err != nil {
 		return "", nil, err
@@ -54,6 +77,7 @@ func (h *Headscale) CreateAPIKey(
 	key := APIKey{
 		Prefix:     prefix,
 		Hash:       hash,
+		Scope:      scope,
 		Expiration: expiration,
 	}
 
@@ -114,31 +138,98 @@ func (h *Headscale) ExpireAPIKey(key *APIKey) error {
 }
 
 func (h *Headscale) ValidateAPIKey(keyStr string) (bool, error) {
+	key, err := h.validateAPIKey(keyStr)
+
+	return key != nil, err
+}
+
+// validateAPIKey returns the ApiKey of keyStr, nil if it is expired.
+func (h *Headscale) validateAPIKey(keyStr string) (*APIKey, error) {
 	prefix, hash, found := strings.Cut(keyStr, ".")
 	if !found {
-		return false, errAPIKeyFailedToParse
+		return nil, errAPIKeyFailedToParse
 	}
 
 	key, err := h.GetAPIKey(prefix)
 	if err != nil {
-		return false, fmt.Errorf("failed to validate api key: %w", err)
+		return nil, fmt.Errorf("failed to validate api key: %w", err)
 	}
 
 	if key.Expiration.Before(time.Now()) {
-		return false, nil
+		return nil, nil
 	}
 
 	if err := bcrypt.CompareHashAndPassword(key.Hash, []byte(hash)); err != nil {
-		return false, err
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// GetScope returns the scope of the key.
+func (key *APIKey) GetScope() string {
+	if key.Scope == "" {
+		return APIKeyScopeFull
+	}
+
+	return key.Scope
+}
+
+// readOnlyMethods are the gRPC methods, by their full name, which read the
+// state without changing it, allowed to the read-only keys. A new RPC is
+// denied to them until it is listed here. ListPreAuthKeys is left out as
+// it returns the secret of the keys.
+var readOnlyMethods = map[string]bool{
+	"/headscale.v1.HeadscaleService/GetNamespace":             true,
+	"/headscale.v1.HeadscaleService/ListNamespaces":           true,
+	"/headscale.v1.HeadscaleService/GetNamespaceImpact":       true,
+	"/headscale.v1.HeadscaleService/GetMachine":               true,
+	"/headscale.v1.HeadscaleService/DumpMachineNetmap":        true,
+	"/headscale.v1.HeadscaleService/ListPendingRegistrations": true,
+	"/headscale.v1.HeadscaleService/ListMachines":             true,
+	"/headscale.v1.HeadscaleService/ListMachineConnections":   true,
+	"/headscale.v1.HeadscaleService/GetMachineRoute":          true,
+	"/headscale.v1.HeadscaleService/ListExitNodes":            true,
+	"/headscale.v1.HeadscaleService/ListApiKeys":              true,
+	"/headscale.v1.HeadscaleService/ListACLReferences":        true,
+	"/headscale.v1.HeadscaleService/CheckACL":                 true,
+	"/headscale.v1.HeadscaleService/ListACLGroups":            true,
+	"/headscale.v1.HeadscaleService/GetACLGroup":              true,
+	"/headscale.v1.HeadscaleService/ListDNSRecords":           true,
+	"/headscale.v1.HeadscaleService/GetNodeStats":             true,
+	"/headscale.v1.HeadscaleService/ListNodeTemplates":        true,
+	"/headscale.v1.HeadscaleService/ListAutoTags":             true,
+	"/headscale.v1.HeadscaleService/GetVersion":               true,
+}
+
+// allowsMethod tells if the scope of the key allows to call the gRPC
+// method, given by its full name (/headscale.v1.HeadscaleService/ListMachines).
+func (key *APIKey) allowsMethod(fullMethod string) bool {
+	return key.GetScope() == APIKeyScopeFull || readOnlyMethods[fullMethod]
+}
+
+// readOnlyDeniedPaths are the GET routes of the API denied to the read-only
+// keys, the ones of the RPCs missing from readOnlyMethods.
+var readOnlyDeniedPaths = map[string]bool{
+	"/api/v1/preauthkey": true,
+}
+
+// allowsHTTPRequest tells if the scope of the key allows an HTTP request
+// to the API, which maps the RPCs reading the state to GET.
+func (key *APIKey) allowsHTTPRequest(method string, path string) bool {
+	if key.GetScope() == APIKeyScopeFull {
+		return true
 	}
 
-	return true, nil
+	return (method == http.MethodGet || method == http.MethodHead) &&
+		!readOnlyDeniedPaths[path]
 }
 
 func (key *APIKey) toProto() *v1.ApiKey {
 	protoKey := v1.ApiKey{
 		Id:     key.ID,
 		Prefix: key.Prefix,
+		Scope:  key.GetScope(),
 	}
 
 	if key.Expiration != nil {
@@ -1,8 +1,18 @@
 package headscale
 
 import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"time"
 
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 	"gopkg.in/check.v1"
 )
 
@@ -87,3 +97,130 @@ func (*Suite) TestExpireAPIKey(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(notValid, check.Equals, false)
 }
+
+func (*Suite) TestAPIKeyScope(c *check.C) {
+	nowPlus2 := time.Now().Add(2 * time.Hour)
+
+	_, _, err := app.CreateAPIKeyWithScope(&nowPlus2, "admin")
+	c.Assert(err, check.Equals, errInvalidAPIKeyScope)
+
+	fullKeyStr, fullKey, err := app.CreateAPIKey(&nowPlus2)
+	c.Assert(err, check.IsNil)
+	c.Assert(fullKey.GetScope(), check.Equals, APIKeyScopeFull)
+
+	readOnlyKeyStr, readOnlyKey, err := app.CreateAPIKeyWithScope(&nowPlus2, APIKeyScopeReadOnly)
+	c.Assert(err, check.IsNil)
+	c.Assert(readOnlyKey.toProto().GetScope(), check.Equals, APIKeyScopeReadOnly)
+
+	call := func(keyStr string, method string) codes.Code {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{}})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", AuthPrefix+keyStr))
+
+		_, err := app.grpcAuthenticationInterceptor(
+			ctx,
+			nil,
+			&grpc.UnaryServerInfo{FullMethod: "/headscale.v1.HeadscaleService/" + method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+		)
+
+		return status.Code(err)
+	}
+
+	for _, method := range []string{"ListMachines", "GetMachine", "CheckACL"} {
+		c.Assert(call(fullKeyStr, method), check.Equals, codes.OK)
+		c.Assert(call(readOnlyKeyStr, method), check.Equals, codes.OK)
+	}
+	for _, method := range []string{
+		"RegisterMachine",
+		"DeleteMachine",
+		"MoveMachine",
+		"ExpireMachine",
+		"ListPreAuthKeys",
+	} {
+		c.Assert(call(fullKeyStr, method), check.Equals, codes.OK)
+		c.Assert(call(readOnlyKeyStr, method), check.Equals, codes.PermissionDenied)
+	}
+
+	handler := app.httpAuthenticationMiddleware(http.HandlerFunc(
+		func(writer http.ResponseWriter, req *http.Request) {},
+	))
+	for _, test := range []struct {
+		method   string
+		path     string
+		expected int
+	}{
+		{http.MethodGet, "/api/v1/machine", http.StatusOK},
+		{http.MethodPost, "/api/v1/machine", http.StatusForbidden},
+		{http.MethodDelete, "/api/v1/machine", http.StatusForbidden},
+		{http.MethodGet, "/api/v1/preauthkey", http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		req.Header.Set("Authorization", AuthPrefix+readOnlyKeyStr)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		c.Assert(recorder.Code, check.Equals, test.expected)
+	}
+}
+
+func (*Suite) TestReadOnlyMethods(c *check.C) {
+	// The methods changing the state or returning secrets, denied to the
+	// read-only keys.
+	denied := map[string]bool{
+		"CreateNamespace":        true,
+		"RenameNamespace":        true,
+		"SetNamespaceDNSDomain":  true,
+		"SetNamespaceIdleExpiry": true,
+		"SetNamespaceMTU":        true,
+		"SetNamespaceLocked":     true,
+		"DeleteNamespace":        true,
+		"CreatePreAuthKey":       true,
+		"ExpirePreAuthKey":       true,
+		"ListPreAuthKeys":        true,
+		"DebugCreateMachine":     true,
+		"SetTags":                true,
+		"RegisterMachine":        true,
+		"DeleteMachine":          true,
+		"ExpireMachine":          true,
+		"SetMachineExpiry":       true,
+		"RefreshMachineKey":      true,
+		"BackfillMachineIPs":     true,
+		"ImportMachine":          true,
+		"QuarantineMachine":      true,
+		"UnquarantineMachine":    true,
+		"ApproveRegistration":    true,
+		"ApplyMachine":           true,
+		"UnmanageMachine":        true,
+		"RestoreMachine":         true,
+		"SetDERPRegion":          true,
+		"SetExitNodeOnly":        true,
+		"TouchMachine":           true,
+		"RenameMachine":          true,
+		"MoveMachine":            true,
+		"EnableMachineRoutes":    true,
+		"CreateApiKey":           true,
+		"ExpireApiKey":           true,
+		"CreateNodeTemplate":     true,
+		"DeleteNodeTemplate":     true,
+		"SetAutoTag":             true,
+		"DeleteAutoTag":          true,
+		"ApplyAutoTags":          true,
+	}
+
+	serviceMethods := map[string]bool{}
+	for _, method := range v1.HeadscaleService_ServiceDesc.Methods {
+		fullMethod := "/" + v1.HeadscaleService_ServiceDesc.ServiceName + "/" + method.MethodName
+		serviceMethods[fullMethod] = true
+
+		c.Assert(
+			readOnlyMethods[fullMethod] != denied[method.MethodName],
+			check.Equals,
+			true,
+			check.Commentf("%s must be either read-only or denied", method.MethodName),
+		)
+	}
+	for fullMethod := range readOnlyMethods {
+		c.Assert(serviceMethods[fullMethod], check.Equals, true, check.Commentf("%s is not a method", fullMethod))
+	}
+}
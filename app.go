@@ -65,6 +65,10 @@ const (
 	registerCacheExpiration = time.Minute * 15
 	registerCacheCleanup    = time.Minute * 20
 
+	deletedMachinesPurgeInterval  = time.Hour
+	idleMachinesSweepInterval     = 5 * time.Minute
+	connectionEventsPurgeInterval = time.Hour
+
 	DisabledClientAuth = "disabled"
 	RelaxedClientAuth  = "relaxed"
 	EnforcedClientAuth = "enforced"
@@ -82,11 +86,17 @@ type Headscale struct {
 	DERPMap    *tailcfg.DERPMap
 	DERPServer *DERPServer
 
+	// Version is the version of the server, reported by the GetVersion
+	// RPC.
+	Version string
+
 	aclPolicy *ACLPolicy
 	aclRules  []tailcfg.FilterRule
 
 	lastStateChange *xsync.MapOf[time.Time]
 
+	flaps flapTracker
+
 	oidcProvider *oidc.Provider
 	oauth2Config *oauth2.Config
 
@@ -227,8 +237,7 @@ func (h *Headscale) expireEphemeralNodesWorker() {
 
 		expiredFound := false
 		for _, machine := range machines {
-			if machine.AuthKey != nil && machine.LastSeen != nil &&
-				machine.AuthKey.Ephemeral &&
+			if machine.LastSeen != nil && machine.isEphemeral() &&
 				time.Now().
 					After(machine.LastSeen.Add(h.cfg.EphemeralNodeInactivityTimeout)) {
 				expiredFound = true
@@ -309,7 +318,7 @@ func (h *Headscale) grpcAuthenticationInterceptor(ctx context.Context,
 		)
 	}
 
-	valid, err := h.ValidateAPIKey(strings.TrimPrefix(token, AuthPrefix))
+	apiKey, err := h.validateAPIKey(strings.TrimPrefix(token, AuthPrefix))
 	if err != nil {
 		log.Error().
 			Caller().
@@ -320,7 +329,7 @@ func (h *Headscale) grpcAuthenticationInterceptor(ctx context.Context,
 		return ctx, status.Error(codes.Internal, "failed to validate token")
 	}
 
-	if !valid {
+	if apiKey == nil {
 		log.Info().
 			Str("client_address", client.Addr.String()).
 			Msg("invalid token")
@@ -328,6 +337,21 @@ func (h *Headscale) grpcAuthenticationInterceptor(ctx context.Context,
 		return ctx, status.Error(codes.Unauthenticated, "invalid token")
 	}
 
+	if !apiKey.allowsMethod(info.FullMethod) {
+		log.Info().
+			Str("client_address", client.Addr.String()).
+			Str("method", info.FullMethod).
+			Str("scope", apiKey.GetScope()).
+			Msg("method not allowed by the scope of the token")
+
+		return ctx, status.Errorf(
+			codes.PermissionDenied,
+			"%s is not allowed with a %s API key",
+			info.FullMethod,
+			apiKey.GetScope(),
+		)
+	}
+
 	return handler(ctx, req)
 }
 
@@ -360,7 +384,7 @@ func (h *Headscale) httpAuthenticationMiddleware(next http.Handler) http.Handler
 			return
 		}
 
-		valid, err := h.ValidateAPIKey(strings.TrimPrefix(authHeader, AuthPrefix))
+		apiKey, err := h.validateAPIKey(strings.TrimPrefix(authHeader, AuthPrefix))
 		if err != nil {
 			log.Error().
 				Caller().
@@ -380,7 +404,7 @@ func (h *Headscale) httpAuthenticationMiddleware(next http.Handler) http.Handler
 			return
 		}
 
-		if !valid {
+		if apiKey == nil {
 			log.Info().
 				Str("client_address", req.RemoteAddr).
 				Msg("invalid token")
@@ -397,6 +421,26 @@ func (h *Headscale) httpAuthenticationMiddleware(next http.Handler) http.Handler
 			return
 		}
 
+		if !apiKey.allowsHTTPRequest(req.Method, req.URL.Path) {
+			log.Info().
+				Str("client_address", req.RemoteAddr).
+				Str("method", req.Method).
+				Str("path", req.URL.Path).
+				Str("scope", apiKey.GetScope()).
+				Msg("method not allowed by the scope of the token")
+
+			writer.WriteHeader(http.StatusForbidden)
+			_, err := writer.Write([]byte("Forbidden"))
+			if err != nil {
+				log.Error().
+					Caller().
+					Err(err).
+					Msg("Failed to write response")
+			}
+
+			return
+		}
+
 		next.ServeHTTP(writer, req)
 	})
 }
@@ -469,6 +513,14 @@ func (h *Headscale) Serve() error {
 
 	go h.expireEphemeralNodes(updateInterval)
 
+	go h.snapshotNodeCounts(nodeCountSnapshotInterval)
+
+	go h.purgeDeletedMachines(deletedMachinesPurgeInterval)
+
+	go h.expireIdleMachines(idleMachinesSweepInterval)
+
+	go h.purgeConnectionEvents(connectionEventsPurgeInterval)
+
 	if zl.GlobalLevel() == zl.TraceLevel {
 		zerolog.RespLog = true
 	} else {
@@ -832,6 +884,33 @@ func (h *Headscale) setLastStateChangeToNow(namespaces ...string) {
 	}
 }
 
+// netmapVersion returns the time of the last state change of any
+// namespace, used as the version of the maps built from this state. It is
+// zero until the first change.
+func (h *Headscale) netmapVersion() time.Time {
+	var version time.Time
+	if h.lastStateChange == nil {
+		return version
+	}
+
+	h.lastStateChange.Range(func(key string, value time.Time) bool {
+		if value.After(version) {
+			version = value
+		}
+
+		return true
+	})
+
+	return version
+}
+
+// machineNetmapUpToDate tells if the last map sent to the machine includes
+// every state change.
+func (h *Headscale) machineNetmapUpToDate(machine *Machine) bool {
+	return machine.NetmapVersion != nil &&
+		!machine.NetmapVersion.Before(h.netmapVersion())
+}
+
 func (h *Headscale) getLastStateChange(namespaces ...string) time.Time {
 	times := []time.Time{}
 
@@ -1,12 +1,22 @@
 package headscale
 
 import (
+	"context"
+	"encoding/json"
 	"io/ioutil"
 	"os"
+	"sort"
+	"strconv"
 	"testing"
+	"time"
 
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/check.v1"
 	"inet.af/netaddr"
+	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
 )
 
 func Test(t *testing.T) {
@@ -77,3 +87,281 @@ func (s *Suite) TestAuthModes(c *check.C) {
 		c.Assert(isValid, check.Equals, true)
 	}
 }
+
+func (s *Suite) TestGetVersion(c *check.C) {
+	app.Version = "v0.17.0"
+	defer func() { app.Version = "" }()
+
+	response, err := newHeadscaleV1APIServer(&app).GetVersion(
+		context.Background(),
+		&v1.GetVersionRequest{},
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(response.GetVersion(), check.Equals, "v0.17.0")
+	c.Assert(sort.StringsAreSorted(response.GetCapabilities()), check.Equals, true)
+	c.Assert(contains(response.GetCapabilities(), "GetVersion"), check.Equals, true)
+	c.Assert(contains(response.GetCapabilities(), "ListMachines"), check.Equals, true)
+}
+
+func (s *Suite) TestGetMachineNotFound(c *check.C) {
+	_, err := newHeadscaleV1APIServer(&app).GetMachine(
+		context.Background(),
+		&v1.GetMachineRequest{MachineId: 42},
+	)
+	c.Assert(status.Code(err), check.Equals, codes.NotFound)
+	c.Assert(status.Convert(err).Message(), check.Equals, "machine not found")
+
+	// The RPCs changing a machine are NotFound for an unknown machine too.
+	api := newHeadscaleV1APIServer(&app)
+	ctx := context.Background()
+	calls := map[string]func() error{
+		"SetTags": func() error {
+			_, err := api.SetTags(ctx, &v1.SetTagsRequest{MachineId: 42})
+
+			return err
+		},
+		"DeleteMachine": func() error {
+			_, err := api.DeleteMachine(ctx, &v1.DeleteMachineRequest{MachineId: 42})
+
+			return err
+		},
+		"ExpireMachine": func() error {
+			_, err := api.ExpireMachine(ctx, &v1.ExpireMachineRequest{MachineId: 42})
+
+			return err
+		},
+		"QuarantineMachine": func() error {
+			_, err := api.QuarantineMachine(ctx, &v1.QuarantineMachineRequest{MachineId: 42})
+
+			return err
+		},
+		"UnquarantineMachine": func() error {
+			_, err := api.UnquarantineMachine(ctx, &v1.UnquarantineMachineRequest{MachineId: 42})
+
+			return err
+		},
+		"UnmanageMachine": func() error {
+			_, err := api.UnmanageMachine(ctx, &v1.UnmanageMachineRequest{MachineId: 42})
+
+			return err
+		},
+		"SetDERPRegion": func() error {
+			_, err := api.SetDERPRegion(ctx, &v1.SetDERPRegionRequest{MachineId: 42})
+
+			return err
+		},
+		"SetExitNodeOnly": func() error {
+			_, err := api.SetExitNodeOnly(ctx, &v1.SetExitNodeOnlyRequest{MachineId: 42})
+
+			return err
+		},
+		"TouchMachine": func() error {
+			_, err := api.TouchMachine(ctx, &v1.TouchMachineRequest{MachineId: 42})
+
+			return err
+		},
+		"MoveMachine": func() error {
+			_, err := api.MoveMachine(ctx, &v1.MoveMachineRequest{MachineId: 42})
+
+			return err
+		},
+		"GetMachineRoute": func() error {
+			_, err := api.GetMachineRoute(ctx, &v1.GetMachineRouteRequest{MachineId: 42})
+
+			return err
+		},
+		"EnableMachineRoutes": func() error {
+			_, err := api.EnableMachineRoutes(ctx, &v1.EnableMachineRoutesRequest{MachineId: 42})
+
+			return err
+		},
+	}
+	for method, call := range calls {
+		c.Assert(status.Code(call()), check.Equals, codes.NotFound, check.Commentf(method))
+	}
+}
+
+func (s *Suite) TestDumpMachineNetmap(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+	now := time.Now()
+	for index := 1; index <= 2; index++ {
+		machine := Machine{
+			ID:          uint64(index),
+			MachineKey:  MachinePublicKeyStripPrefix(key.NewMachine().Public()),
+			NodeKey:     NodePublicKeyStripPrefix(key.NewNode().Public()),
+			Hostname:    "testmachine" + strconv.Itoa(index),
+			GivenName:   "testmachine" + strconv.Itoa(index),
+			NamespaceID: namespace.ID,
+			IPAddresses: MachineAddresses{
+				netaddr.MustParseIP("10.27.0." + strconv.Itoa(index)),
+			},
+			LastSeen: &now,
+			Expiry:   &time.Time{},
+		}
+		app.db.Save(&machine)
+	}
+
+	api := newHeadscaleV1APIServer(&app)
+	response, err := api.DumpMachineNetmap(
+		context.Background(),
+		&v1.DumpMachineNetmapRequest{MachineId: 1},
+	)
+	c.Assert(err, check.IsNil)
+
+	var node tailcfg.Node
+	c.Assert(json.Unmarshal([]byte(response.GetNode()), &node), check.IsNil)
+	c.Assert(node.ID, check.Equals, tailcfg.NodeID(1))
+
+	var peers []tailcfg.Node
+	c.Assert(json.Unmarshal([]byte(response.GetPeers()), &peers), check.IsNil)
+	c.Assert(peers, check.HasLen, 1)
+	c.Assert(peers[0].ID, check.Equals, tailcfg.NodeID(2))
+
+	c.Assert(response.GetPacketFilter(), check.Not(check.Equals), "")
+	c.Assert(response.GetDnsConfig(), check.Not(check.Equals), "")
+
+	_, err = api.DumpMachineNetmap(
+		context.Background(),
+		&v1.DumpMachineNetmapRequest{MachineId: 42},
+	)
+	c.Assert(status.Code(err), check.Equals, codes.NotFound)
+}
+
+func (s *Suite) TestListMachinesPages(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+	for index := 1; index <= 5; index++ {
+		machine := Machine{
+			ID:          uint64(index),
+			MachineKey:  "foo" + strconv.Itoa(index),
+			Hostname:    "testmachine" + strconv.Itoa(index),
+			NamespaceID: namespace.ID,
+		}
+		app.db.Save(&machine)
+	}
+
+	api := newHeadscaleV1APIServer(&app)
+	ids := []uint64{}
+	pages := 0
+	request := &v1.ListMachinesRequest{PageSize: 2}
+	for {
+		response, err := api.ListMachines(context.Background(), request)
+		c.Assert(err, check.IsNil)
+		c.Assert(len(response.GetMachines()) <= 2, check.Equals, true)
+		for _, machine := range response.GetMachines() {
+			ids = append(ids, machine.GetId())
+		}
+		pages++
+		if response.GetNextPageToken() == "" {
+			break
+		}
+		request.PageToken = response.GetNextPageToken()
+	}
+	c.Assert(ids, check.DeepEquals, []uint64{1, 2, 3, 4, 5})
+	c.Assert(pages, check.Equals, 3)
+
+	response, err := api.ListMachines(
+		context.Background(),
+		&v1.ListMachinesRequest{Namespace: "test", PageSize: 5},
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(response.GetMachines(), check.HasLen, 5)
+	c.Assert(response.GetNextPageToken(), check.Equals, "5")
+
+	_, err = api.ListMachines(
+		context.Background(),
+		&v1.ListMachinesRequest{PageSize: 2, PageToken: "foo"},
+	)
+	c.Assert(status.Code(err), check.Equals, codes.InvalidArgument)
+}
+
+func (s *Suite) TestListMachinesPagesWithLabelSelector(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+	for index := 1; index <= 7; index++ {
+		machine := Machine{
+			ID:          uint64(index),
+			MachineKey:  "foo" + strconv.Itoa(index),
+			Hostname:    "testmachine" + strconv.Itoa(index),
+			NamespaceID: namespace.ID,
+			Labels:      StringMap{"env": "prod"},
+		}
+		if index%3 != 0 {
+			machine.Labels = StringMap{"env": "staging"}
+		}
+		app.db.Save(&machine)
+	}
+
+	// The machines not matching the selector do not shorten the pages.
+	api := newHeadscaleV1APIServer(&app)
+	pages := [][]uint64{}
+	request := &v1.ListMachinesRequest{LabelSelector: "env=staging", PageSize: 2}
+	for {
+		response, err := api.ListMachines(context.Background(), request)
+		c.Assert(err, check.IsNil)
+		ids := []uint64{}
+		for _, machine := range response.GetMachines() {
+			ids = append(ids, machine.GetId())
+		}
+		pages = append(pages, ids)
+		if response.GetNextPageToken() == "" {
+			break
+		}
+		request.PageToken = response.GetNextPageToken()
+	}
+	c.Assert(pages, check.DeepEquals, [][]uint64{{1, 2}, {4, 5}, {7}})
+
+	response, err := api.ListMachines(
+		context.Background(),
+		&v1.ListMachinesRequest{LabelSelector: "env=prod"},
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(response.GetMachines(), check.HasLen, 2)
+	c.Assert(response.GetNextPageToken(), check.Equals, "")
+}
+
+func (s *Suite) TestListDeletedMachinesPagesInNamespace(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+	other, err := app.CreateNamespace("other")
+	c.Assert(err, check.IsNil)
+	for index := 1; index <= 6; index++ {
+		machine := Machine{
+			ID:          uint64(index),
+			MachineKey:  "foo" + strconv.Itoa(index),
+			Hostname:    "testmachine" + strconv.Itoa(index),
+			NamespaceID: namespace.ID,
+		}
+		if index%2 == 0 {
+			machine.NamespaceID = other.ID
+		}
+		app.db.Save(&machine)
+		c.Assert(app.DeleteMachine(&machine), check.IsNil)
+	}
+
+	// The machines of the other namespace do not shorten the pages.
+	api := newHeadscaleV1APIServer(&app)
+	pages := [][]uint64{}
+	request := &v1.ListMachinesRequest{Namespace: "test", Deleted: true, PageSize: 2}
+	for {
+		response, err := api.ListMachines(context.Background(), request)
+		c.Assert(err, check.IsNil)
+		ids := []uint64{}
+		for _, machine := range response.GetMachines() {
+			ids = append(ids, machine.GetId())
+		}
+		pages = append(pages, ids)
+		if response.GetNextPageToken() == "" {
+			break
+		}
+		request.PageToken = response.GetNextPageToken()
+	}
+	c.Assert(pages, check.DeepEquals, [][]uint64{{1, 3}, {5}})
+
+	_, err = api.ListMachines(
+		context.Background(),
+		&v1.ListMachinesRequest{Namespace: "unknown", Deleted: true},
+	)
+	c.Assert(err, check.NotNil)
+}
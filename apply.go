@@ -0,0 +1,354 @@
+package headscale
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"gorm.io/gorm"
+	"inet.af/netaddr"
+)
+
+const (
+	errInvalidMachineSpec = Error("invalid machine spec")
+
+	MachineSpecCreated   = "created"
+	MachineSpecUpdated   = "updated"
+	MachineSpecUnchanged = "unchanged"
+)
+
+// MachineSpec is the declarative description of a machine, used to
+// register it or to update it until it matches. The machines it is applied
+// to are marked as managed. Every field but Name is
+// reconciled: empty tags, routes or labels remove the current ones and a
+// nil Expiry means that the machine never expires.
+type MachineSpec struct {
+	Namespace string
+	// Key is the machine key given to the machine when it asked to be
+	// registered, with or without the mkey: prefix.
+	Key    string
+	Name   string
+	Tags   []string
+	Routes []string
+	Labels map[string]string
+	Expiry *time.Time
+}
+
+func (spec MachineSpec) validate() error {
+	if spec.Namespace == "" {
+		return fmt.Errorf("namespace is required: %w", errInvalidMachineSpec)
+	}
+	if spec.Key == "" {
+		return fmt.Errorf("key is required: %w", errInvalidMachineSpec)
+	}
+	if spec.Name != "" {
+		if err := CheckForFQDNRules(spec.Name); err != nil {
+			return fmt.Errorf("name %q: %s: %w", spec.Name, err, errInvalidMachineSpec)
+		}
+	}
+	for _, tag := range spec.Tags {
+		if !strings.HasPrefix(tag, "tag:") {
+			return fmt.Errorf(
+				"tag %q must start with 'tag:': %w",
+				tag,
+				errInvalidMachineSpec,
+			)
+		}
+	}
+	for _, route := range spec.Routes {
+		if _, err := netaddr.ParseIPPrefix(route); err != nil {
+			return fmt.Errorf("route %q: %s: %w", route, err, errInvalidMachineSpec)
+		}
+	}
+	if err := validateLabels(spec.Labels); err != nil {
+		return fmt.Errorf("%s: %w", err, errInvalidMachineSpec)
+	}
+
+	return nil
+}
+
+// ApplyMachineSpec registers the machine described by spec if it does not
+// exist yet, and updates it to match the spec. It returns the machine, the
+// outcome (created, updated or unchanged) and a description of the changes.
+// With dryRun, nothing is changed and the machine is nil when it would be
+// created.
+func (h *Headscale) ApplyMachineSpec(
+	spec MachineSpec,
+	dryRun bool,
+) (*Machine, string, []string, error) {
+	if err := spec.validate(); err != nil {
+		return nil, "", nil, err
+	}
+
+	machineKeyStr := strings.TrimPrefix(spec.Key, machinePublicHexPrefix)
+
+	machine := Machine{}
+	err := h.db.Preload("Namespace").
+		First(&machine, "machine_key = ?", machineKeyStr).Error
+	if err == nil {
+		changes, err := machineSpecChanges(&machine, spec)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		if !machine.Managed {
+			changes = append(changes, "managed: false -> true")
+		}
+		if len(changes) == 0 {
+			return &machine, MachineSpecUnchanged, changes, nil
+		}
+		if dryRun {
+			return &machine, MachineSpecUpdated, changes, nil
+		}
+
+		if err := h.applyMachineSpec(&machine, spec); err != nil {
+			return nil, "", nil, err
+		}
+
+		return &machine, MachineSpecUpdated, changes, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", nil, err
+	}
+
+	item, ok := h.registrationCache.Get(machineKeyStr)
+	if !ok {
+		return nil, "", nil, errMachineNotFoundRegistrationCache
+	}
+	registration, ok := item.(Machine)
+	if !ok {
+		return nil, "", nil, errCouldNotConvertMachineInterface
+	}
+	if _, err := h.GetNamespace(spec.Namespace); err != nil {
+		return nil, "", nil, err
+	}
+
+	registration.Namespace = Namespace{Name: spec.Namespace}
+	changes, err := machineSpecChanges(&registration, spec)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	changes = append(
+		[]string{fmt.Sprintf("register in namespace %q", spec.Namespace)},
+		changes...,
+	)
+	if dryRun {
+		return nil, MachineSpecCreated, changes, nil
+	}
+
+	registered, err := h.RegisterMachineFromAuthCallback(
+		machineKeyStr,
+		spec.Namespace,
+		RegisterMethodCLI,
+		spec.Labels,
+		nil,
+	)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	// Reload the machine along with its namespace.
+	created, err := h.GetMachineByID(registered.ID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if err := h.applyMachineSpec(created, spec); err != nil {
+		return nil, "", nil, err
+	}
+
+	return created, MachineSpecCreated, changes, nil
+}
+
+// machineSpecChanges describes what must change for machine to match spec.
+func machineSpecChanges(machine *Machine, spec MachineSpec) ([]string, error) {
+	changes := []string{}
+
+	if machine.Namespace.Name != spec.Namespace {
+		changes = append(changes, fmt.Sprintf(
+			"namespace: %q -> %q",
+			machine.Namespace.Name,
+			spec.Namespace,
+		))
+	}
+
+	if spec.Name != "" && machine.GivenName != spec.Name {
+		changes = append(changes, fmt.Sprintf(
+			"name: %q -> %q",
+			machine.GivenName,
+			spec.Name,
+		))
+	}
+
+	if !equalStringSets(machine.ForcedTags, spec.Tags) {
+		changes = append(changes, fmt.Sprintf(
+			"tags: [%s] -> [%s]",
+			strings.Join(machine.ForcedTags, ", "),
+			strings.Join(spec.Tags, ", "),
+		))
+	}
+
+	for _, routeStr := range spec.Routes {
+		route, _ := netaddr.ParseIPPrefix(routeStr)
+		if !contains(machine.GetAdvertisedRoutes(), route) {
+			return nil, fmt.Errorf(
+				"route (%s) is not available on node %s: %w",
+				route,
+				machine.Hostname,
+				errMachineRouteIsNotAvailable,
+			)
+		}
+	}
+	enabledRoutes := ipPrefixToString(machine.GetEnabledRoutes())
+	if !equalStringSets(enabledRoutes, spec.Routes) {
+		changes = append(changes, fmt.Sprintf(
+			"routes: [%s] -> [%s]",
+			strings.Join(enabledRoutes, ", "),
+			strings.Join(spec.Routes, ", "),
+		))
+	}
+
+	if !equalLabels(machine.Labels, spec.Labels) {
+		changes = append(changes, fmt.Sprintf(
+			"labels: {%s} -> {%s}",
+			formatLabels(machine.Labels),
+			formatLabels(spec.Labels),
+		))
+	}
+
+	if !equalExpiry(machine.Expiry, spec.Expiry) {
+		changes = append(changes, fmt.Sprintf(
+			"expiry: %s -> %s",
+			formatExpiry(machine.Expiry),
+			formatExpiry(spec.Expiry),
+		))
+	}
+
+	return changes, nil
+}
+
+func (h *Headscale) applyMachineSpec(machine *Machine, spec MachineSpec) error {
+	if machine.Namespace.Name != spec.Namespace {
+		if err := h.SetMachineNamespace(machine, spec.Namespace); err != nil {
+			return err
+		}
+	}
+
+	if spec.Name != "" && machine.GivenName != spec.Name {
+		if err := h.RenameMachine(machine, spec.Name); err != nil {
+			return err
+		}
+	}
+
+	if !equalStringSets(machine.ForcedTags, spec.Tags) {
+		if err := h.SetTags(machine, spec.Tags); err != nil {
+			return err
+		}
+	}
+
+	if !equalStringSets(ipPrefixToString(machine.GetEnabledRoutes()), spec.Routes) {
+		if err := h.EnableRoutes(machine, spec.Routes...); err != nil {
+			return err
+		}
+	}
+
+	if !equalLabels(machine.Labels, spec.Labels) ||
+		!equalExpiry(machine.Expiry, spec.Expiry) ||
+		!machine.Managed {
+		machine.Labels = spec.Labels
+		machine.Expiry = spec.Expiry
+		machine.Managed = true
+
+		if err := h.db.Save(machine).Error; err != nil {
+			return fmt.Errorf("failed to update machine in the database: %w", err)
+		}
+	}
+
+	h.setLastStateChangeToNow(machine.Namespace.Name)
+
+	return nil
+}
+
+// UnmanageMachine releases a machine from nodes apply, until a spec is
+// applied to it again.
+func (h *Headscale) UnmanageMachine(machine *Machine) error {
+	machine.Managed = false
+
+	if err := h.db.Save(machine).Error; err != nil {
+		return fmt.Errorf("failed to unmanage machine in the database: %w", err)
+	}
+
+	return nil
+}
+
+func equalStringSets(current []string, wanted []string) bool {
+	if len(current) != len(wanted) {
+		return false
+	}
+
+	for _, value := range wanted {
+		if !contains(current, value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalLabels(current map[string]string, wanted map[string]string) bool {
+	if len(current) != len(wanted) {
+		return false
+	}
+
+	for key, value := range wanted {
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func equalExpiry(current *time.Time, wanted *time.Time) bool {
+	if current == nil || current.IsZero() {
+		return wanted == nil || wanted.IsZero()
+	}
+
+	return wanted != nil && current.Equal(*wanted)
+}
+
+func formatLabels(labels map[string]string) string {
+	formatted := make([]string, 0, len(labels))
+	for key, value := range labels {
+		formatted = append(formatted, key+"="+value)
+	}
+	sort.Strings(formatted)
+
+	return strings.Join(formatted, ", ")
+}
+
+func formatExpiry(expiry *time.Time) string {
+	if expiry == nil || expiry.IsZero() {
+		return "never"
+	}
+
+	return expiry.UTC().Format(time.RFC3339)
+}
+
+func machineSpecFromProto(spec *v1.MachineSpec) MachineSpec {
+	machineSpec := MachineSpec{
+		Namespace: spec.GetNamespace(),
+		Key:       spec.GetKey(),
+		Name:      spec.GetName(),
+		Tags:      spec.GetTags(),
+		Routes:    spec.GetRoutes(),
+		Labels:    spec.GetLabels(),
+	}
+	if spec.GetExpiry() != nil {
+		expiry := spec.GetExpiry().AsTime()
+		machineSpec.Expiry = &expiry
+	}
+
+	return machineSpec
+}
@@ -0,0 +1,102 @@
+package headscale
+
+import (
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"gopkg.in/check.v1"
+	"inet.af/netaddr"
+)
+
+func (s *Suite) TestApplyMachineSpec(c *check.C) {
+	namespace, err := app.CreateNamespace("servers")
+	c.Assert(err, check.IsNil)
+	_, err = app.CreateNamespace("other")
+	c.Assert(err, check.IsNil)
+
+	app.registrationCache = cache.New(
+		registerCacheExpiration,
+		registerCacheCleanup,
+	)
+	app.registrationCache.Set(
+		"foo",
+		Machine{
+			MachineKey: "foo",
+			NodeKey:    "bar",
+			DiscoKey:   "faa",
+			Hostname:   "web",
+			GivenName:  "web",
+			HostInfo: HostInfo{
+				RoutableIPs: []netaddr.IPPrefix{netaddr.MustParseIPPrefix("10.0.0.0/24")},
+			},
+		},
+		registerCacheExpiration,
+	)
+
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	spec := MachineSpec{
+		Namespace: namespace.Name,
+		Key:       "mkey:foo",
+		Name:      "web-1",
+		Tags:      []string{"tag:web"},
+		Routes:    []string{"10.0.0.0/24"},
+		Labels:    map[string]string{"env": "production"},
+		Expiry:    &expiry,
+	}
+
+	_, _, _, err = app.ApplyMachineSpec(MachineSpec{Key: "foo"}, false)
+	c.Assert(err, check.ErrorMatches, ".*invalid machine spec")
+
+	machine, result, changes, err := app.ApplyMachineSpec(spec, true)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine, check.IsNil)
+	c.Assert(result, check.Equals, MachineSpecCreated)
+	c.Assert(changes, check.Not(check.HasLen), 0)
+	_, err = app.GetMachineByID(1)
+	c.Assert(err, check.NotNil)
+
+	machine, result, _, err = app.ApplyMachineSpec(spec, false)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, MachineSpecCreated)
+	c.Assert(machine.GivenName, check.Equals, "web-1")
+	c.Assert(machine.ForcedTags, check.DeepEquals, StringList{"tag:web"})
+	c.Assert(machine.IsRoutesEnabled("10.0.0.0/24"), check.Equals, true)
+	c.Assert(machine.Labels["env"], check.Equals, "production")
+	c.Assert(machine.Expiry.Equal(expiry), check.Equals, true)
+	c.Assert(machine.toProto().GetManaged(), check.Equals, true)
+
+	_, result, changes, err = app.ApplyMachineSpec(spec, false)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, MachineSpecUnchanged)
+	c.Assert(changes, check.HasLen, 0)
+
+	err = app.UnmanageMachine(machine)
+	c.Assert(err, check.IsNil)
+	_, result, changes, err = app.ApplyMachineSpec(spec, false)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, MachineSpecUpdated)
+	c.Assert(changes, check.DeepEquals, []string{"managed: false -> true"})
+	machine, err = app.GetMachineByID(machine.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.Managed, check.Equals, true)
+
+	spec.Namespace = "other"
+	spec.Tags = nil
+	spec.Expiry = nil
+	_, result, changes, err = app.ApplyMachineSpec(spec, true)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, MachineSpecUpdated)
+	c.Assert(changes, check.HasLen, 3)
+
+	machine, result, _, err = app.ApplyMachineSpec(spec, false)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.Equals, MachineSpecUpdated)
+	c.Assert(machine.Namespace.Name, check.Equals, "other")
+	c.Assert(machine.ForcedTags, check.HasLen, 0)
+	c.Assert(machine.Expiry, check.IsNil)
+
+	spec.Routes = []string{"192.168.0.0/24"}
+	_, _, _, err = app.ApplyMachineSpec(spec, true)
+	c.Assert(err, check.ErrorMatches, ".*is not available.*")
+
+}
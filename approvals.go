@@ -0,0 +1,136 @@
+package headscale
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	errPendingRegistrationNotFound = Error("pending registration not found")
+
+	pendingRegistrationCachePrefix = "pending:"
+)
+
+// PendingRegistration is a machine which authenticated with OIDC and waits
+// for an administrator to approve its registration.
+type PendingRegistration struct {
+	MachineKey string
+	Hostname   string
+	// Email is the email of the OIDC user who requested the registration.
+	Email       string
+	Namespace   string
+	RequestedAt time.Time
+}
+
+// requestRegistrationApproval keeps the OIDC identity of the user
+// registering a machine until an administrator approves it.
+func (h *Headscale) requestRegistrationApproval(
+	machineKeyStr string,
+	email string,
+	namespaceName string,
+) error {
+	machineInterface, ok := h.registrationCache.Get(machineKeyStr)
+	if !ok {
+		return errMachineNotFoundRegistrationCache
+	}
+	machine, ok := machineInterface.(Machine)
+	if !ok {
+		return errCouldNotConvertMachineInterface
+	}
+
+	h.registrationCache.Set(
+		pendingRegistrationCachePrefix+machineKeyStr,
+		PendingRegistration{
+			MachineKey:  machineKeyStr,
+			Hostname:    machine.Hostname,
+			Email:       email,
+			Namespace:   namespaceName,
+			RequestedAt: time.Now().UTC(),
+		},
+		registerCacheExpiration,
+	)
+
+	return nil
+}
+
+// ListPendingRegistrations returns the registrations waiting for an approval,
+// oldest first. They expire with the registration requests of the machines.
+func (h *Headscale) ListPendingRegistrations() []PendingRegistration {
+	pending := []PendingRegistration{}
+	for cacheKey, item := range h.registrationCache.Items() {
+		if !strings.HasPrefix(cacheKey, pendingRegistrationCachePrefix) {
+			continue
+		}
+
+		registration, ok := item.Object.(PendingRegistration)
+		if !ok {
+			continue
+		}
+
+		// The machine gave up waiting.
+		if _, ok := h.registrationCache.Get(registration.MachineKey); !ok {
+			continue
+		}
+
+		// The machine was registered in another way in the meantime.
+		var count int64
+		if err := h.db.Model(&Machine{}).
+			Where("machine_key = ?", registration.MachineKey).
+			Count(&count).Error; err != nil || count > 0 {
+			continue
+		}
+
+		pending = append(pending, registration)
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].RequestedAt.Before(pending[j].RequestedAt)
+	})
+
+	return pending
+}
+
+// ApproveRegistration registers a machine waiting for an approval in the
+// namespace of the OIDC user who requested it.
+func (h *Headscale) ApproveRegistration(machineKeyStr string) (*Machine, error) {
+	machineKeyStr = strings.TrimPrefix(machineKeyStr, machinePublicHexPrefix)
+
+	item, ok := h.registrationCache.Get(pendingRegistrationCachePrefix + machineKeyStr)
+	if !ok {
+		return nil, errPendingRegistrationNotFound
+	}
+	registration, ok := item.(PendingRegistration)
+	if !ok {
+		return nil, errPendingRegistrationNotFound
+	}
+
+	machine, err := h.RegisterMachineFromAuthCallback(
+		registration.MachineKey,
+		registration.Namespace,
+		RegisterMethodOIDC,
+		nil,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	h.registrationCache.Delete(pendingRegistrationCachePrefix + machineKeyStr)
+
+	// Reload the machine along with its namespace.
+	return h.GetMachineByID(machine.ID)
+}
+
+func (registration PendingRegistration) toProto() *v1.PendingRegistration {
+	return &v1.PendingRegistration{
+		MachineKey:  registration.MachineKey,
+		Hostname:    registration.Hostname,
+		Email:       registration.Email,
+		Namespace:   registration.Namespace,
+		RequestedAt: timestamppb.New(registration.RequestedAt),
+	}
+}
@@ -0,0 +1,50 @@
+package headscale
+
+import (
+	"github.com/patrickmn/go-cache"
+	"gopkg.in/check.v1"
+)
+
+func (s *Suite) TestApproveRegistration(c *check.C) {
+	namespace, err := app.CreateNamespace("alice")
+	c.Assert(err, check.IsNil)
+
+	app.registrationCache = cache.New(
+		registerCacheExpiration,
+		registerCacheCleanup,
+	)
+	app.registrationCache.Set(
+		"foo",
+		Machine{
+			MachineKey: "foo",
+			NodeKey:    "bar",
+			DiscoKey:   "faa",
+			Hostname:   "laptop",
+			GivenName:  "laptop",
+		},
+		registerCacheExpiration,
+	)
+
+	_, err = app.ApproveRegistration("foo")
+	c.Assert(err, check.Equals, errPendingRegistrationNotFound)
+
+	err = app.requestRegistrationApproval("unknown", "alice@example.com", namespace.Name)
+	c.Assert(err, check.Equals, errMachineNotFoundRegistrationCache)
+
+	err = app.requestRegistrationApproval("foo", "alice@example.com", namespace.Name)
+	c.Assert(err, check.IsNil)
+
+	pending := app.ListPendingRegistrations()
+	c.Assert(pending, check.HasLen, 1)
+	c.Assert(pending[0].MachineKey, check.Equals, "foo")
+	c.Assert(pending[0].Hostname, check.Equals, "laptop")
+	c.Assert(pending[0].Email, check.Equals, "alice@example.com")
+	c.Assert(pending[0].Namespace, check.Equals, namespace.Name)
+
+	machine, err := app.ApproveRegistration("mkey:foo")
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.Namespace.Name, check.Equals, namespace.Name)
+	c.Assert(machine.RegisterMethod, check.Equals, RegisterMethodOIDC)
+
+	c.Assert(app.ListPendingRegistrations(), check.HasLen, 0)
+}
@@ -0,0 +1,229 @@
+package headscale
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+const (
+	errInvalidAutoTag  = Error("invalid autotag")
+	errAutoTagNotFound = Error("autotag not found")
+
+	AutoTagSourceConfig = "config"
+	AutoTagSourceCLI    = "cli"
+)
+
+// AutoTagRule forces tags on the machines with a hostname matching
+// Pattern, when they are registered.
+type AutoTagRule struct {
+	Pattern string   `mapstructure:"pattern"`
+	Tags    []string `mapstructure:"tags"`
+
+	// Source tells if the rule comes from the configuration file or was
+	// set with the CLI.
+	Source string `mapstructure:"-"`
+}
+
+// AutoTag is an autotag rule set with the CLI, stored in the database.
+type AutoTag struct {
+	ID      uint64 `gorm:"primary_key"`
+	Pattern string `gorm:"unique"`
+	Tags    StringList
+
+	CreatedAt *time.Time
+}
+
+// AutoTagChange lists the tags an autotag rule adds to a machine.
+type AutoTagChange struct {
+	Machine   Machine
+	AddedTags []string
+}
+
+// GetAutoTagsConfig reads the autotag rules of the configuration file.
+func GetAutoTagsConfig() ([]AutoTagRule, error) {
+	rules := []AutoTagRule{}
+	if err := viper.UnmarshalKey("autotags", &rules); err != nil {
+		return nil, err
+	}
+	for index := range rules {
+		rules[index].Source = AutoTagSourceConfig
+	}
+
+	return rules, nil
+}
+
+func (rule AutoTagRule) validate() error {
+	if _, err := regexp.Compile(rule.Pattern); err != nil {
+		return fmt.Errorf("pattern %q: %s: %w", rule.Pattern, err, errInvalidAutoTag)
+	}
+	if len(rule.Tags) == 0 {
+		return fmt.Errorf("pattern %q has no tag: %w", rule.Pattern, errInvalidAutoTag)
+	}
+	for _, tag := range rule.Tags {
+		if !strings.HasPrefix(tag, "tag:") {
+			return fmt.Errorf("tag %q must start with 'tag:': %w", tag, errInvalidAutoTag)
+		}
+	}
+
+	return nil
+}
+
+// SetAutoTag saves the tags forced on the machines with a hostname
+// matching pattern, replacing the tags of an existing rule.
+func (h *Headscale) SetAutoTag(pattern string, tags []string) (*AutoTagRule, error) {
+	rule := AutoTagRule{Pattern: pattern, Tags: tags, Source: AutoTagSourceCLI}
+	if err := rule.validate(); err != nil {
+		return nil, err
+	}
+
+	autoTag := AutoTag{}
+	err := h.db.Where("pattern = ?", pattern).First(&autoTag).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	autoTag.Pattern = pattern
+	autoTag.Tags = tags
+	if err := h.db.Save(&autoTag).Error; err != nil {
+		return nil, fmt.Errorf("failed to save autotag to database: %w", err)
+	}
+
+	return &rule, nil
+}
+
+// DeleteAutoTag deletes an autotag rule set with the CLI.
+func (h *Headscale) DeleteAutoTag(pattern string) error {
+	result := h.db.Where("pattern = ?", pattern).Delete(&AutoTag{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errAutoTagNotFound
+	}
+
+	return nil
+}
+
+// ListAutoTags returns the autotag rules of the configuration file, then
+// the ones set with the CLI.
+func (h *Headscale) ListAutoTags() ([]AutoTagRule, error) {
+	rules := append([]AutoTagRule{}, h.cfg.AutoTags...)
+
+	autoTags := []AutoTag{}
+	if err := h.db.Order("pattern").Find(&autoTags).Error; err != nil {
+		return nil, err
+	}
+	for _, autoTag := range autoTags {
+		rules = append(rules, AutoTagRule{
+			Pattern: autoTag.Pattern,
+			Tags:    autoTag.Tags,
+			Source:  AutoTagSourceCLI,
+		})
+	}
+
+	return rules, nil
+}
+
+// autoTagsFor returns the tags of the autotag rules matching a hostname.
+func (h *Headscale) autoTagsFor(hostname string) ([]string, error) {
+	rules, err := h.ListAutoTags()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("pattern", rule.Pattern).
+				Msg("Skipping invalid autotag pattern")
+
+			continue
+		}
+		if !pattern.MatchString(hostname) {
+			continue
+		}
+		for _, tag := range rule.Tags {
+			if !contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	return tags, nil
+}
+
+// missingAutoTags returns the autotags of a machine it is not forced yet.
+func (h *Headscale) missingAutoTags(machine *Machine) ([]string, error) {
+	tags, err := h.autoTagsFor(machine.Hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := []string{}
+	for _, tag := range tags {
+		if !contains(machine.ForcedTags, tag) {
+			missing = append(missing, tag)
+		}
+	}
+
+	return missing, nil
+}
+
+// ApplyAutoTags adds the autotags to the existing machines, the
+// registrations get them automatically. With dryRun, the machines are
+// left unchanged.
+func (h *Headscale) ApplyAutoTags(dryRun bool) ([]AutoTagChange, error) {
+	machines, err := h.ListMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []AutoTagChange{}
+	for index := range machines {
+		machine := &machines[index]
+
+		missing, err := h.missingAutoTags(machine)
+		if err != nil {
+			return nil, err
+		}
+		if len(missing) == 0 {
+			continue
+		}
+
+		if !dryRun {
+			tags := append(append([]string{}, machine.ForcedTags...), missing...)
+			if err := h.SetTags(machine, tags); err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, AutoTagChange{Machine: *machine, AddedTags: missing})
+	}
+
+	return changes, nil
+}
+
+func (rule AutoTagRule) toProto() *v1.AutoTag {
+	return &v1.AutoTag{
+		Pattern: rule.Pattern,
+		Tags:    rule.Tags,
+		Source:  rule.Source,
+	}
+}
+
+func (change AutoTagChange) toProto() *v1.AutoTagChange {
+	return &v1.AutoTagChange{
+		Machine:   change.Machine.toProto(),
+		AddedTags: change.AddedTags,
+	}
+}
@@ -0,0 +1,92 @@
+package headscale
+
+import (
+	"errors"
+
+	"gopkg.in/check.v1"
+)
+
+func (s *Suite) TestSetAutoTag(c *check.C) {
+	_, err := app.SetAutoTag("^web-(", []string{"tag:web"})
+	c.Assert(errors.Is(err, errInvalidAutoTag), check.Equals, true)
+
+	_, err = app.SetAutoTag("^web-", []string{"web"})
+	c.Assert(errors.Is(err, errInvalidAutoTag), check.Equals, true)
+
+	_, err = app.SetAutoTag("^web-", []string{"tag:web"})
+	c.Assert(err, check.IsNil)
+
+	_, err = app.SetAutoTag("^web-", []string{"tag:web", "tag:prod"})
+	c.Assert(err, check.IsNil)
+
+	app.cfg.AutoTags = []AutoTagRule{
+		{Pattern: `^db-\d+$`, Tags: []string{"tag:db"}, Source: AutoTagSourceConfig},
+	}
+	defer func() { app.cfg.AutoTags = nil }()
+
+	rules, err := app.ListAutoTags()
+	c.Assert(err, check.IsNil)
+	c.Assert(rules, check.DeepEquals, []AutoTagRule{
+		{Pattern: `^db-\d+$`, Tags: []string{"tag:db"}, Source: AutoTagSourceConfig},
+		{Pattern: "^web-", Tags: []string{"tag:web", "tag:prod"}, Source: AutoTagSourceCLI},
+	})
+
+	err = app.DeleteAutoTag("^web-")
+	c.Assert(err, check.IsNil)
+
+	err = app.DeleteAutoTag("^web-")
+	c.Assert(err, check.Equals, errAutoTagNotFound)
+}
+
+func (s *Suite) TestApplyAutoTags(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	existing := Machine{
+		ID:             1,
+		MachineKey:     "foo",
+		NodeKey:        "bar",
+		DiscoKey:       "faa",
+		Hostname:       "web-1",
+		NamespaceID:    namespace.ID,
+		Namespace:      *namespace,
+		RegisterMethod: RegisterMethodCLI,
+	}
+	app.db.Save(&existing)
+
+	_, err = app.SetAutoTag(`^web-\d+$`, []string{"tag:web"})
+	c.Assert(err, check.IsNil)
+
+	registered, err := app.RegisterMachine(Machine{
+		MachineKey:     "foo2",
+		NodeKey:        "bar2",
+		DiscoKey:       "faa2",
+		Hostname:       "web-2",
+		NamespaceID:    namespace.ID,
+		RegisterMethod: RegisterMethodCLI,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(registered.ForcedTags, check.DeepEquals, StringList{"tag:web"})
+
+	changes, err := app.ApplyAutoTags(true)
+	c.Assert(err, check.IsNil)
+	c.Assert(changes, check.HasLen, 1)
+	c.Assert(changes[0].Machine.Hostname, check.Equals, "web-1")
+	c.Assert(changes[0].AddedTags, check.DeepEquals, []string{"tag:web"})
+
+	machine, err := app.GetMachineByID(existing.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.ForcedTags, check.HasLen, 0)
+
+	changes, err = app.ApplyAutoTags(false)
+	c.Assert(err, check.IsNil)
+	c.Assert(changes, check.HasLen, 1)
+
+	machine, err = app.GetMachineByID(existing.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.ForcedTags, check.DeepEquals, StringList{"tag:web"})
+
+	changes, err = app.ApplyAutoTags(false)
+	c.Assert(err, check.IsNil)
+	c.Assert(changes, check.HasLen, 0)
+}
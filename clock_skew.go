@@ -0,0 +1,57 @@
+package headscale
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"tailscale.com/tailcfg"
+)
+
+// clientClockSkew returns the offset between the clock of a client, as
+// reported in its register request, and the clock of the server. It is
+// nil when the client does not report its time, which it only does when
+// it signs its requests.
+func clientClockSkew(registerRequest tailcfg.RegisterRequest, now time.Time) *time.Duration {
+	if registerRequest.Timestamp == nil || registerRequest.Timestamp.IsZero() {
+		return nil
+	}
+
+	skew := registerRequest.Timestamp.Sub(now)
+
+	return &skew
+}
+
+// isClockSkewed tells if an offset is beyond the configured threshold.
+func (h *Headscale) isClockSkewed(skew *time.Duration) bool {
+	if skew == nil || h.cfg.ClockSkewThreshold <= 0 {
+		return false
+	}
+
+	return *skew > h.cfg.ClockSkewThreshold || *skew < -h.cfg.ClockSkewThreshold
+}
+
+// recordClockSkew saves the clock offset of a registered machine, and
+// warns when it is beyond the threshold.
+func (h *Headscale) recordClockSkew(machine *Machine, skew *time.Duration) {
+	if skew == nil {
+		return
+	}
+
+	machine.ClockSkew = skew
+	if err := h.db.Model(machine).Update("clock_skew", *skew).Error; err != nil {
+		log.Error().
+			Caller().
+			Err(err).
+			Str("machine", machine.Hostname).
+			Msg("Cannot save the clock skew of the machine")
+
+		return
+	}
+
+	if h.isClockSkewed(skew) {
+		log.Warn().
+			Str("machine", machine.Hostname).
+			Dur("clock_skew", *skew).
+			Msg("The clock of the machine is skewed, its expiry and last seen may look wrong")
+	}
+}
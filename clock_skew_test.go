@@ -0,0 +1,47 @@
+package headscale
+
+import (
+	"time"
+
+	"gopkg.in/check.v1"
+	"tailscale.com/tailcfg"
+)
+
+func (s *Suite) TestClockSkew(c *check.C) {
+	app.cfg.ClockSkewThreshold = time.Minute
+	now := time.Now().UTC()
+
+	c.Assert(clientClockSkew(tailcfg.RegisterRequest{}, now), check.IsNil)
+
+	clientTime := now.Add(-5 * time.Minute)
+	skew := clientClockSkew(tailcfg.RegisterRequest{Timestamp: &clientTime}, now)
+	c.Assert(skew, check.NotNil)
+	c.Assert(*skew, check.Equals, -5*time.Minute)
+	c.Assert(app.isClockSkewed(skew), check.Equals, true)
+
+	small := 30 * time.Second
+	c.Assert(app.isClockSkewed(&small), check.Equals, false)
+	c.Assert(app.isClockSkewed(nil), check.Equals, false)
+
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	machine := &Machine{
+		ID:             0,
+		MachineKey:     "foo",
+		NodeKey:        "bar",
+		DiscoKey:       "faa",
+		Hostname:       "testmachine",
+		NamespaceID:    namespace.ID,
+		RegisterMethod: RegisterMethodAuthKey,
+	}
+	app.db.Save(machine)
+
+	app.recordClockSkew(machine, skew)
+
+	machineFromDB, err := app.GetMachine("test", "testmachine")
+	c.Assert(err, check.IsNil)
+	c.Assert(machineFromDB.ClockSkew, check.NotNil)
+	c.Assert(*machineFromDB.ClockSkew, check.Equals, -5*time.Minute)
+	c.Assert(machineFromDB.toProto().GetClockSkew().AsDuration(), check.Equals, -5*time.Minute)
+}
@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	rootCmd.AddCommand(aclCmd)
+
+	aclReferencesCmd.Flags().StringP("tag", "t", "", "Tag to look for (e.g. tag:web)")
+	aclReferencesCmd.Flags().Uint64("node", 0, "Node identifier (ID) to look for")
+	aclCmd.AddCommand(aclReferencesCmd)
+
+	aclCheckCmd.Flags().Uint64("src", 0, "Source node identifier (ID)")
+	aclCheckCmd.Flags().Uint64("dst", 0, "Destination node identifier (ID)")
+	aclCheckCmd.Flags().Uint16("port", 0, "Destination port, any port if not set")
+	aclCheckCmd.Flags().Bool("trace", false, "Show how each rule of the policy was evaluated")
+	aclCmd.AddCommand(aclCheckCmd)
+}
+
+var aclCmd = &cobra.Command{
+	Use:     "acl",
+	Short:   "Inspect the ACL policy of Headscale",
+	Aliases: []string{"acls", "policy"},
+}
+
+var aclReferencesCmd = &cobra.Command{
+	Use:   "references",
+	Short: "List the ACL policy entries referencing a tag or a node",
+	Long: `
+List every entry of the loaded ACL policy referencing a tag, or
+applying to a node through any alias (namespace, group, tag, host, IP or CIDR).
+Useful to know the impact of changing a tag before doing it.`,
+	Aliases: []string{"refs"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		tag, _ := cmd.Flags().GetString("tag")
+		identifier, _ := cmd.Flags().GetUint64("node")
+		if (tag == "") == (identifier == 0) {
+			ErrorOutput(
+				errMissingParameter,
+				"Please specify either --tag or --node",
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.ListACLReferencesRequest{
+			Tag:       tag,
+			MachineId: identifier,
+		}
+
+		response, err := client.ListACLReferences(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot list ACL references: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.References, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{
+			{"Section", "Rule", "Field", "Entry", "Line"},
+		}
+		for _, reference := range response.GetReferences() {
+			rule := "-"
+			if reference.GetSection() == "acls" {
+				rule = strconv.FormatUint(uint64(reference.GetRule()), headscale.Base10)
+			}
+
+			line := "-"
+			if reference.GetLine() != 0 {
+				line = strconv.FormatUint(uint64(reference.GetLine()), headscale.Base10)
+			}
+
+			tableData = append(tableData, []string{
+				reference.GetSection(),
+				rule,
+				reference.GetField(),
+				reference.GetEntry(),
+				line,
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+var aclCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check if the ACL policy allows a node to connect to another",
+	Long: `
+Check if the loaded ACL policy allows a node to connect to another one.
+
+With --trace, show for each rule what its sources and destinations expanded
+to (the namespaces of the groups and tag owners, and the IPs), which of
+them matched the nodes, and why the rule accepted the connection or not.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		src, _ := cmd.Flags().GetUint64("src")
+		dst, _ := cmd.Flags().GetUint64("dst")
+		port, _ := cmd.Flags().GetUint16("port")
+		trace, _ := cmd.Flags().GetBool("trace")
+		if src == 0 || dst == 0 {
+			ErrorOutput(
+				errMissingParameter,
+				"Please specify both --src and --dst",
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.CheckACLRequest{
+			SourceMachineId:      src,
+			DestinationMachineId: dst,
+			Port:                 uint32(port),
+			Trace:                trace,
+		}
+
+		response, err := client.CheckACL(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot check ACL policy: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response, "", output)
+
+			return
+		}
+
+		if trace {
+			tableData := pterm.TableData{
+				{"Rule", "Proto", "Sources", "Destinations", "Result"},
+			}
+			for _, rule := range response.GetRules() {
+				result := pterm.LightRed(rule.GetReason())
+				if rule.GetAccepted() {
+					result = pterm.LightGreen(rule.GetReason())
+				}
+
+				tableData = append(tableData, []string{
+					strconv.FormatUint(uint64(rule.GetRule()), headscale.Base10),
+					rule.GetProtocol(),
+					aliasTracesToString(rule.GetSources()),
+					aliasTracesToString(rule.GetDestinations()),
+					result,
+				})
+			}
+			err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Failed to render pterm table: %s", err),
+					output,
+				)
+
+				return
+			}
+		}
+
+		if response.GetAllowed() {
+			fmt.Println(pterm.LightGreen("Allowed: " + response.GetReason()))
+		} else {
+			fmt.Println(pterm.LightRed("Denied: " + response.GetReason()))
+		}
+	},
+}
+
+// aliasTracesToString shows one alias per line, with what it expanded
+// to, in green when it matched the node.
+func aliasTracesToString(traces []*v1.ACLAliasTrace) string {
+	lines := make([]string, 0, len(traces))
+	for _, trace := range traces {
+		alias := trace.GetAlias()
+		if trace.GetPorts() != "" {
+			alias += ":" + trace.GetPorts()
+		}
+
+		expanded := []string{}
+		if len(trace.GetNamespaces()) > 0 {
+			expanded = append(expanded, "namespaces: "+strings.Join(trace.GetNamespaces(), ", "))
+		}
+		if trace.GetError() != "" {
+			expanded = append(expanded, "error: "+trace.GetError())
+		} else {
+			expanded = append(expanded, "IPs: "+strings.Join(trace.GetIps(), ", "))
+		}
+		line := fmt.Sprintf("%s (%s)", alias, strings.Join(expanded, "; "))
+
+		if trace.GetMatched() {
+			line = pterm.LightGreen(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -25,6 +25,8 @@ func init() {
 
 	createAPIKeyCmd.Flags().
 		StringP("expiration", "e", DefaultAPIKeyExpiry, "Human-readable expiration of the key (e.g. 30m, 24h)")
+	createAPIKeyCmd.Flags().
+		String("scope", headscale.APIKeyScopeFull, "Scope of the key: full, or read-only to only read the state")
 
 	apiKeysCmd.AddCommand(createAPIKeyCmd)
 
@@ -73,7 +75,7 @@ var listAPIKeys = &cobra.Command{
 		}
 
 		tableData := pterm.TableData{
-			{"ID", "Prefix", "Expiration", "Created"},
+			{"ID", "Prefix", "Scope", "Expiration", "Created"},
 		}
 		for _, key := range response.ApiKeys {
 			expiration := "-"
@@ -85,6 +87,7 @@ var listAPIKeys = &cobra.Command{
 			tableData = append(tableData, []string{
 				strconv.FormatUint(key.GetId(), headscale.Base10),
 				key.GetPrefix(),
+				key.GetScope(),
 				expiration,
 				key.GetCreatedAt().AsTime().Format(HeadscaleDateTimeFormat),
 			})
@@ -109,7 +112,11 @@ var createAPIKeyCmd = &cobra.Command{
 	Long: `
 Creates a new Api key, the Api key is only visible on creation
 and cannot be retrieved again.
-If you loose a key, create a new one and revoke (expire) the old one.`,
+If you loose a key, create a new one and revoke (expire) the old one.
+
+A key created with --scope read-only can only read the state (e.g. list
+the nodes or dump their netmap), for a dashboard. The server refuses the
+other calls with PERMISSION_DENIED (403 Forbidden on the REST API).`,
 	Aliases: []string{"c", "new"},
 	Run: func(cmd *cobra.Command, args []string) {
 		output, _ := cmd.Flags().GetString("output")
@@ -137,6 +144,7 @@ If you loose a key, create a new one and revoke (expire) the old one.`,
 		log.Trace().Dur("expiration", time.Duration(duration)).Msg("expiration has been set")
 
 		request.Expiration = timestamppb.New(expiration)
+		request.Scope, _ = cmd.Flags().GetString("scope")
 
 		ctx, client, conn, cancel := getHeadscaleCLIClient()
 		defer cancel()
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	aclCmd.AddCommand(setAutoTagCmd)
+	aclCmd.AddCommand(unsetAutoTagCmd)
+	aclCmd.AddCommand(listAutoTagsCmd)
+
+	autoTagNodesCmd.Flags().Bool("dry-run", false, "Only show the tags that would be added")
+	nodeCmd.AddCommand(autoTagNodesCmd)
+}
+
+var setAutoTagCmd = &cobra.Command{
+	Use:   "set-autotag PATTERN TAG...",
+	Short: "Force tags on the nodes with a hostname matching a regular expression",
+	Long: `
+Force tags on the nodes registered with a hostname matching the regular
+expression PATTERN, e.g. headscale policy set-autotag '^web-\d+$' tag:web.
+Setting the tags of an existing pattern replaces them.
+
+The rule applies to the new registrations, use 'headscale nodes autotag'
+to apply it to the existing nodes. Rules can also be set in the autotags
+section of the configuration file.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.SetAutoTagRequest{
+			Pattern: args[0],
+			Tags:    args[1:],
+		}
+
+		response, err := client.SetAutoTag(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot set autotag: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response.AutoTag, "Autotag set", output)
+	},
+}
+
+var unsetAutoTagCmd = &cobra.Command{
+	Use:   "unset-autotag PATTERN",
+	Short: "Delete an autotag set with set-autotag",
+	Long:  "Deleting an autotag does not remove the tags it added to the nodes.",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.DeleteAutoTag(ctx, &v1.DeleteAutoTagRequest{Pattern: args[0]})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot delete autotag: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response, "Autotag deleted", output)
+	},
+}
+
+var listAutoTagsCmd = &cobra.Command{
+	Use:   "autotags",
+	Short: "List the autotags, from the configuration file and set with set-autotag",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ListAutoTags(ctx, &v1.ListAutoTagsRequest{})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get autotags: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.AutoTags, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{{"Pattern", "Tags", "Source"}}
+		for _, autoTag := range response.GetAutoTags() {
+			tableData = append(tableData, []string{
+				autoTag.GetPattern(),
+				strings.Join(autoTag.GetTags(), ", "),
+				autoTag.GetSource(),
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+var autoTagNodesCmd = &cobra.Command{
+	Use:   "autotag",
+	Short: "Add the autotags to the existing nodes",
+	Long: `
+Add the tags of the autotags matching the hostname of the existing nodes
+to their forced tags. The new registrations get them automatically.
+Use --dry-run to preview the tags each node would get.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ApplyAutoTags(ctx, &v1.ApplyAutoTagsRequest{DryRun: dryRun})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot apply autotags: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.Changes, "", output)
+
+			return
+		}
+
+		header := "Added tags"
+		if dryRun {
+			header = "Tags to add"
+		}
+		tableData := pterm.TableData{{"ID", "Hostname", "Name", header}}
+		for _, change := range response.GetChanges() {
+			tableData = append(tableData, []string{
+				strconv.FormatUint(change.GetMachine().GetId(), headscale.Base10),
+				change.GetMachine().GetName(),
+				change.GetMachine().GetGivenName(),
+				strings.Join(change.GetAddedTags(), ", "),
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
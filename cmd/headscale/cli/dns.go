@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	rootCmd.AddCommand(dnsCmd)
+
+	dnsRecordsCmd.Flags().StringP("namespace", "n", "", "Only show the records of this namespace")
+	dnsCmd.AddCommand(dnsRecordsCmd)
+}
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Inspect the MagicDNS configuration of Headscale",
+}
+
+var dnsRecordsCmd = &cobra.Command{
+	Use:   "records",
+	Short: "Show the MagicDNS records of the nodes",
+	Long: `
+Show the A and AAAA records the nodes can resolve through MagicDNS, as a zone
+file like listing. The names are computed like in the netmaps sent to the
+nodes. Headscale does not serve any other kind of record.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		namespace, _ := cmd.Flags().GetString("namespace")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.ListDNSRecordsRequest{
+			Namespace: namespace,
+		}
+
+		response, err := client.ListDNSRecords(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot list DNS records: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.Records, "", output)
+
+			return
+		}
+
+		if !response.GetMagicDns() {
+			fmt.Println("; MagicDNS is disabled, the nodes do not resolve these records")
+		}
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+		for _, record := range response.GetRecords() {
+			fmt.Fprintf(
+				writer,
+				"%s\tIN\t%s\t%s\n",
+				record.GetName(),
+				record.GetType(),
+				record.GetValue(),
+			)
+		}
+		writer.Flush()
+	},
+}
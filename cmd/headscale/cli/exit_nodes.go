@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	rootCmd.AddCommand(exitNodesCmd)
+
+	listExitNodesCmd.Flags().StringP("namespace", "n", "", "Filter by namespace")
+	exitNodesCmd.AddCommand(listExitNodesCmd)
+}
+
+var exitNodesCmd = &cobra.Command{
+	Use:     "exit-nodes",
+	Short:   "Manage the exit nodes of Headscale",
+	Aliases: []string{"exit-node", "exits"},
+}
+
+var listExitNodesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the nodes advertising themselves as exit nodes",
+	Long: "List the nodes advertising both default routes (0.0.0.0/0 and ::/0). " +
+		"An exit node is approved when both routes are enabled, " +
+		"see 'headscale nodes routes enable'.",
+	Aliases: []string{"ls", "show"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		namespace, _ := cmd.Flags().GetString("namespace")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.ListExitNodesRequest{
+			Namespace: namespace,
+		}
+
+		response, err := client.ListExitNodes(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get exit nodes: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.ExitNodes, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{
+			{"ID", "Hostname", "Name", "Namespace", "Approved", "Online", "DERP"},
+		}
+		for _, exitNode := range response.GetExitNodes() {
+			machine := exitNode.GetMachine()
+
+			approved := pterm.LightGreen("yes")
+			if !exitNode.GetApproved() {
+				approved = pterm.LightYellow("no, awaiting approval")
+			}
+
+			online := pterm.LightRed("offline")
+			if nodeOnline(machine, defaultOnlineThreshold) {
+				online = pterm.LightGreen("online")
+			}
+
+			derpRegion := "-"
+			if machine.GetPreferredDerpRegion() != 0 {
+				derpRegion = strconv.Itoa(int(machine.GetPreferredDerpRegion()))
+			}
+
+			tableData = append(tableData, []string{
+				strconv.FormatUint(machine.GetId(), headscale.Base10),
+				machine.GetName(),
+				machine.GetGivenName(),
+				machine.GetNamespace().GetName(),
+				approved,
+				online,
+				derpRegion,
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
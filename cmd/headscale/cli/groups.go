@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	rootCmd.AddCommand(groupsCmd)
+	groupsCmd.AddCommand(listGroupsCmd)
+	groupsCmd.AddCommand(showGroupCmd)
+}
+
+var groupsCmd = &cobra.Command{
+	Use:     "groups",
+	Short:   "Inspect the groups of the ACL policy and their nodes",
+	Aliases: []string{"group"},
+}
+
+var listGroupsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the groups of the ACL policy with their current nodes",
+	Long: `
+List the groups of the loaded ACL policy, with the nodes of their
+namespaces, as the groups are expanded in the ACL rules.`,
+	Aliases: []string{"ls"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ListACLGroups(ctx, &v1.ListACLGroupsRequest{})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get groups: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.Groups, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{{"Group", "Namespaces", "Nodes", "Count"}}
+		for _, group := range response.GetGroups() {
+			namespaces := strings.Join(group.GetNamespaces(), ", ")
+			if group.GetError() != "" {
+				namespaces = pterm.LightRed(group.GetError())
+			}
+
+			nodes := make([]string, 0, len(group.GetMachines()))
+			for _, machine := range group.GetMachines() {
+				nodes = append(nodes, machine.GetGivenName())
+			}
+
+			tableData = append(tableData, []string{
+				group.GetName(),
+				namespaces,
+				strings.Join(nodes, ", "),
+				strconv.Itoa(len(group.GetMachines())),
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+var showGroupCmd = &cobra.Command{
+	Use:   "show GROUP",
+	Short: "Show a group of the ACL policy and its current nodes",
+	Long: `
+Show the members of a group of the loaded ACL policy, the tags it owns,
+and the nodes it currently covers. The "group:" prefix is optional.`,
+	Aliases: []string{"get"},
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.GetACLGroup(ctx, &v1.GetACLGroupRequest{Name: args[0]})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get group: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.Group, "", output)
+
+			return
+		}
+
+		group := response.GetGroup()
+		fmt.Printf("Group:      %s\n", group.GetName())
+		fmt.Printf("Members:    %s\n", strings.Join(group.GetMembers(), ", "))
+		if group.GetError() != "" {
+			fmt.Printf("Error:      %s\n", pterm.LightRed(group.GetError()))
+		}
+		fmt.Printf("Owned tags: %s\n\n", valueOrDash(strings.Join(group.GetTags(), ", ")))
+
+		tableData := pterm.TableData{
+			{"ID", "Hostname", "Name", "Namespace", "IP addresses", "Tags", "Online"},
+		}
+		for _, machine := range group.GetMachines() {
+			online := pterm.LightRed("offline")
+			if nodeOnline(machine, defaultOnlineThreshold) {
+				online = pterm.LightGreen("online")
+			}
+
+			tableData = append(tableData, []string{
+				strconv.FormatUint(machine.GetId(), headscale.Base10),
+				machine.GetName(),
+				machine.GetGivenName(),
+				machine.GetNamespace().GetName(),
+				strings.Join(machine.GetIpAddresses(), ", "),
+				strings.Join(machine.GetForcedTags(), ", "),
+				online,
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
@@ -1,15 +1,22 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/juanfont/headscale"
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/prometheus/common/model"
 	"github.com/pterm/pterm"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 )
 
 func init() {
@@ -18,6 +25,35 @@ func init() {
 	namespaceCmd.AddCommand(listNamespacesCmd)
 	namespaceCmd.AddCommand(destroyNamespaceCmd)
 	namespaceCmd.AddCommand(renameNamespaceCmd)
+
+	namespaceCmd.AddCommand(namespaceImpactCmd)
+	namespaceImpactCmd.Flags().StringP("name", "n", "", "Namespace")
+	err := namespaceImpactCmd.MarkFlagRequired("name")
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+
+	namespaceCmd.AddCommand(setNamespaceCmd)
+	setNamespaceCmd.Flags().StringP("name", "n", "", "Namespace")
+	err = setNamespaceCmd.MarkFlagRequired("name")
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	setNamespaceCmd.Flags().
+		String("dns-domain", "", "MagicDNS domain overriding [namespace].[base_domain] for the nodes of the namespace (empty to remove)")
+	setNamespaceCmd.Flags().
+		String("idle-expiry", "", "Human-readable duration (e.g. 30d) after which the nodes not seen are expired (0 to disable)")
+	setNamespaceCmd.Flags().
+		Uint32("mtu", 0, "MTU the nodes of the namespace should use, between 1280 and 9000 (0 to unset)")
+
+	for _, cmd := range []*cobra.Command{lockNamespaceCmd, unlockNamespaceCmd} {
+		namespaceCmd.AddCommand(cmd)
+		cmd.Flags().StringP("name", "n", "", "Namespace")
+		err = cmd.MarkFlagRequired("name")
+		if err != nil {
+			log.Fatal().Err(err).Msg("")
+		}
+	}
 }
 
 const (
@@ -109,7 +145,7 @@ var destroyNamespaceCmd = &cobra.Command{
 		}
 
 		confirm := false
-		force, _ := cmd.Flags().GetBool("force")
+		force := forceExecution(cmd)
 		if !force {
 			prompt := &survey.Confirm{
 				Message: fmt.Sprintf(
@@ -146,6 +182,97 @@ var destroyNamespaceCmd = &cobra.Command{
 	},
 }
 
+var namespaceImpactCmd = &cobra.Command{
+	Use:   "impact",
+	Short: "Show what would be removed along with a namespace",
+	Long: `
+List the nodes owned by a namespace, the other namespaces those nodes are
+visible from, their enabled routes and the pre auth keys of the namespace.
+This is a read-only check to run before destroying a namespace.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		namespaceName, err := cmd.Flags().GetString("name")
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error getting namespace: %s", err), output)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.GetNamespaceImpactRequest{Name: namespaceName}
+
+		response, err := client.GetNamespaceImpact(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get namespace impact: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response, "", output)
+
+			return
+		}
+
+		sharedMachines := 0
+		enabledRoutes := 0
+		tableData := pterm.TableData{{"ID", "Name", "Shared with", "Enabled routes"}}
+		for _, machine := range response.GetMachines() {
+			if len(machine.GetSharedWith()) > 0 {
+				sharedMachines++
+			}
+			enabledRoutes += len(machine.GetEnabledRoutes())
+
+			tableData = append(
+				tableData,
+				[]string{
+					strconv.FormatUint(machine.GetMachine().GetId(), headscale.Base10),
+					machine.GetMachine().GetGivenName(),
+					strings.Join(machine.GetSharedWith(), ", "),
+					strings.Join(machine.GetEnabledRoutes(), ", "),
+				},
+			)
+		}
+
+		if len(response.GetMachines()) > 0 {
+			err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Failed to render pterm table: %s", err),
+					output,
+				)
+
+				return
+			}
+		}
+
+		SuccessOutput(
+			response,
+			fmt.Sprintf(
+				"Namespace %s: %d node(s), %d visible from other namespaces, %d enabled route(s), %d pre auth key(s)",
+				namespaceName,
+				len(response.GetMachines()),
+				sharedMachines,
+				enabledRoutes,
+				len(response.GetPreAuthKeys()),
+			),
+			output,
+		)
+	},
+}
+
 var listNamespacesCmd = &cobra.Command{
 	Use:     "list",
 	Short:   "List all the namespaces",
@@ -176,13 +303,21 @@ var listNamespacesCmd = &cobra.Command{
 			return
 		}
 
-		tableData := pterm.TableData{{"ID", "Name", "Created"}}
+		tableData := pterm.TableData{{"ID", "Name", "DNS domain", "MTU", "Locked", "Created"}}
 		for _, namespace := range response.GetNamespaces() {
+			locked := "-"
+			if namespace.GetLocked() {
+				locked = pterm.LightRed("locked")
+			}
+
 			tableData = append(
 				tableData,
 				[]string{
 					namespace.GetId(),
 					namespace.GetName(),
+					namespace.GetDnsDomain(),
+					namespaceMTUString(namespace),
+					locked,
 					namespace.GetCreatedAt().AsTime().Format("2006-01-02 15:04:05"),
 				},
 			)
@@ -241,3 +376,259 @@ var renameNamespaceCmd = &cobra.Command{
 		SuccessOutput(response.Namespace, "Namespace renamed", output)
 	},
 }
+
+var setNamespaceCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set properties of a namespace",
+	Long: `
+Set properties of a namespace.
+
+--idle-expiry expires the nodes of the namespace which have not been seen
+for the given duration. Headscale checks for idle nodes every 5 minutes.
+A connected node is seen continuously, so reconnecting resets the clock,
+and a node never seen is idle since its registration.
+
+--mtu records the MTU the nodes of the namespace should use, e.g. 1280 on
+networks with tunneling overhead. The Tailscale clients do not receive it
+from the server: set TS_DEBUG_MTU to this value in the environment of
+tailscaled. Unset, the clients use 1280.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		namespaceName, err := cmd.Flags().GetString("name")
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error getting namespace: %s", err), output)
+
+			return
+		}
+
+		if !cmd.Flags().Changed("dns-domain") &&
+			!cmd.Flags().Changed("idle-expiry") &&
+			!cmd.Flags().Changed("mtu") {
+			ErrorOutput(
+				errMissingParameter,
+				"Nothing to set, please specify at least one property (e.g. --dns-domain)",
+				output,
+			)
+
+			return
+		}
+
+		var idleExpiry time.Duration
+		if cmd.Flags().Changed("idle-expiry") {
+			idleExpiryStr, _ := cmd.Flags().GetString("idle-expiry")
+			duration, err := model.ParseDuration(idleExpiryStr)
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Could not parse duration: %s\n", err),
+					output,
+				)
+
+				return
+			}
+			idleExpiry = time.Duration(duration)
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		var namespace *v1.Namespace
+
+		if cmd.Flags().Changed("dns-domain") {
+			dnsDomain, _ := cmd.Flags().GetString("dns-domain")
+
+			request := &v1.SetNamespaceDNSDomainRequest{
+				Name:      namespaceName,
+				DnsDomain: dnsDomain,
+			}
+
+			response, err := client.SetNamespaceDNSDomain(ctx, request)
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf(
+						"Cannot set DNS domain of namespace: %s",
+						status.Convert(err).Message(),
+					),
+					output,
+				)
+
+				return
+			}
+			namespace = response.Namespace
+		}
+
+		if cmd.Flags().Changed("idle-expiry") {
+			request := &v1.SetNamespaceIdleExpiryRequest{
+				Name:       namespaceName,
+				IdleExpiry: durationpb.New(idleExpiry),
+			}
+
+			response, err := client.SetNamespaceIdleExpiry(ctx, request)
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf(
+						"Cannot set idle expiry of namespace: %s",
+						status.Convert(err).Message(),
+					),
+					output,
+				)
+
+				return
+			}
+			namespace = response.Namespace
+		}
+
+		if cmd.Flags().Changed("mtu") {
+			mtu, _ := cmd.Flags().GetUint32("mtu")
+
+			request := &v1.SetNamespaceMTURequest{
+				Name: namespaceName,
+				Mtu:  mtu,
+			}
+
+			response, err := client.SetNamespaceMTU(ctx, request)
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf(
+						"Cannot set MTU of namespace: %s",
+						status.Convert(err).Message(),
+					),
+					output,
+				)
+
+				return
+			}
+			namespace = response.Namespace
+		}
+
+		SuccessOutput(namespace, "Namespace updated", output)
+	},
+}
+
+var lockNamespaceCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Prevent new nodes from registering in a namespace",
+	Long: `
+Refuse the registration of new nodes in a namespace, from the CLI, OIDC
+or a pre-auth key, for example during a maintenance. The nodes already
+registered keep working and can still log in again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setNamespaceLocked(cmd, true)
+	},
+}
+
+var unlockNamespaceCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Allow new nodes to register in a namespace again",
+	Run: func(cmd *cobra.Command, args []string) {
+		setNamespaceLocked(cmd, false)
+	},
+}
+
+func setNamespaceLocked(cmd *cobra.Command, locked bool) {
+	output, _ := cmd.Flags().GetString("output")
+
+	namespaceName, err := cmd.Flags().GetString("name")
+	if err != nil {
+		ErrorOutput(err, fmt.Sprintf("Error getting namespace: %s", err), output)
+
+		return
+	}
+
+	ctx, client, conn, cancel := getHeadscaleCLIClient()
+	defer cancel()
+	defer conn.Close()
+
+	request := &v1.SetNamespaceLockedRequest{
+		Name:   namespaceName,
+		Locked: locked,
+	}
+
+	response, err := client.SetNamespaceLocked(ctx, request)
+	if err != nil {
+		ErrorOutput(
+			err,
+			fmt.Sprintf(
+				"Cannot lock or unlock namespace: %s",
+				status.Convert(err).Message(),
+			),
+			output,
+		)
+
+		return
+	}
+
+	result := "Namespace unlocked"
+	if locked {
+		result = "Namespace locked"
+	}
+	SuccessOutput(response.Namespace, result, output)
+}
+
+// namespaceMTUString shows the MTU of a namespace, or a dash when unset.
+func namespaceMTUString(namespace *v1.Namespace) string {
+	if namespace.GetMtu() == 0 {
+		return "-"
+	}
+
+	return strconv.FormatUint(uint64(namespace.GetMtu()), headscale.Base10)
+}
+
+// checkNamespaceExists returns a NotFound error when a namespace does not
+// exist, to tell it apart from a namespace without nodes.
+func checkNamespaceExists(
+	ctx context.Context,
+	client v1.HeadscaleServiceClient,
+	name string,
+) error {
+	response, err := client.ListNamespaces(ctx, &v1.ListNamespacesRequest{})
+	if err != nil {
+		return err
+	}
+	for _, namespace := range response.GetNamespaces() {
+		if namespace.GetName() == name {
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.NotFound, "namespace %s not found", name)
+}
+
+// completeNamespaces completes the --namespace flags with the names of the
+// namespaces of the server, and nothing when it cannot be reached.
+func completeNamespaces(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]string, cobra.ShellCompDirective) {
+	cfg, err := headscale.GetHeadscaleConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	ctx, client, conn, cancel, err := newHeadscaleCLIClient(cfg)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer cancel()
+	defer conn.Close()
+
+	response, err := client.ListNamespaces(ctx, &v1.ListNamespacesRequest{})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	names := []string{}
+	for _, namespace := range response.GetNamespaces() {
+		if strings.HasPrefix(namespace.GetName(), toComplete) {
+			names = append(names, namespace.GetName())
+		}
+	}
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,328 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/prometheus/common/model"
+	"github.com/pterm/pterm"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	errInvalidNamespaceSpec = Error("invalid namespace spec")
+
+	namespaceImportCreated = "created"
+	namespaceImportSkipped = "skipped, already exists"
+	namespaceImportFailed  = "failed"
+)
+
+func init() {
+	importNamespacesCmd.Flags().StringP("file", "f", "", "Path to the YAML file of the namespaces")
+	err := importNamespacesCmd.MarkFlagRequired("file")
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	namespaceCmd.AddCommand(importNamespacesCmd)
+
+	exportNamespacesCmd.Flags().
+		StringP("file", "f", "", "Path of the YAML file to write, standard output if not set")
+	namespaceCmd.AddCommand(exportNamespacesCmd)
+}
+
+// namespaceSpec is an entry of the YAML file read by namespaces import
+// and written by namespaces export.
+type namespaceSpec struct {
+	Name       string `yaml:"name"`
+	DNSDomain  string `yaml:"dns_domain,omitempty"`
+	IdleExpiry string `yaml:"idle_expiry,omitempty"`
+	MTU        uint32 `yaml:"mtu,omitempty"`
+
+	idleExpiry time.Duration
+}
+
+// namespaceImportResult is the outcome of the import of a namespace.
+type namespaceImportResult struct {
+	Name      string        `json:"name"`
+	Result    string        `json:"result"`
+	Error     string        `json:"error,omitempty"`
+	Namespace *v1.Namespace `json:"namespace,omitempty"`
+}
+
+// readNamespaceSpecs reads and validates the namespaces of a YAML file,
+// so nothing is created when one of them is invalid.
+func readNamespaceSpecs(path string) ([]namespaceSpec, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var specs []namespaceSpec
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&specs); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(specs))
+	for index := range specs {
+		spec := &specs[index]
+		if err := headscale.CheckForFQDNRules(spec.Name); err != nil {
+			return nil, fmt.Errorf("namespace %q: %s: %w", spec.Name, err, errInvalidNamespaceSpec)
+		}
+		if names[spec.Name] {
+			return nil, fmt.Errorf("namespace %q is defined twice: %w", spec.Name, errInvalidNamespaceSpec)
+		}
+		names[spec.Name] = true
+
+		if spec.DNSDomain != "" {
+			if err := headscale.CheckForDNSDomainRules(spec.DNSDomain); err != nil {
+				return nil, fmt.Errorf(
+					"namespace %q: dns_domain: %s: %w",
+					spec.Name,
+					err,
+					errInvalidNamespaceSpec,
+				)
+			}
+		}
+		if spec.IdleExpiry != "" {
+			duration, err := model.ParseDuration(spec.IdleExpiry)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"namespace %q: idle_expiry: %s: %w",
+					spec.Name,
+					err,
+					errInvalidNamespaceSpec,
+				)
+			}
+			spec.idleExpiry = time.Duration(duration)
+		}
+	}
+
+	return specs, nil
+}
+
+var importNamespacesCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Create the namespaces described in a YAML file",
+	Long: `
+Create the namespaces described in a YAML file, with their settings,
+skipping the ones which already exist. For example:
+
+  - name: servers
+    dns_domain: servers.example.com
+    idle_expiry: 30d
+    mtu: 1280
+  - name: laptops
+
+Every namespace is validated before any is created. The settings of the
+existing namespaces are left unchanged. 'headscale namespaces export'
+writes the namespaces in the same format.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		path, _ := cmd.Flags().GetString("file")
+
+		specs, err := readNamespaceSpecs(path)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error reading namespaces: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ListNamespaces(ctx, &v1.ListNamespacesRequest{})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get namespaces: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+		existing := make(map[string]bool, len(response.GetNamespaces()))
+		for _, namespace := range response.GetNamespaces() {
+			existing[namespace.GetName()] = true
+		}
+
+		created := 0
+		results := make([]namespaceImportResult, len(specs))
+		for index, spec := range specs {
+			results[index] = namespaceImportResult{Name: spec.Name, Result: namespaceImportCreated}
+			if existing[spec.Name] {
+				results[index].Result = namespaceImportSkipped
+
+				continue
+			}
+
+			namespace, err := importNamespace(ctx, client, spec)
+			results[index].Namespace = namespace
+			if err != nil {
+				results[index].Result = namespaceImportFailed
+				results[index].Error = status.Convert(err).Message()
+
+				continue
+			}
+			created++
+		}
+
+		if output != "" {
+			SuccessOutput(results, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{{"Namespace", "Result"}}
+		for _, result := range results {
+			outcome := pterm.LightGreen(result.Result)
+			switch {
+			case result.Error != "":
+				outcome = pterm.LightRed(fmt.Sprintf("%s: %s", result.Result, result.Error))
+			case result.Result == namespaceImportSkipped:
+				outcome = pterm.LightYellow(result.Result)
+			}
+
+			tableData = append(tableData, []string{result.Name, outcome})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		fmt.Printf("%d of %d namespaces created\n", created, len(specs))
+	},
+}
+
+// importNamespace creates a namespace, then sets its settings. The
+// namespace is returned even when a setting fails, as it was created.
+func importNamespace(
+	ctx context.Context,
+	client v1.HeadscaleServiceClient,
+	spec namespaceSpec,
+) (*v1.Namespace, error) {
+	createResponse, err := client.CreateNamespace(
+		ctx,
+		&v1.CreateNamespaceRequest{Name: spec.Name},
+	)
+	if err != nil {
+		return nil, err
+	}
+	namespace := createResponse.GetNamespace()
+
+	if spec.DNSDomain != "" {
+		response, err := client.SetNamespaceDNSDomain(ctx, &v1.SetNamespaceDNSDomainRequest{
+			Name:      spec.Name,
+			DnsDomain: spec.DNSDomain,
+		})
+		if err != nil {
+			return namespace, err
+		}
+		namespace = response.GetNamespace()
+	}
+
+	if spec.idleExpiry != 0 {
+		response, err := client.SetNamespaceIdleExpiry(ctx, &v1.SetNamespaceIdleExpiryRequest{
+			Name:       spec.Name,
+			IdleExpiry: durationpb.New(spec.idleExpiry),
+		})
+		if err != nil {
+			return namespace, err
+		}
+		namespace = response.GetNamespace()
+	}
+
+	if spec.MTU != 0 {
+		response, err := client.SetNamespaceMTU(ctx, &v1.SetNamespaceMTURequest{
+			Name: spec.Name,
+			Mtu:  spec.MTU,
+		})
+		if err != nil {
+			return namespace, err
+		}
+		namespace = response.GetNamespace()
+	}
+
+	return namespace, nil
+}
+
+var exportNamespacesCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the namespaces and their settings as YAML",
+	Long: `
+Write the namespaces and their settings in the YAML format read by
+'headscale namespaces import', to reproduce them on another server.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		path, _ := cmd.Flags().GetString("file")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ListNamespaces(ctx, &v1.ListNamespacesRequest{})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get namespaces: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		specs := make([]namespaceSpec, len(response.GetNamespaces()))
+		for index, namespace := range response.GetNamespaces() {
+			specs[index] = namespaceSpec{
+				Name:      namespace.GetName(),
+				DNSDomain: namespace.GetDnsDomain(),
+				MTU:       namespace.GetMtu(),
+			}
+			if namespace.GetIdleExpiry() != nil {
+				specs[index].IdleExpiry = model.Duration(namespace.GetIdleExpiry().AsDuration()).String()
+			}
+		}
+
+		data, err := yaml.Marshal(specs)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Cannot encode namespaces: %s", err), output)
+
+			return
+		}
+
+		if path == "" {
+			fmt.Print(string(data))
+
+			return
+		}
+
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			ErrorOutput(err, fmt.Sprintf("Cannot write namespaces: %s", err), output)
+
+			return
+		}
+
+		SuccessOutput(specs, fmt.Sprintf("%d namespaces written to %s", len(specs), path), output)
+	},
+}
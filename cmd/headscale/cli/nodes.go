@@ -1,8 +1,13 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -10,6 +15,7 @@ import (
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/juanfont/headscale"
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/prometheus/common/model"
 	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc/status"
@@ -17,85 +23,1393 @@ import (
 	"tailscale.com/types/key"
 )
 
+const (
+	errInvalidLabel           = Error("invalid label")
+	errInvalidNodeSort        = Error("invalid sort, must be one of: id, name, hostname, namespace, last_seen, expired, online, flaps")
+	errInvalidNodeStatus      = Error("invalid status, must be one of: online, offline")
+	errConflictingAge         = Error("--older-than and --namespace cannot be used with --identifier or --selector")
+	errConflictingEphemeral   = Error("--ephemeral and --persistent cannot be used together")
+	errInvalidOnlineThreshold = Error("invalid online threshold, must be positive")
+	errEmptyMachineKey        = Error("no machine key read from the standard input")
+	errConflictingColumns     = Error("--columns all or default cannot be used with other columns")
+	errConflictingFromNS      = Error("--from-namespace cannot be used with --identifier or --selector")
+	errInvalidColumnWidth     = Error("invalid column width, must be a width or column=width (e.g. routes=20)")
+	errUnknownColumn          = Error("unknown column")
+
+	// defaultMaxColumnWidth is the default of --max-col-width.
+	defaultMaxColumnWidth = 40
+
+	machineKeyPrefix = "mkey:"
+	nodeKeyPrefix    = "nodekey:"
+	discoKeyPrefix   = "discokey:"
+
+	// redactedKeyLength is the number of hex characters of a key kept
+	// when it is redacted.
+	redactedKeyLength = 4
+
+	// authKeyPrefixLength is the number of characters of the pre-auth key
+	// of a node shown in the nodes list, enough to tell the keys apart.
+	authKeyPrefixLength = 8
+
+	// defaultOnlineThreshold is how long after it was last seen a node is
+	// shown as online, unless nodes list is given --online-threshold.
+	defaultOnlineThreshold = 5 * time.Minute
+
+	// machinesPageSize is the number of machines fetched per ListMachines
+	// call, to keep the responses under the gRPC message size limit.
+	machinesPageSize = 500
+)
+
 func init() {
 	rootCmd.AddCommand(nodeCmd)
-	listNodesCmd.Flags().StringP("namespace", "n", "", "Filter by namespace")
-	listNodesCmd.Flags().BoolP("tags", "t", false, "Show tags")
+	listNodesCmd.Flags().
+		StringP("namespace", "n", "", "Filter by namespace, or by a pattern of namespaces (e.g. 'team-*')")
+	if err := listNodesCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces); err != nil {
+		log.Fatalf(err.Error())
+	}
+	addNodeTableFlags(listNodesCmd)
+	listNodesCmd.Flags().
+		StringSlice("tag", []string{}, "Only list the nodes with all these tags, forced or requested (e.g. tag:prod)")
+	listNodesCmd.Flags().Bool("deleted", false, "List the deleted nodes that can be restored")
+	listNodesCmd.Flags().
+		String("selector", "", "Only list the nodes matching these labels (e.g. env=staging,team=web)")
+	listNodesCmd.Flags().
+		Bool("show-keys", false, "Show the node keys even if cli.hide_keys_by_default is set")
+	listNodesCmd.Flags().
+		Bool("redact-keys", false, "Mask the keys in the json and yaml outputs")
+	listNodesCmd.Flags().
+		String("sort", "id", "Sort the nodes by id, name, hostname, namespace, last_seen, expired, online or flaps (disconnections during the last hour, most first)")
+	listNodesCmd.Flags().Bool("reverse", false, "Reverse the order of --sort")
+	listNodesCmd.Flags().String("status", "", "Only list the online or offline nodes")
+	listNodesCmd.Flags().
+		String("online-threshold", "5m", "How long after it was last seen a node is online (e.g. 90s, 15m)")
+	listNodesCmd.Flags().
+		StringSlice("filter", []string{}, "Only list the nodes matching all these key=value or key~substring predicates (e.g. online=false,name~web)")
+	listNodesCmd.Flags().
+		Int("limit", 0, "Stop after fetching this many nodes, by ID, before the other filters (0 for all)")
+	listNodesCmd.Flags().
+		Bool("idle-expiring", false, "Only list the nodes subject to the idle expiry of their namespace, soonest first")
+	listNodesCmd.Flags().Bool("legend", false, "Explain the colours of the table")
+	listNodesCmd.Flags().String("arch", "", "Only list the nodes of an architecture (e.g. arm64)")
+	listNodesCmd.Flags().
+		Bool("ephemeral", false, "Only list the ephemeral nodes, deleted when they stay disconnected")
+	listNodesCmd.Flags().Bool("persistent", false, "Only list the nodes which are not ephemeral")
+	listNodesCmd.Flags().
+		String("key", "", "Only list the nodes registered with a pre-auth key starting with this prefix")
+	listNodesCmd.Flags().
+		String("endpoint-contains", "", "Only list the nodes with an endpoint containing this string (e.g. 203.0.113)")
+	listNodesCmd.Flags().
+		String("last-seen-before", "", "Only list the nodes last seen before an RFC 3339 time or a duration ago (e.g. 30d), and the ones never seen")
+	listNodesCmd.Flags().
+		String("last-seen-after", "", "Only list the nodes last seen after an RFC 3339 time or a duration ago (e.g. 24h)")
+	listNodesCmd.Flags().
+		Bool("summary", false, "End the table with the number of nodes listed, online, offline and expired")
 	nodeCmd.AddCommand(listNodesCmd)
 
-	registerNodeCmd.Flags().StringP("namespace", "n", "", "Namespace")
-	err := registerNodeCmd.MarkFlagRequired("namespace")
-	if err != nil {
-		log.Fatalf(err.Error())
-	}
-	registerNodeCmd.Flags().StringP("key", "k", "", "Key")
-	err = registerNodeCmd.MarkFlagRequired("key")
-	if err != nil {
-		log.Fatalf(err.Error())
-	}
-	nodeCmd.AddCommand(registerNodeCmd)
+	registerNodeCmd.Flags().StringP("namespace", "n", "", "Namespace, required unless --preauthkey is set")
+	err := registerNodeCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	registerNodeCmd.Flags().StringP("key", "k", "", "Key, - to read it from the standard input")
+	registerNodeCmd.Flags().
+		String("preauthkey", "", "Pre-auth key to register the node with, giving it the namespace and the ephemeral setting of the key")
+	registerNodeCmd.Flags().
+		String("keys-file", "", "File of machine keys to register, one per line (# starts a comment)")
+	registerNodeCmd.Flags().
+		String("from-yaml", "", "YAML file of the machines to register, with their key, namespace, name and tags")
+	registerNodeCmd.Flags().
+		StringArrayP("label", "l", []string{}, "Label to set on the node, as key=value (can be repeated)")
+	registerNodeCmd.Flags().String("template", "", "Node template to apply")
+	registerNodeCmd.Flags().
+		Bool("dry-run", false, "Only validate the registration and show the node it would create")
+	registerNodeCmd.Flags().
+		StringSlice("tags", []string{}, "Tags forced on the node, replacing the ones of the template")
+	registerNodeCmd.Flags().
+		StringSlice("enable-routes", []string{}, "Routes to enable, replacing the ones of the template")
+	registerNodeCmd.Flags().
+		String("expiry", "", "Human-readable validity of the registration (e.g. 90d), replacing the one of the template")
+	registerNodeCmd.Flags().
+		StringSlice("ip", []string{}, "IP address to give to the node, one per configured prefix, the others are allocated (e.g. 100.64.0.10)")
+	registerNodeCmd.Flags().
+		StringSlice("wait-for-routes", []string{}, "Wait until the node advertises these routes")
+	registerNodeCmd.Flags().
+		Duration("timeout", time.Minute, "How long to wait for the routes of --wait-for-routes")
+	registerNodeCmd.Flags().
+		Bool("auto-enable", false, "Enable the routes of --wait-for-routes once advertised")
+	nodeCmd.AddCommand(registerNodeCmd)
+
+	nodeCmd.AddCommand(pendingNodesCmd)
+
+	approveNodeCmd.Flags().StringP("key", "k", "", "Machine key of the pending node")
+	err = approveNodeCmd.MarkFlagRequired("key")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(approveNodeCmd)
+
+	expireNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	addNodeSelectorFlags(expireNodeCmd)
+	expireNodeCmd.Flags().
+		String("older-than", "", "Expire every node registered longer ago than this human-readable age (e.g. 180d)")
+	expireNodeCmd.Flags().
+		StringP("namespace", "n", "", "Expire every node of this namespace, or only its old ones with --older-than")
+	expireNodeCmd.Flags().
+		Bool("dry-run", false, "List the nodes that would be expired, with --older-than, --namespace or --selector")
+	nodeCmd.AddCommand(expireNodeCmd)
+
+	quarantineNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = quarantineNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(quarantineNodeCmd)
+
+	unquarantineNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = unquarantineNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(unquarantineNodeCmd)
+
+	restoreNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = restoreNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(restoreNodeCmd)
+
+	setDERPNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = setDERPNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	setDERPNodeCmd.Flags().Int("region", 0, "DERP region ID to force")
+	setDERPNodeCmd.Flags().Bool("clear", false, "Revert to the automatic DERP region selection")
+	nodeCmd.AddCommand(setDERPNodeCmd)
+
+	setExitOnlyNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = setExitOnlyNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	setExitOnlyNodeCmd.Flags().
+		Bool("disable", false, "Allow direct peer access to the node again")
+	nodeCmd.AddCommand(setExitOnlyNodeCmd)
+
+	touchNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = touchNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	touchNodeCmd.Flags().
+		Bool("i-understand-this-is-for-testing", false, "Confirm that the last seen time of a real node will be faked")
+	err = touchNodeCmd.MarkFlagRequired("i-understand-this-is-for-testing")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(touchNodeCmd)
+
+	renameNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err = renameNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(renameNodeCmd)
+
+	deleteNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	addNodeSelectorFlags(deleteNodeCmd)
+	nodeCmd.AddCommand(deleteNodeCmd)
+
+	moveNodeCmd.Flags().
+		UintSliceP("identifier", "i", nil, "Node identifier (ID), repeat it to move several nodes")
+	addNodeSelectorFlags(moveNodeCmd)
+	moveNodeCmd.Flags().String("from-namespace", "", "Move all the nodes of this namespace")
+
+	moveNodeCmd.Flags().StringP("namespace", "n", "", "New namespace")
+
+	err = moveNodeCmd.MarkFlagRequired("namespace")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	for _, flag := range []string{"namespace", "from-namespace"} {
+		err = moveNodeCmd.RegisterFlagCompletionFunc(flag, completeNamespaces)
+		if err != nil {
+			log.Fatalf(err.Error())
+		}
+	}
+	nodeCmd.AddCommand(moveNodeCmd)
+
+	tagCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	addNodeSelectorFlags(tagCmd)
+	tagCmd.Flags().
+		StringSliceP("tags", "t", []string{}, "List of tags to force on the node, replacing the forced ones")
+	nodeCmd.AddCommand(tagCmd)
+}
+
+var nodeCmd = &cobra.Command{
+	Use:     "nodes",
+	Short:   "Manage the nodes of Headscale",
+	Aliases: []string{"node", "machine", "machines"},
+}
+
+var registerNodeCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Registers a machine to your network",
+	Long: `
+Register a machine to your network.
+
+With --wait-for-routes, wait until the node advertises the given routes,
+for subnet routers which can only have their routes enabled once their
+client advertises them. --auto-enable then enables the routes, keeping the
+ones already enabled.
+
+With --dry-run, the server validates the namespace, the key, the template,
+the tags, routes, labels and expiry, and shows the node it would create:
+the IP addresses it would get, its tags and its enabled routes. Nothing is
+created.
+
+With --ip, the node gets the given addresses, for example to keep the ones
+it had on another control server. They must be in the configured prefixes
+and not be used by another node. The addresses of the prefixes without an
+--ip are allocated as usual.
+
+With --key -, the key is read from the standard input, so it does not
+appear in the shell history nor in the arguments of the process:
+
+  echo "$MACHINE_KEY" | headscale nodes register -n NAMESPACE --key -
+
+With --keys-file, every key of the file is registered with the same
+settings, instead of --key. The failures do not stop the batch: the result
+of each key is shown and the command exits with a non-zero status when one
+of them failed.
+
+With --from-yaml, the machines of a YAML file are registered like with
+--keys-file, each with its namespace, defaulting to --namespace, its tags,
+replacing --tags, and its name:
+
+  - key: mkey:...
+    namespace: servers
+    name: web-1
+    tags: [tag:web]
+  - key: mkey:...
+
+Every entry is validated before any machine is registered, the errors
+giving the line of the entry.
+
+With --preauthkey, the node is registered like a client logging in with
+the pre-auth key: it gets the namespace and the ephemeral setting of the
+key, which must be valid and counts as used. --namespace is then optional,
+and must be the namespace of the key when set.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		namespace, err := cmd.Flags().GetString("namespace")
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error getting namespace: %s", err), output)
+
+			return
+		}
+		preAuthKey, _ := cmd.Flags().GetString("preauthkey")
+		fromYAML, _ := cmd.Flags().GetString("from-yaml")
+		if namespace == "" && preAuthKey == "" && fromYAML == "" {
+			ErrorOutput(
+				errMissingParameter,
+				"Please specify either --namespace or --preauthkey",
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		machineKey, err := cmd.Flags().GetString("key")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error getting machine key from flag: %s", err),
+				output,
+			)
+
+			return
+		}
+		if machineKey == "-" {
+			machineKey, err = readMachineKeyFromStdin()
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Error reading machine key: %s", err),
+					output,
+				)
+
+				return
+			}
+		}
+		keysFile, _ := cmd.Flags().GetString("keys-file")
+		sources := 0
+		for _, source := range []string{machineKey, keysFile, fromYAML} {
+			if source != "" {
+				sources++
+			}
+		}
+		switch {
+		case sources == 0:
+			ErrorOutput(
+				errMissingParameter,
+				"Please specify either --key, --keys-file or --from-yaml",
+				output,
+			)
+
+			return
+		case sources > 1:
+			ErrorOutput(errConflictingKeys, errConflictingKeys.Error(), output)
+
+			return
+		}
+
+		labelsArgs, _ := cmd.Flags().GetStringArray("label")
+		labels, err := parseLabels(labelsArgs)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error parsing labels: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		expiry, err := parseExpiry(cmd)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Could not parse duration: %s\n", err),
+				output,
+			)
+
+			return
+		}
+
+		template, _ := cmd.Flags().GetString("template")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		routes, _ := cmd.Flags().GetStringSlice("enable-routes")
+		ipAddresses, _ := cmd.Flags().GetStringSlice("ip")
+
+		waitRoutesArgs, _ := cmd.Flags().GetStringSlice("wait-for-routes")
+		waitRoutes, err := normalizeRoutes(waitRoutesArgs)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error parsing routes to wait for: %s", err),
+				output,
+			)
+
+			return
+		}
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		autoEnable, _ := cmd.Flags().GetBool("auto-enable")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		request := &v1.RegisterMachineRequest{
+			Key:         machineKey,
+			Namespace:   namespace,
+			Labels:      labels,
+			Template:    template,
+			Tags:        tags,
+			Routes:      routes,
+			Expiry:      expiry,
+			DryRun:      dryRun,
+			IpAddresses: ipAddresses,
+			PreAuthKey:  preAuthKey,
+		}
+
+		if keysFile != "" || fromYAML != "" {
+			if len(waitRoutes) > 0 {
+				ErrorOutput(errKeysFileWait, errKeysFileWait.Error(), output)
+
+				return
+			}
+			if len(ipAddresses) > 0 {
+				ErrorOutput(errKeysFileIP, errKeysFileIP.Error(), output)
+
+				return
+			}
+		}
+
+		if keysFile != "" {
+			keys, err := readMachineKeys(keysFile)
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Error reading machine keys: %s", err),
+					output,
+				)
+
+				return
+			}
+
+			registerMachineKeys(ctx, client, machineKeyRegistrations(keys, request), dryRun, output)
+
+			return
+		}
+
+		if fromYAML != "" {
+			// With --preauthkey, the entries may take the namespace of the key.
+			specs, err := readNodeRegistrationSpecs(fromYAML, namespace, preAuthKey == "")
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Error reading %s: %s", fromYAML, err),
+					output,
+				)
+
+				return
+			}
+
+			registerMachineKeys(ctx, client, nodeSpecRegistrations(specs, request), dryRun, output)
+
+			return
+		}
+
+		response, err := client.RegisterMachine(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot register machine: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if dryRun {
+			SuccessOutput(response, dryRunRegistrationMessage(response), output)
+
+			return
+		}
+
+		if len(waitRoutes) == 0 {
+			SuccessOutput(response.Machine, "Machine register", output)
+
+			return
+		}
+
+		result, err := waitForRoutes(client, response.Machine, waitRoutes, timeout, autoEnable)
+		if errors.Is(err, errRoutesNotAdvertised) {
+			ErrorOutput(err, fmt.Sprintf("Machine registered, but %s", err), output)
+
+			return
+		}
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Machine registered, but cannot set up its routes: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		message := fmt.Sprintf("Machine registered, routes advertised: %s", strings.Join(result.Advertised, ", "))
+		if autoEnable {
+			message = fmt.Sprintf(
+				"Machine registered, routes advertised and enabled: %s",
+				strings.Join(result.Advertised, ", "),
+			)
+		}
+		SuccessOutput(result, message, output)
+	},
+}
+
+// dryRunRegistrationMessage describes the node a registration would
+// create.
+func dryRunRegistrationMessage(response *v1.RegisterMachineResponse) string {
+	machine := response.GetMachine()
+	expiry := "never"
+	if machine.GetExpiry() != nil && !machine.GetExpiry().AsTime().IsZero() {
+		expiry = machine.GetExpiry().AsTime().Format(HeadscaleDateTimeFormat)
+	}
+
+	return fmt.Sprintf(`Dry run, nothing was created. The registration would create:
+  Hostname:       %s
+  Namespace:      %s
+  IP addresses:   %s
+  Tags:           %s
+  Enabled routes: %s
+  Expiry:         %s
+  Ephemeral:      %t`,
+		machine.GetName(),
+		machine.GetNamespace().GetName(),
+		strings.Join(machine.GetIpAddresses(), ", "),
+		valueOrDash(strings.Join(machine.GetForcedTags(), ", ")),
+		valueOrDash(strings.Join(response.GetEnabledRoutes(), ", ")),
+		expiry,
+		machine.GetEphemeral(),
+	)
+}
+
+// parseLabels converts a list of key=value strings to a map.
+func parseLabels(labelsArgs []string) (map[string]string, error) {
+	labels := make(map[string]string, len(labelsArgs))
+	for _, label := range labelsArgs {
+		key, value, found := strings.Cut(label, "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("%w: label %q is not in the key=value format", errInvalidLabel, label)
+		}
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+// nodeColumnFlags are the flags of addNodeTableFlags adding columns to the
+// table, in the order they are shown by --columns all.
+var nodeColumnFlags = []string{
+	"auth-key",
+	"quarantined",
+	"managed",
+	"derp",
+	"flaps",
+	"tags",
+	"ack",
+	"inventory",
+	"clock-skew",
+	"endpoints",
+	"ptr",
+	"derp-names",
+	"ip-columns",
+	"expiry",
+	"shared",
+	"routes-updated",
+}
+
+// nodeColumnAlignments is the alignment of the columns of the table of
+// nodesToPtables, by their columnKey, the numbers aligned on the right.
+// The others are aligned on the left.
+var nodeColumnAlignments = map[string]columnAlignment{
+	"id":    alignRight,
+	"flaps": alignRight,
+}
+
+// addNodeTableFlags adds the flags of the table of nodesToPtables to a
+// command, read by nodesTable.
+func addNodeTableFlags(cmd *cobra.Command) {
+	cmd.Flags().
+		Bool("auth-key", false, "Show the beginning of the pre-auth key the nodes were registered with")
+	cmd.Flags().
+		Bool("quarantined", false, "Show if the nodes are quarantined, in red when they are")
+	cmd.Flags().
+		Bool("managed", false, "Show if the nodes are managed by nodes apply")
+	cmd.Flags().
+		Bool("derp", false, "Show the home DERP region of the nodes, with the forced one in yellow")
+	cmd.Flags().
+		Bool("flaps", false, "Show how many times the nodes disconnected during the last hour")
+	cmd.Flags().BoolP("tags", "t", false, "Show tags")
+	cmd.Flags().
+		Bool("ack", false, "Show when the nodes last received a map and if it is up to date")
+	cmd.Flags().
+		Bool("inventory", false, "Show the OS, OS version, architecture, device model, package and client version of the nodes")
+	cmd.Flags().
+		Bool("clock-skew", false, "Show the offset of the clock of the nodes reporting their time, in red when skewed")
+	cmd.Flags().
+		Bool("endpoints", false, "Show the endpoints (ip:port) last reported by the nodes")
+	cmd.Flags().
+		Bool("ptr", false, "Show the reverse DNS (PTR) names of the IP addresses of the nodes")
+	cmd.Flags().
+		Bool("derp-names", false, "Show the home DERP region of the nodes with its code (e.g. nyc (10)), like --derp")
+	cmd.Flags().
+		Bool("ip-columns", false, "Show the IPv4 and IPv6 addresses of the nodes in separate columns")
+	cmd.Flags().
+		Bool("expiry", false, "Show when the keys of the nodes expire, in yellow within 24 hours")
+	cmd.Flags().
+		Bool("shared", false, "Show if the nodes are shared into the namespace of --namespace rather than owned by it")
+	cmd.Flags().
+		Bool("routes-updated", false, "Show when the routes advertised by the nodes last changed")
+	cmd.Flags().
+		StringSlice("columns", nil, "Optional columns to show (e.g. tags,derp), all for every one, or default for none of them")
+	cmd.Flags().
+		Bool("full-keys", false, "Show the complete node keys instead of their short form")
+	cmd.Flags().
+		StringSlice(
+			"max-col-width",
+			[]string{strconv.Itoa(defaultMaxColumnWidth)},
+			"Truncate the cells longer than this width, or than column=width for a column (e.g. 40,forcedtags=20), 0 to disable",
+		)
+	cmd.Flags().
+		Bool("relative-time", false, "Show when the nodes were last seen relative to now (e.g. 3m ago), in green within 5 minutes and in red after an hour")
+}
+
+// nodesTable is the table of the nodes with the columns of the flags of
+// addNodeTableFlags. --columns must have been applied by applyColumns.
+func nodesTable(
+	cmd *cobra.Command,
+	currentNamespace string,
+	hideKeys bool,
+	showIdleExpiry bool,
+	onlineThreshold time.Duration,
+	machines []*v1.Machine,
+) (pterm.TableData, error) {
+	options := nodeTableOptions{
+		currentNamespace: currentNamespace,
+		hideKeys:         hideKeys,
+		showIdleExpiry:   showIdleExpiry,
+		onlineThreshold:  onlineThreshold,
+	}
+	options.showAuthKey, _ = cmd.Flags().GetBool("auth-key")
+	options.showQuarantined, _ = cmd.Flags().GetBool("quarantined")
+	options.showManaged, _ = cmd.Flags().GetBool("managed")
+	options.showDERP, _ = cmd.Flags().GetBool("derp")
+	options.showFlaps, _ = cmd.Flags().GetBool("flaps")
+	options.showTags, _ = cmd.Flags().GetBool("tags")
+	options.showAck, _ = cmd.Flags().GetBool("ack")
+	options.showInventory, _ = cmd.Flags().GetBool("inventory")
+	options.showClockSkew, _ = cmd.Flags().GetBool("clock-skew")
+	options.showEndpoints, _ = cmd.Flags().GetBool("endpoints")
+	options.showPTR, _ = cmd.Flags().GetBool("ptr")
+	options.showDERPNames, _ = cmd.Flags().GetBool("derp-names")
+	options.showIPColumns, _ = cmd.Flags().GetBool("ip-columns")
+	options.showExpiry, _ = cmd.Flags().GetBool("expiry")
+	options.showShared, _ = cmd.Flags().GetBool("shared")
+	options.showRoutesUpdated, _ = cmd.Flags().GetBool("routes-updated")
+	options.relativeTime, _ = cmd.Flags().GetBool("relative-time")
+	options.fullKeys, _ = cmd.Flags().GetBool("full-keys")
+
+	return nodesToPtables(options, machines)
+}
+
+// nodesSummaryRow is the last row of the table of nodesToPtables for
+// nodes list --summary, counting the listed nodes in the ID, Hostname,
+// Online and Expired columns of header.
+func nodesSummaryRow(
+	header []string,
+	machines []*v1.Machine,
+	onlineThreshold time.Duration,
+) []string {
+	online, expired := 0, 0
+	for _, machine := range machines {
+		if nodeOnline(machine, onlineThreshold) {
+			online++
+		}
+		if machine.GetExpiry() != nil && !machine.GetExpiry().AsTime().IsZero() &&
+			!machine.GetExpiry().AsTime().After(time.Now()) {
+			expired++
+		}
+	}
+
+	summary := map[string]string{
+		"id":       "Total",
+		"hostname": fmt.Sprintf("%d nodes", len(machines)),
+		"online": fmt.Sprintf(
+			"%s, %s",
+			pterm.LightGreen(fmt.Sprintf("%d online", online)),
+			pterm.LightRed(fmt.Sprintf("%d offline", len(machines)-online)),
+		),
+		"expired": fmt.Sprintf("%d expired", expired),
+	}
+	row := make([]string, len(header))
+	for index, column := range header {
+		row[index] = summary[columnKey(column)]
+	}
+
+	return row
+}
+
+// columnKey is the name of a column of a table in --max-col-width, its
+// header in lower case with dashes (e.g. ip-addresses).
+func columnKey(header string) string {
+	return strings.ToLower(strings.ReplaceAll(header, " ", "-"))
+}
+
+// truncateTable truncates the cells of a table, but its header, to the
+// --max-col-width of cmd. It is only applied to the tables rendered by
+// pterm, the other outputs keep the full values.
+func truncateTable(cmd *cobra.Command, tableData pterm.TableData) error {
+	values, _ := cmd.Flags().GetStringSlice("max-col-width")
+
+	width := defaultMaxColumnWidth
+	columnWidths := map[string]int{}
+	for _, value := range values {
+		column, columnWidth, hasColumn := strings.Cut(value, "=")
+		if !hasColumn {
+			columnWidth = column
+		}
+		parsed, err := strconv.Atoi(columnWidth)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("%q: %w", value, errInvalidColumnWidth)
+		}
+		if hasColumn {
+			columnWidths[columnKey(column)] = parsed
+		} else {
+			width = parsed
+		}
+	}
+
+	if len(tableData) == 0 {
+		return nil
+	}
+	widths := make([]int, len(tableData[0]))
+	fullKeys, _ := cmd.Flags().GetBool("full-keys")
+	for index, header := range tableData[0] {
+		widths[index] = width
+		// The complete keys are not truncated, unless their column is set.
+		if fullKeys && columnKey(header) == columnKey("NodeKey") {
+			widths[index] = 0
+		}
+		if columnWidth, ok := columnWidths[columnKey(header)]; ok {
+			widths[index] = columnWidth
+			delete(columnWidths, columnKey(header))
+		}
+	}
+	if len(columnWidths) > 0 {
+		unknown := make([]string, 0, len(columnWidths))
+		for column := range columnWidths {
+			unknown = append(unknown, column)
+		}
+		sort.Strings(unknown)
+
+		return fmt.Errorf("%s: %w", strings.Join(unknown, ", "), errUnknownColumn)
+	}
+
+	for _, row := range tableData[1:] {
+		for index := range row {
+			if index < len(widths) {
+				row[index] = truncateCell(row[index], widths[index])
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyColumns sets the nodeColumnFlags of cmd listed by --columns, all of
+// them for all. default leaves them all unset. all and default are refused
+// with other columns, in --columns or with their flags.
+func applyColumns(cmd *cobra.Command) error {
+	columns, err := cmd.Flags().GetStringSlice("columns")
+	if err != nil || len(columns) == 0 {
+		return err
+	}
+
+	names := columns
+	for _, column := range columns {
+		if column != "all" && column != "default" {
+			continue
+		}
+		if len(columns) > 1 {
+			return errConflictingColumns
+		}
+		for _, name := range nodeColumnFlags {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--%s: %w", name, errConflictingColumns)
+			}
+		}
+		names = nil
+		if column == "all" {
+			names = nodeColumnFlags
+		}
+	}
+
+	for _, name := range names {
+		if !contains(nodeColumnFlags, name) {
+			return fmt.Errorf(
+				"%q, must be all, default or one of %s: %w",
+				name,
+				strings.Join(nodeColumnFlags, ", "),
+				errUnknownColumn,
+			)
+		}
+		if err := cmd.Flags().Set(name, "true"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var listNodesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List nodes",
+	Long: `
+List the nodes, or the ones of a namespace with --namespace.
+
+--namespace also takes a pattern of namespaces, with the * and ? wildcards
+and the [a-z] classes of path.Match. Quote it so the shell does not expand
+it, for instance --namespace 'team-*'. The nodes shared into the matching
+namespaces are not listed, unlike with a namespace name.
+
+--filter only keeps the nodes matching all its predicates: key=value for
+an exact match and key~substring for a substring. The keys are id, name,
+hostname, namespace, ip, online, expired, ephemeral, quarantined, managed,
+os and arch, for instance --filter online=false,name~web.
+
+--last-seen-before and --last-seen-after take an RFC 3339 time or a
+duration ago, for instance --last-seen-before 30d for the nodes not seen
+for 30 days. The nodes never seen are only listed with --last-seen-before
+alone.
+
+--columns adds the optional columns named like their flags, for instance
+--columns tags,derp like --tags --derp. --columns all shows all of them:
+auth-key, quarantined, managed, derp, flaps, tags, ack, inventory,
+clock-skew, endpoints, ptr, derp-names, ip-columns, expiry, shared and
+routes-updated. --columns default shows none of them.`,
+	Aliases: []string{"ls", "show"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		if err := applyColumns(cmd); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+		namespace, err := cmd.Flags().GetString("namespace")
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error getting namespace: %s", err), output)
+
+			return
+		}
+		deleted, _ := cmd.Flags().GetBool("deleted")
+		selector, _ := cmd.Flags().GetString("selector")
+		showKeys, _ := cmd.Flags().GetBool("show-keys")
+		redactKeys, _ := cmd.Flags().GetBool("redact-keys")
+		sortBy, _ := cmd.Flags().GetString("sort")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+		nodeStatus, _ := cmd.Flags().GetString("status")
+		onlineThresholdStr, _ := cmd.Flags().GetString("online-threshold")
+		filterArgs, _ := cmd.Flags().GetStringSlice("filter")
+		limit, _ := cmd.Flags().GetInt("limit")
+		idleExpiring, _ := cmd.Flags().GetBool("idle-expiring")
+		showLegend, _ := cmd.Flags().GetBool("legend")
+		arch, _ := cmd.Flags().GetString("arch")
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		endpointContains, _ := cmd.Flags().GetString("endpoint-contains")
+		keyPrefix, _ := cmd.Flags().GetString("key")
+		ephemeralOnly, _ := cmd.Flags().GetBool("ephemeral")
+		persistentOnly, _ := cmd.Flags().GetBool("persistent")
+		lastSeenBeforeStr, _ := cmd.Flags().GetString("last-seen-before")
+		lastSeenAfterStr, _ := cmd.Flags().GetString("last-seen-after")
+		showSummary, _ := cmd.Flags().GetBool("summary")
+
+		onlineThreshold, err := time.ParseDuration(onlineThresholdStr)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Could not parse online threshold: %s", err),
+				output,
+			)
+
+			return
+		}
+		if onlineThreshold <= 0 {
+			ErrorOutput(errInvalidOnlineThreshold, errInvalidOnlineThreshold.Error(), output)
+
+			return
+		}
+		if err := validateTags(tags); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+		filters, err := parseNodeFilters(filterArgs)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error parsing filters: %s", err), output)
+
+			return
+		}
+		pattern, err := namespacePattern(namespace)
+		if err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+		if pattern != "" {
+			namespace = ""
+		}
+
+		nodeLess, ok := nodeSorts[sortBy]
+		if !ok {
+			ErrorOutput(errInvalidNodeSort, errInvalidNodeSort.Error(), output)
+
+			return
+		}
+		// The online sort follows --online-threshold, like the table.
+		if sortBy == "online" {
+			nodeLess = func(a, b *v1.Machine) bool {
+				return nodeOnline(a, onlineThreshold) && !nodeOnline(b, onlineThreshold)
+			}
+		}
+		if nodeStatus != "" && nodeStatus != "online" && nodeStatus != "offline" {
+			ErrorOutput(errInvalidNodeStatus, errInvalidNodeStatus.Error(), output)
+
+			return
+		}
+		if ephemeralOnly && persistentOnly {
+			ErrorOutput(errConflictingEphemeral, errConflictingEphemeral.Error(), output)
+
+			return
+		}
+		now := time.Now()
+		lastSeenBefore, err := parseLastSeen(lastSeenBeforeStr, now)
+		if err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+		lastSeenAfter, err := parseLastSeen(lastSeenAfterStr, now)
+		if err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+
+		cfg, err := headscale.GetHeadscaleConfig()
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Failed to load configuration: %s", err), output)
+
+			return
+		}
+		hideKeys := cfg.CLI.HideKeysByDefault && !showKeys
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		if namespace != "" {
+			if err := checkNamespaceExists(ctx, client, namespace); err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+					output,
+				)
+
+				return
+			}
+		}
+
+		request := &v1.ListMachinesRequest{
+			Namespace:     namespace,
+			Deleted:       deleted,
+			LabelSelector: selector,
+		}
+
+		allMachines, err := listMachines(ctx, client, request, limit)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		// The prefix may be copied from the table, with its ellipsis.
+		keyPrefix = strings.TrimSuffix(keyPrefix, "…")
+		machines := filterNodes(allMachines, nodeListFilter{
+			pattern:          pattern,
+			status:           nodeStatus,
+			onlineThreshold:  onlineThreshold,
+			lastSeenBefore:   lastSeenBefore,
+			lastSeenAfter:    lastSeenAfter,
+			filters:          filters,
+			arch:             arch,
+			endpointContains: endpointContains,
+			keyPrefix:        keyPrefix,
+			tags:             tags,
+			ephemeralOnly:    ephemeralOnly,
+			persistentOnly:   persistentOnly,
+		})
+		if idleExpiring {
+			expiring := []*v1.Machine{}
+			for _, machine := range machines {
+				if _, ok := idleExpiryDeadline(machine); ok {
+					expiring = append(expiring, machine)
+				}
+			}
+			machines = expiring
+			sort.SliceStable(machines, func(i, j int) bool {
+				deadlineI, _ := idleExpiryDeadline(machines[i])
+				deadlineJ, _ := idleExpiryDeadline(machines[j])
+
+				return deadlineI.Before(deadlineJ)
+			})
+		}
+		// The nodes are listed by id, or by idle expiry with
+		// --idle-expiring, so the default sort keeps their order.
+		if sortBy != "id" || reverse {
+			sort.SliceStable(machines, func(i, j int) bool {
+				if reverse {
+					return nodeLess(machines[j], machines[i])
+				}
+
+				return nodeLess(machines[i], machines[j])
+			})
+		}
+
+		if output != "" && output != outputCSV {
+			if redactKeys {
+				for _, machine := range machines {
+					redactMachineKeys(machine)
+				}
+			}
+			SuccessOutput(machines, "", output)
+
+			return
+		}
+
+		var tableData pterm.TableData
+		if deleted {
+			tableData = deletedNodesToPtables(machines)
+		} else {
+			tableData, err = nodesTable(
+				cmd,
+				namespace,
+				hideKeys,
+				idleExpiring,
+				onlineThreshold,
+				machines,
+			)
+			if err != nil {
+				ErrorOutput(err, fmt.Sprintf("Error converting to table: %s", err), output)
+
+				return
+			}
+		}
+
+		if output == outputCSV {
+			err = writeCSV(os.Stdout, tableData)
+			if err != nil {
+				ErrorOutput(err, fmt.Sprintf("Failed to write CSV: %s", err), output)
+			}
+
+			return
+		}
+
+		if err := truncateTable(cmd, tableData); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+		if showSummary && !deleted {
+			tableData = append(tableData, nodesSummaryRow(tableData[0], machines, onlineThreshold))
+		}
+		alignTable(tableData, nodeColumnAlignments)
+
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		if showLegend && !deleted {
+			printLegend(nodesLegend)
+		}
+	},
+}
+
+var pendingNodesCmd = &cobra.Command{
+	Use:   "pending",
+	Short: "List the nodes waiting for an approval to be registered",
+	Long: "List the nodes authenticated with OIDC waiting for an approval, " +
+		"when oidc.require_approval is enabled.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ListPendingRegistrations(
+			ctx,
+			&v1.ListPendingRegistrationsRequest{},
+		)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get pending nodes: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.Registrations, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{
+			{"Machine key", "Hostname", "Email", "Namespace", "Requested"},
+		}
+		for _, registration := range response.GetRegistrations() {
+			tableData = append(tableData, []string{
+				registration.GetMachineKey(),
+				registration.GetHostname(),
+				registration.GetEmail(),
+				registration.GetNamespace(),
+				registration.GetRequestedAt().AsTime().Format(HeadscaleDateTimeFormat),
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+var approveNodeCmd = &cobra.Command{
+	Use:   "approve",
+	Short: "Approve the registration of a pending node",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		machineKey, _ := cmd.Flags().GetString("key")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.ApproveRegistrationRequest{
+			MachineKey: machineKey,
+		}
+
+		response, err := client.ApproveRegistration(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot approve node: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(
+			response.Machine,
+			fmt.Sprintf(
+				"Machine %s registered in namespace %s",
+				response.Machine.GetGivenName(),
+				response.Machine.GetNamespace().GetName(),
+			),
+			output,
+		)
+	},
+}
+
+var expireNodeCmd = &cobra.Command{
+	Use:   "expire",
+	Short: "Expire (log out) a machine in your network",
+	Long: "Expiring a node will keep the node in the database and force it to reauthenticate. " +
+		"With --older-than, every node registered longer ago than the given age is expired, " +
+		"to enforce the rotation of the keys. With --namespace, every node of the namespace " +
+		"is expired, after a confirmation unless --force is set.",
+	Aliases: []string{"logout", "exp", "e"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		if cmd.Flags().Changed("older-than") || cmd.Flags().Changed("namespace") {
+			expireNodesInBulk(cmd)
+
+			return
+		}
+
+		if cmd.Flags().Changed("selector") {
+			runOnSelectedNodes(cmd, "expire", "expired", true, func(
+				ctx context.Context,
+				client v1.HeadscaleServiceClient,
+				machine *v1.Machine,
+			) error {
+				_, err := client.ExpireMachine(
+					ctx,
+					&v1.ExpireMachineRequest{MachineId: machine.GetId()},
+				)
+
+				return err
+			})
+
+			return
+		}
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+		if identifier == 0 {
+			missingNodeTarget(output)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.ExpireMachineRequest{
+			MachineId: identifier,
+		}
+
+		response, err := client.ExpireMachine(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot expire machine: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response.Machine, "Machine expired", output)
+	},
+}
+
+// expireNodesInBulk expires the nodes of the --namespace of the command,
+// or of every namespace, registered before its --older-than age when set.
+// The nodes already expired are skipped.
+func expireNodesInBulk(cmd *cobra.Command) {
+	output, _ := cmd.Flags().GetString("output")
+	namespace, _ := cmd.Flags().GetString("namespace")
+	olderThanStr, _ := cmd.Flags().GetString("older-than")
+
+	if cmd.Flags().Changed("identifier") || cmd.Flags().Changed("selector") {
+		ErrorOutput(
+			errConflictingAge,
+			errConflictingAge.Error(),
+			output,
+		)
 
-	expireNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
-	err = expireNodeCmd.MarkFlagRequired("identifier")
-	if err != nil {
-		log.Fatalf(err.Error())
+		return
 	}
-	nodeCmd.AddCommand(expireNodeCmd)
+	if namespace == "" && olderThanStr == "" {
+		ErrorOutput(
+			errMissingParameter,
+			"Please specify a namespace or an age to expire nodes in bulk",
+			output,
+		)
 
-	renameNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
-	err = renameNodeCmd.MarkFlagRequired("identifier")
-	if err != nil {
-		log.Fatalf(err.Error())
+		return
 	}
-	nodeCmd.AddCommand(renameNodeCmd)
 
-	deleteNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
-	err = deleteNodeCmd.MarkFlagRequired("identifier")
-	if err != nil {
-		log.Fatalf(err.Error())
+	registeredBefore := time.Now()
+	if olderThanStr != "" {
+		olderThan, err := model.ParseDuration(olderThanStr)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Could not parse duration: %s\n", err),
+				output,
+			)
+
+			return
+		}
+		registeredBefore = registeredBefore.Add(-time.Duration(olderThan))
 	}
-	nodeCmd.AddCommand(deleteNodeCmd)
 
-	moveNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	ctx, client, conn, cancel := getHeadscaleCLIClient()
+	defer cancel()
+	defer conn.Close()
 
-	err = moveNodeCmd.MarkFlagRequired("identifier")
+	allMachines, err := listMachines(
+		ctx,
+		client,
+		&v1.ListMachinesRequest{Namespace: namespace},
+		0,
+	)
 	if err != nil {
-		log.Fatalf(err.Error())
+		ErrorOutput(
+			err,
+			fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+			output,
+		)
+
+		return
 	}
 
-	moveNodeCmd.Flags().StringP("namespace", "n", "", "New namespace")
+	machines := []*v1.Machine{}
+	for _, machine := range allMachines {
+		// Nodes already expired have to reauthenticate anyway.
+		if machine.GetExpiry() != nil &&
+			!machine.GetExpiry().AsTime().IsZero() &&
+			machine.GetExpiry().AsTime().Before(time.Now()) {
+			continue
+		}
 
-	err = moveNodeCmd.MarkFlagRequired("namespace")
-	if err != nil {
-		log.Fatalf(err.Error())
+		if machine.GetCreatedAt().AsTime().Before(registeredBefore) {
+			machines = append(machines, machine)
+		}
 	}
-	nodeCmd.AddCommand(moveNodeCmd)
 
-	tagCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	if len(machines) == 0 {
+		message := fmt.Sprintf("No node was registered more than %s ago", olderThanStr)
+		if olderThanStr == "" {
+			message = fmt.Sprintf("No node to expire in namespace %s", namespace)
+		}
+		SuccessOutput([]nodeResult{}, message, output)
 
-	err = tagCmd.MarkFlagRequired("identifier")
-	if err != nil {
-		log.Fatalf(err.Error())
+		return
 	}
-	tagCmd.Flags().
-		StringSliceP("tags", "t", []string{}, "List of tags to add to the node")
-	nodeCmd.AddCommand(tagCmd)
-}
 
-var nodeCmd = &cobra.Command{
-	Use:     "nodes",
-	Short:   "Manage the nodes of Headscale",
-	Aliases: []string{"node", "machine", "machines"},
+	runOnNodes(client, cmd, machines, "expire", "expired", true, func(
+		ctx context.Context,
+		client v1.HeadscaleServiceClient,
+		machine *v1.Machine,
+	) error {
+		_, err := client.ExpireMachine(
+			ctx,
+			&v1.ExpireMachineRequest{MachineId: machine.GetId()},
+		)
+
+		return err
+	})
 }
 
-var registerNodeCmd = &cobra.Command{
-	Use:   "register",
-	Short: "Registers a machine to your network",
+var quarantineNodeCmd = &cobra.Command{
+	Use:   "quarantine",
+	Short: "Isolate a machine from the network",
+	Long: "Quarantining a node keeps it registered and connected to headscale, " +
+		"but removes all its peers and hides it from the other nodes.",
 	Run: func(cmd *cobra.Command, args []string) {
 		output, _ := cmd.Flags().GetString("output")
-		namespace, err := cmd.Flags().GetString("namespace")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
 		if err != nil {
-			ErrorOutput(err, fmt.Sprintf("Error getting namespace: %s", err), output)
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
 
 			return
 		}
@@ -104,28 +1418,59 @@ var registerNodeCmd = &cobra.Command{
 		defer cancel()
 		defer conn.Close()
 
-		machineKey, err := cmd.Flags().GetString("key")
+		request := &v1.QuarantineMachineRequest{
+			MachineId: identifier,
+		}
+
+		response, err := client.QuarantineMachine(ctx, request)
 		if err != nil {
 			ErrorOutput(
 				err,
-				fmt.Sprintf("Error getting machine key from flag: %s", err),
+				fmt.Sprintf(
+					"Cannot quarantine machine: %s\n",
+					status.Convert(err).Message(),
+				),
 				output,
 			)
 
 			return
 		}
 
-		request := &v1.RegisterMachineRequest{
-			Key:       machineKey,
-			Namespace: namespace,
+		SuccessOutput(response.Machine, "Machine quarantined", output)
+	},
+}
+
+var unquarantineNodeCmd = &cobra.Command{
+	Use:   "unquarantine",
+	Short: "Lift the quarantine of a machine",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
 		}
 
-		response, err := client.RegisterMachine(ctx, request)
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.UnquarantineMachineRequest{
+			MachineId: identifier,
+		}
+
+		response, err := client.UnquarantineMachine(ctx, request)
 		if err != nil {
 			ErrorOutput(
 				err,
 				fmt.Sprintf(
-					"Cannot register machine: %s\n",
+					"Cannot unquarantine machine: %s\n",
 					status.Convert(err).Message(),
 				),
 				output,
@@ -134,25 +1479,97 @@ var registerNodeCmd = &cobra.Command{
 			return
 		}
 
-		SuccessOutput(response.Machine, "Machine register", output)
+		SuccessOutput(response.Machine, "Machine unquarantined", output)
 	},
 }
 
-var listNodesCmd = &cobra.Command{
-	Use:     "list",
-	Short:   "List nodes",
-	Aliases: []string{"ls", "show"},
+var restoreNodeCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a deleted node",
+	Long: "Restore a node deleted within the retention period (deleted_nodes_retention). " +
+		"Use 'headscale nodes list --deleted' to find the restorable nodes. " +
+		"A node whose name was taken in the meantime must have the other node renamed first.",
 	Run: func(cmd *cobra.Command, args []string) {
 		output, _ := cmd.Flags().GetString("output")
-		namespace, err := cmd.Flags().GetString("namespace")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
 		if err != nil {
-			ErrorOutput(err, fmt.Sprintf("Error getting namespace: %s", err), output)
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.RestoreMachineRequest{
+			MachineId: identifier,
+		}
+
+		response, err := client.RestoreMachine(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot restore machine: %s\n",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if response.GetIpAddressesChanged() {
+			SuccessOutput(
+				response.Machine,
+				fmt.Sprintf(
+					"Machine restored, its IP addresses were reallocated, new addresses: %s",
+					strings.Join(response.GetMachine().GetIpAddresses(), ", "),
+				),
+				output,
+			)
 
 			return
 		}
-		showTags, err := cmd.Flags().GetBool("tags")
+
+		SuccessOutput(response.Machine, "Machine restored", output)
+	},
+}
+
+var setDERPNodeCmd = &cobra.Command{
+	Use:   "set-derp",
+	Short: "Force the home DERP region of a node",
+	Long: "Ask a node to use a DERP region as its home instead of the closest one, " +
+		"by marking all the other regions as to be avoided in its DERP map. " +
+		"Clients ignoring this hint keep picking their region automatically.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
 		if err != nil {
-			ErrorOutput(err, fmt.Sprintf("Error getting tags flag: %s", err), output)
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		region, _ := cmd.Flags().GetInt("region")
+		clearRegion, _ := cmd.Flags().GetBool("clear")
+		if (region == 0) == !clearRegion {
+			ErrorOutput(
+				errMissingParameter,
+				"Please specify either --region or --clear",
+				output,
+			)
 
 			return
 		}
@@ -161,52 +1578,101 @@ var listNodesCmd = &cobra.Command{
 		defer cancel()
 		defer conn.Close()
 
-		request := &v1.ListMachinesRequest{
-			Namespace: namespace,
+		request := &v1.SetDERPRegionRequest{
+			MachineId: identifier,
+			RegionId:  int32(region),
 		}
 
-		response, err := client.ListMachines(ctx, request)
+		response, err := client.SetDERPRegion(ctx, request)
 		if err != nil {
 			ErrorOutput(
 				err,
-				fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+				fmt.Sprintf(
+					"Cannot set the DERP region of machine: %s\n",
+					status.Convert(err).Message(),
+				),
 				output,
 			)
 
 			return
 		}
 
-		if output != "" {
-			SuccessOutput(response.Machines, "", output)
+		if clearRegion {
+			SuccessOutput(response.Machine, "Machine DERP region is selected automatically", output)
 
 			return
 		}
 
-		tableData, err := nodesToPtables(namespace, showTags, response.Machines)
+		SuccessOutput(
+			response.Machine,
+			fmt.Sprintf("Machine DERP region forced to %d", region),
+			output,
+		)
+	},
+}
+
+var setExitOnlyNodeCmd = &cobra.Command{
+	Use:   "set-exit-only",
+	Short: "Restrict a node to be used as an exit node only",
+	Long: "An exit only node keeps forwarding the traffic of its peers to the " +
+		"internet and its subnet routes, but the peers cannot reach the node itself.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
 		if err != nil {
-			ErrorOutput(err, fmt.Sprintf("Error converting to table: %s", err), output)
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
 
 			return
 		}
 
-		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		disable, _ := cmd.Flags().GetBool("disable")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.SetExitNodeOnlyRequest{
+			MachineId:    identifier,
+			ExitNodeOnly: !disable,
+		}
+
+		response, err := client.SetExitNodeOnly(ctx, request)
 		if err != nil {
 			ErrorOutput(
 				err,
-				fmt.Sprintf("Failed to render pterm table: %s", err),
+				fmt.Sprintf(
+					"Cannot set exit only on machine: %s\n",
+					status.Convert(err).Message(),
+				),
 				output,
 			)
 
 			return
 		}
+
+		if disable {
+			SuccessOutput(response.Machine, "Machine is no longer exit only", output)
+
+			return
+		}
+
+		SuccessOutput(response.Machine, "Machine is now exit only", output)
 	},
 }
 
-var expireNodeCmd = &cobra.Command{
-	Use:     "expire",
-	Short:   "Expire (log out) a machine in your network",
-	Long:    "Expiring a node will keep the node in the database and force it to reauthenticate.",
-	Aliases: []string{"logout", "exp", "e"},
+var touchNodeCmd = &cobra.Command{
+	Use:   "touch",
+	Short: "Set the last seen time of a node to now (testing only)",
+	Long: `
+Set the last seen time of a node to now, as if it had just contacted headscale.
+This is an administration command meant to test and demonstrate the online
+status of the nodes without a real client, it should not be used in production.`,
+	Hidden: true,
 	Run: func(cmd *cobra.Command, args []string) {
 		output, _ := cmd.Flags().GetString("output")
 
@@ -221,20 +1687,31 @@ var expireNodeCmd = &cobra.Command{
 			return
 		}
 
+		confirmed, _ := cmd.Flags().GetBool("i-understand-this-is-for-testing")
+		if !confirmed {
+			ErrorOutput(
+				errMissingParameter,
+				"This command is for testing only, pass --i-understand-this-is-for-testing to run it",
+				output,
+			)
+
+			return
+		}
+
 		ctx, client, conn, cancel := getHeadscaleCLIClient()
 		defer cancel()
 		defer conn.Close()
 
-		request := &v1.ExpireMachineRequest{
+		request := &v1.TouchMachineRequest{
 			MachineId: identifier,
 		}
 
-		response, err := client.ExpireMachine(ctx, request)
+		response, err := client.TouchMachine(ctx, request)
 		if err != nil {
 			ErrorOutput(
 				err,
 				fmt.Sprintf(
-					"Cannot expire machine: %s\n",
+					"Cannot touch machine: %s\n",
 					status.Convert(err).Message(),
 				),
 				output,
@@ -243,13 +1720,24 @@ var expireNodeCmd = &cobra.Command{
 			return
 		}
 
-		SuccessOutput(response.Machine, "Machine expired", output)
+		SuccessOutput(response.Machine, "Machine last seen time updated", output)
 	},
 }
 
 var renameNodeCmd = &cobra.Command{
 	Use:   "rename NEW_NAME",
 	Short: "Renames a machine in your network",
+	Long: `
+Change the name of a node, used in its MagicDNS name. The new name must be
+a DNS label (up to 63 lowercase letters, digits and hyphens) not used by
+another node of the namespace.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		output, _ := cmd.Flags().GetString("output")
 
@@ -264,14 +1752,23 @@ var renameNodeCmd = &cobra.Command{
 			return
 		}
 
+		newName := args[0]
+		if err := headscale.CheckForDNSLabelRules(newName); err != nil {
+			ErrorOutput(err, fmt.Sprintf("Cannot rename machine: %s", err), output)
+
+			return
+		}
+
 		ctx, client, conn, cancel := getHeadscaleCLIClient()
 		defer cancel()
 		defer conn.Close()
 
-		newName := ""
-		if len(args) > 0 {
-			newName = args[0]
+		if err := checkManagedNodeByID(ctx, client, identifier, forceExecution(cmd)); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
 		}
+
 		request := &v1.RenameMachineRequest{
 			MachineId: identifier,
 			NewName:   newName,
@@ -302,6 +1799,23 @@ var deleteNodeCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		output, _ := cmd.Flags().GetString("output")
 
+		if cmd.Flags().Changed("selector") {
+			runOnSelectedNodes(cmd, "delete", "deleted", true, func(
+				ctx context.Context,
+				client v1.HeadscaleServiceClient,
+				machine *v1.Machine,
+			) error {
+				_, err := client.DeleteMachine(
+					ctx,
+					&v1.DeleteMachineRequest{MachineId: machine.GetId()},
+				)
+
+				return err
+			})
+
+			return
+		}
+
 		identifier, err := cmd.Flags().GetUint64("identifier")
 		if err != nil {
 			ErrorOutput(
@@ -312,6 +1826,11 @@ var deleteNodeCmd = &cobra.Command{
 
 			return
 		}
+		if identifier == 0 {
+			missingNodeTarget(output)
+
+			return
+		}
 
 		ctx, client, conn, cancel := getHeadscaleCLIClient()
 		defer cancel()
@@ -340,7 +1859,7 @@ var deleteNodeCmd = &cobra.Command{
 		}
 
 		confirm := false
-		force, _ := cmd.Flags().GetBool("force")
+		force := forceExecution(cmd)
 		if !force {
 			prompt := &survey.Confirm{
 				Message: fmt.Sprintf(
@@ -350,6 +1869,8 @@ var deleteNodeCmd = &cobra.Command{
 			}
 			err = survey.AskOne(prompt, &confirm)
 			if err != nil {
+				ErrorOutput(err, fmt.Sprintf("Error reading the confirmation: %s", err), output)
+
 				return
 			}
 		}
@@ -378,40 +1899,219 @@ var deleteNodeCmd = &cobra.Command{
 				"Node deleted",
 				output,
 			)
-		} else {
-			SuccessOutput(map[string]string{"Result": "Node not deleted"}, "Node not deleted", output)
+		} else {
+			SuccessOutput(map[string]string{"Result": "Node not deleted"}, "Node not deleted", output)
+		}
+	},
+}
+
+// moveNodeOperation moves a node to namespace, for the moves of several
+// nodes.
+func moveNodeOperation(namespace string, force bool) nodeOperation {
+	return func(
+		ctx context.Context,
+		client v1.HeadscaleServiceClient,
+		machine *v1.Machine,
+	) error {
+		if err := checkManagedNode(machine, force); err != nil {
+			return err
+		}
+
+		_, err := client.MoveMachine(
+			ctx,
+			&v1.MoveMachineRequest{
+				MachineId: machine.GetId(),
+				Namespace: namespace,
+			},
+		)
+
+		return err
+	}
+}
+
+// uniqueIdentifiers is identifiers without the repeated ones, in the order
+// they were first given, so that a node repeated with --identifier is only
+// changed once.
+func uniqueIdentifiers(identifiers []uint) []uint {
+	seen := make(map[uint]bool, len(identifiers))
+	unique := make([]uint, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		if !seen[identifier] {
+			seen[identifier] = true
+			unique = append(unique, identifier)
+		}
+	}
+
+	return unique
+}
+
+// moveNodesByID moves the nodes of the repeated --identifier of nodes move
+// to namespace. They are all looked up before any is moved.
+func moveNodesByID(cmd *cobra.Command, identifiers []uint, namespace string) {
+	output, _ := cmd.Flags().GetString("output")
+
+	ctx, client, conn, cancel := getHeadscaleCLIClient()
+	defer cancel()
+	defer conn.Close()
+
+	machines := make([]*v1.Machine, len(identifiers))
+	for index, identifier := range identifiers {
+		response, err := client.GetMachine(
+			ctx,
+			&v1.GetMachineRequest{MachineId: uint64(identifier)},
+		)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Error getting node %d: %s",
+					identifier,
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
 		}
-	},
+		machines[index] = response.GetMachine()
+	}
+
+	runOnNodes(
+		client,
+		cmd,
+		machines,
+		"move",
+		"moved",
+		true,
+		moveNodeOperation(namespace, forceExecution(cmd)),
+	)
+}
+
+// moveNamespaceNodes moves the nodes of the --from-namespace of nodes move
+// to namespace. The nodes shared into the namespace stay in their own.
+func moveNamespaceNodes(cmd *cobra.Command, namespace string) {
+	output, _ := cmd.Flags().GetString("output")
+	fromNamespace, _ := cmd.Flags().GetString("from-namespace")
+
+	ctx, client, conn, cancel := getHeadscaleCLIClient()
+	defer cancel()
+	defer conn.Close()
+
+	allMachines, err := listMachines(
+		ctx,
+		client,
+		&v1.ListMachinesRequest{Namespace: fromNamespace},
+		0,
+	)
+	if err != nil {
+		ErrorOutput(
+			err,
+			fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+			output,
+		)
+
+		return
+	}
+
+	machines := []*v1.Machine{}
+	for _, machine := range allMachines {
+		if machine.GetNamespace().GetName() == fromNamespace {
+			machines = append(machines, machine)
+		}
+	}
+	if len(machines) == 0 {
+		SuccessOutput(
+			[]nodeResult{},
+			fmt.Sprintf("No node in namespace %s", fromNamespace),
+			output,
+		)
+
+		return
+	}
+
+	runOnNodes(
+		client,
+		cmd,
+		machines,
+		"move",
+		"moved",
+		true,
+		moveNodeOperation(namespace, forceExecution(cmd)),
+	)
 }
 
 var moveNodeCmd = &cobra.Command{
-	Use:     "move",
-	Short:   "Move node to another namespace",
+	Use:   "move",
+	Short: "Move node to another namespace",
+	Long: `
+Move a node to another namespace, after a confirmation showing the node and
+its current and new namespaces, as the move changes the ACL rules applying
+to the node. --force skips the confirmation, for the scripts.
+
+Several nodes are moved by repeating --identifier, with --selector, or with
+--from-namespace, which moves all the nodes of a namespace to --namespace.
+They are confirmed together and a node failing to move does not stop the
+others.`,
 	Aliases: []string{"mv"},
 	Run: func(cmd *cobra.Command, args []string) {
 		output, _ := cmd.Flags().GetString("output")
 
-		identifier, err := cmd.Flags().GetUint64("identifier")
+		namespace, err := cmd.Flags().GetString("namespace")
 		if err != nil {
 			ErrorOutput(
 				err,
-				fmt.Sprintf("Error converting ID to integer: %s", err),
+				fmt.Sprintf("Error getting namespace: %s", err),
 				output,
 			)
 
 			return
 		}
 
-		namespace, err := cmd.Flags().GetString("namespace")
+		if cmd.Flags().Changed("from-namespace") {
+			if cmd.Flags().Changed("identifier") || cmd.Flags().Changed("selector") {
+				ErrorOutput(errConflictingFromNS, errConflictingFromNS.Error(), output)
+
+				return
+			}
+			moveNamespaceNodes(cmd, namespace)
+
+			return
+		}
+
+		if cmd.Flags().Changed("selector") {
+			runOnSelectedNodes(
+				cmd,
+				"move",
+				"moved",
+				true,
+				moveNodeOperation(namespace, forceExecution(cmd)),
+			)
+
+			return
+		}
+
+		identifiers, err := cmd.Flags().GetUintSlice("identifier")
 		if err != nil {
 			ErrorOutput(
 				err,
-				fmt.Sprintf("Error getting namespace: %s", err),
+				fmt.Sprintf("Error converting ID to integer: %s", err),
 				output,
 			)
 
 			return
 		}
+		identifiers = uniqueIdentifiers(identifiers)
+		if len(identifiers) > 1 {
+			moveNodesByID(cmd, identifiers, namespace)
+
+			return
+		}
+		if len(identifiers) == 0 || identifiers[0] == 0 {
+			missingNodeTarget(output)
+
+			return
+		}
+		identifier := uint64(identifiers[0])
 
 		ctx, client, conn, cancel := getHeadscaleCLIClient()
 		defer cancel()
@@ -421,7 +2121,7 @@ var moveNodeCmd = &cobra.Command{
 			MachineId: identifier,
 		}
 
-		_, err = client.GetMachine(ctx, getRequest)
+		getResponse, err := client.GetMachine(ctx, getRequest)
 		if err != nil {
 			ErrorOutput(
 				err,
@@ -434,6 +2134,37 @@ var moveNodeCmd = &cobra.Command{
 
 			return
 		}
+		force := forceExecution(cmd)
+		if err := checkManagedNode(getResponse.GetMachine(), force); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+
+		// The ACL rules of the namespaces differ, so a move can change
+		// what the node reaches and what reaches it.
+		if !force {
+			confirm := false
+			prompt := &survey.Confirm{
+				Message: fmt.Sprintf(
+					"Do you want to move the node %s from namespace %s to %s?",
+					getResponse.GetMachine().GetGivenName(),
+					getResponse.GetMachine().GetNamespace().GetName(),
+					namespace,
+				),
+			}
+			err = survey.AskOne(prompt, &confirm)
+			if err != nil {
+				ErrorOutput(err, fmt.Sprintf("Error reading the confirmation: %s", err), output)
+
+				return
+			}
+			if !confirm {
+				SuccessOutput(map[string]string{"Result": "Node not moved"}, "Node not moved", output)
+
+				return
+			}
+		}
 
 		moveRequest := &v1.MoveMachineRequest{
 			MachineId: identifier,
@@ -458,9 +2189,282 @@ var moveNodeCmd = &cobra.Command{
 	},
 }
 
+func deletedNodesToPtables(machines []*v1.Machine) pterm.TableData {
+	tableData := pterm.TableData{
+		{"ID", "Hostname", "Name", "Namespace", "IP addresses", "Deleted"},
+	}
+	for _, machine := range machines {
+		tableData = append(tableData, []string{
+			strconv.FormatUint(machine.GetId(), headscale.Base10),
+			machine.GetName(),
+			machine.GetGivenName(),
+			machine.GetNamespace().GetName(),
+			strings.Join(machine.GetIpAddresses(), ", "),
+			machine.GetDeletedAt().AsTime().Format(HeadscaleDateTimeFormat),
+		})
+	}
+
+	return tableData
+}
+
+// readMachineKeyFromStdin reads the key of nodes register --key -, without
+// the surrounding whitespace and newlines.
+func readMachineKeyFromStdin() (string, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+
+	machineKey := strings.TrimSpace(string(data))
+	if machineKey == "" {
+		return "", errEmptyMachineKey
+	}
+
+	return machineKey, nil
+}
+
+// listMachines fetches the machines of request page by page, in the order
+// of their IDs, stopping after limit machines when it is not 0.
+func listMachines(
+	ctx context.Context,
+	client v1.HeadscaleServiceClient,
+	request *v1.ListMachinesRequest,
+	limit int,
+) ([]*v1.Machine, error) {
+	machines := []*v1.Machine{}
+	request.PageSize = machinesPageSize
+	for {
+		response, err := client.ListMachines(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		machines = append(machines, response.GetMachines()...)
+
+		if limit > 0 && len(machines) >= limit {
+			return machines[:limit], nil
+		}
+		// The servers without paging return all the machines at once,
+		// without a next page.
+		if response.GetNextPageToken() == "" {
+			return machines, nil
+		}
+		request.PageToken = response.GetNextPageToken()
+	}
+}
+
+// nodeOnline tells if the node was seen during the last threshold. The
+// API has no online status, the last seen time is the only signal.
+func nodeOnline(machine *v1.Machine, threshold time.Duration) bool {
+	return machine.GetLastSeen() != nil &&
+		machine.GetLastSeen().AsTime().After(time.Now().Add(-threshold))
+}
+
+// redactMachineKeys masks the public keys and the pre-auth key of machine,
+// keeping the beginning of the keys to tell them apart.
+func redactMachineKeys(machine *v1.Machine) {
+	machine.MachineKey = redactKey(machine.GetMachineKey(), machineKeyPrefix)
+	machine.NodeKey = redactKey(machine.GetNodeKey(), nodeKeyPrefix)
+	machine.DiscoKey = redactKey(machine.GetDiscoKey(), discoKeyPrefix)
+	if machine.GetPreAuthKey() != nil {
+		machine.PreAuthKey.Key = redactKey(machine.GetPreAuthKey().GetKey(), "")
+	}
+}
+
+// authKeyPrefix shows the beginning of the pre-auth key a node was
+// registered with, or a dash when it was registered another way.
+func authKeyPrefix(machine *v1.Machine) string {
+	key := machine.GetPreAuthKey().GetKey()
+	if key == "" {
+		return "-"
+	}
+	if len(key) > authKeyPrefixLength {
+		key = key[:authKeyPrefixLength]
+	}
+
+	return key + "…"
+}
+
+func redactKey(key string, prefix string) string {
+	if key == "" {
+		return ""
+	}
+
+	key = strings.TrimPrefix(key, prefix)
+	if len(key) > redactedKeyLength {
+		key = key[:redactedKeyLength]
+	}
+
+	return prefix + key + "…"
+}
+
+// valueOrDash shows the values not reported by a client as a dash.
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+// clockSkewString shows the clock offset of a machine with its sign, or a
+// dash when the machine does not report its time.
+func clockSkewString(machine *v1.Machine) string {
+	if machine.GetClockSkew() == nil {
+		return "-"
+	}
+
+	skew := machine.GetClockSkew().AsDuration()
+	if skew >= 0 {
+		return "+" + skew.String()
+	}
+
+	return skew.String()
+}
+
+// expiryKeyWarning is how long before the key of a node expires its
+// expiry is highlighted.
+const expiryKeyWarning = 24 * time.Hour
+
+// expiryString formats the expiry of the key of a node, in yellow when it
+// expires soon and in red when it expired.
+func expiryString(expiry time.Time) string {
+	if expiry.IsZero() {
+		return "never"
+	}
+
+	expiryStr := expiry.Format(HeadscaleDateTimeFormat)
+	switch untilExpiry := time.Until(expiry); {
+	case untilExpiry <= 0:
+		return pterm.LightRed(expiryStr)
+	case untilExpiry <= expiryKeyWarning:
+		return pterm.LightYellow(expiryStr)
+	}
+
+	return expiryStr
+}
+
+const (
+	// lastSeenRecent and lastSeenStale are how long after it was last
+	// seen a node is shown in green, then in yellow by --relative-time.
+	lastSeenRecent = 5 * time.Minute
+	lastSeenStale  = time.Hour
+)
+
+// relativeTimeString formats when a node was last seen relative to now,
+// like 3m ago, in green when recent and in red when stale.
+func relativeTimeString(lastSeen time.Time) string {
+	const day = 24 * time.Hour
+	since := time.Since(lastSeen)
+
+	var relative string
+	switch {
+	case since < time.Minute:
+		relative = fmt.Sprintf("%ds ago", int(since.Seconds()))
+	case since < time.Hour:
+		relative = fmt.Sprintf("%dm ago", int(since.Minutes()))
+	case since < day:
+		relative = fmt.Sprintf("%dh ago", int(since.Hours()))
+	default:
+		relative = fmt.Sprintf("%dd ago", int(since/day))
+	}
+
+	switch {
+	case since < lastSeenRecent:
+		return pterm.LightGreen(relative)
+	case since < lastSeenStale:
+		return pterm.LightYellow(relative)
+	}
+
+	return pterm.LightRed(relative)
+}
+
+// joinNonEmpty joins the values which are not empty, so a missing value
+// does not leave a stray separator.
+func joinNonEmpty(values ...string) string {
+	nonEmpty := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+
+	return strings.Join(nonEmpty, ", ")
+}
+
+// derpRegionString is the home DERP region of a node, with its code when
+// showName is set, and the region forced by the administrators in yellow.
+func derpRegionString(machine *v1.Machine, showName bool) string {
+	derpRegion := "-"
+	if machine.GetPreferredDerpRegion() != 0 {
+		derpRegion = strconv.Itoa(int(machine.GetPreferredDerpRegion()))
+		if showName && machine.GetPreferredDerpRegionCode() != "" {
+			derpRegion = fmt.Sprintf("%s (%s)", machine.GetPreferredDerpRegionCode(), derpRegion)
+		}
+	}
+	if machine.GetForcedDerpRegion() != 0 {
+		derpRegion = pterm.LightYellow(
+			fmt.Sprintf("%s (forced %d)", derpRegion, machine.GetForcedDerpRegion()),
+		)
+	}
+
+	return derpRegion
+}
+
+// idleExpiryDeadline returns when a machine will be expired for being idle,
+// false if its namespace has no idle expiry or if it is already expired.
+// The machine is idle since it was last seen, or since its registration.
+func idleExpiryDeadline(machine *v1.Machine) (time.Time, bool) {
+	idleExpiry := machine.GetNamespace().GetIdleExpiry()
+	if idleExpiry == nil || idleExpiry.AsDuration() <= 0 {
+		return time.Time{}, false
+	}
+	if machine.GetExpiry() != nil && !machine.GetExpiry().AsTime().IsZero() &&
+		machine.GetExpiry().AsTime().Before(time.Now()) {
+		return time.Time{}, false
+	}
+
+	idleSince := machine.GetCreatedAt().AsTime()
+	if machine.GetLastSeen() != nil && machine.GetLastSeen().AsTime().After(idleSince) {
+		idleSince = machine.GetLastSeen().AsTime()
+	}
+
+	return idleSince.Add(idleExpiry.AsDuration()), true
+}
+
+// nodeTableOptions are the options of the table of nodesToPtables, the
+// columns it shows and how.
+type nodeTableOptions struct {
+	// currentNamespace is the namespace listed, the nodes of the others
+	// being shared into it.
+	currentNamespace string
+	hideKeys         bool
+	fullKeys         bool
+	relativeTime     bool
+	onlineThreshold  time.Duration
+
+	showAuthKey     bool
+	showQuarantined bool
+	showManaged     bool
+	showDERP        bool
+	// showDERPNames shows the DERP column, like showDERP, with the codes
+	// of the regions.
+	showDERPNames     bool
+	showFlaps         bool
+	showTags          bool
+	showAck           bool
+	showIdleExpiry    bool
+	showInventory     bool
+	showClockSkew     bool
+	showEndpoints     bool
+	showPTR           bool
+	showIPColumns     bool
+	showExpiry        bool
+	showShared        bool
+	showRoutesUpdated bool
+}
+
 func nodesToPtables(
-	currentNamespace string,
-	showTags bool,
+	options nodeTableOptions,
 	machines []*v1.Machine,
 ) (pterm.TableData, error) {
 	tableHeader := []string{
@@ -475,26 +2479,77 @@ func nodesToPtables(
 		"Online",
 		"Expired",
 	}
-	if showTags {
+	if options.showAuthKey {
+		tableHeader = append(tableHeader, "Auth key")
+	}
+	if options.showQuarantined {
+		tableHeader = append(tableHeader, "Quarantined")
+	}
+	if options.showManaged {
+		tableHeader = append(tableHeader, "Managed")
+	}
+	if options.showDERP || options.showDERPNames {
+		tableHeader = append(tableHeader, "DERP")
+	}
+	if options.showFlaps {
+		tableHeader = append(tableHeader, "Flaps")
+	}
+	if options.showTags {
 		tableHeader = append(tableHeader, []string{
 			"ForcedTags",
 			"InvalidTags",
 			"ValidTags",
 		}...)
 	}
+	if options.showAck {
+		tableHeader = append(tableHeader, "Last ack", "Netmap")
+	}
+	if options.showIdleExpiry {
+		tableHeader = append(tableHeader, "Idle expiry")
+	}
+	if options.showInventory {
+		tableHeader = append(
+			tableHeader,
+			"OS",
+			"OS version",
+			"Arch",
+			"Device",
+			"Package",
+			"Client version",
+		)
+	}
+	if options.showClockSkew {
+		tableHeader = append(tableHeader, "Clock skew")
+	}
+	if options.showEndpoints {
+		tableHeader = append(tableHeader, "Endpoints")
+	}
+	if options.showPTR {
+		tableHeader = append(tableHeader, "PTR names")
+	}
+	if options.showIPColumns {
+		tableHeader = append(tableHeader, "IPv4", "IPv6")
+	}
+	if options.showExpiry {
+		tableHeader = append(tableHeader, "Expiry")
+	}
+	if options.showShared {
+		tableHeader = append(tableHeader, "Shared")
+	}
+	if options.showRoutesUpdated {
+		tableHeader = append(tableHeader, "Routes updated")
+	}
 	tableData := pterm.TableData{tableHeader}
 
 	for _, machine := range machines {
-		var ephemeral bool
-		if machine.PreAuthKey != nil && machine.PreAuthKey.Ephemeral {
-			ephemeral = true
-		}
-
 		var lastSeen time.Time
 		var lastSeenTime string
 		if machine.LastSeen != nil {
 			lastSeen = machine.LastSeen.AsTime()
 			lastSeenTime = lastSeen.Format("2006-01-02 15:04:05")
+			if options.relativeTime {
+				lastSeenTime = relativeTimeString(lastSeen)
+			}
 		}
 
 		var expiry time.Time
@@ -511,9 +2566,7 @@ func nodesToPtables(
 		}
 
 		var online string
-		if lastSeen.After(
-			time.Now().Add(-5 * time.Minute),
-		) { // TODO: Find a better way to reliably show if online
+		if nodeOnline(machine, options.onlineThreshold) {
 			online = pterm.LightGreen("online")
 		} else {
 			online = pterm.LightRed("offline")
@@ -526,6 +2579,13 @@ func nodesToPtables(
 			expired = pterm.LightRed("yes")
 		}
 
+		var quarantined string
+		if machine.GetQuarantined() {
+			quarantined = pterm.LightRed("yes")
+		} else {
+			quarantined = pterm.LightGreen("no")
+		}
+
 		var forcedTags string
 		for _, tag := range machine.ForcedTags {
 			forcedTags += "," + tag
@@ -547,38 +2607,132 @@ func nodesToPtables(
 		validTags = strings.TrimLeft(validTags, ",")
 
 		var namespace string
-		if currentNamespace == "" || (currentNamespace == machine.Namespace.Name) {
-			namespace = pterm.LightMagenta(machine.Namespace.Name)
-		} else {
+		shared := options.currentNamespace != "" && options.currentNamespace != machine.Namespace.Name
+		if shared {
 			// Shared into this namespace
 			namespace = pterm.LightYellow(machine.Namespace.Name)
+		} else {
+			namespace = pterm.LightMagenta(machine.Namespace.Name)
 		}
 
 		var IPV4Address string
 		var IPV6Address string
+		// A malformed address is flagged rather than failing the whole
+		// table.
+		var invalidAddresses []string
 		for _, addr := range machine.IpAddresses {
-			if netaddr.MustParseIP(addr).Is4() {
+			ip, err := netaddr.ParseIP(addr)
+			switch {
+			case err != nil:
+				invalidAddresses = append(invalidAddresses, pterm.LightRed(fmt.Sprintf("%q (invalid)", addr)))
+			case ip.Is4():
 				IPV4Address = addr
-			} else {
+			default:
 				IPV6Address = addr
 			}
 		}
 
+		// The short form of two keys can be the same.
+		var nodeKeyStr string
+		switch {
+		case options.hideKeys:
+			nodeKeyStr = redactKey(machine.NodeKey, nodeKeyPrefix)
+		case options.fullKeys:
+			nodeKeyStr = nodeKey.String()
+		default:
+			nodeKeyStr = nodeKey.ShortString()
+		}
+
 		nodeData := []string{
 			strconv.FormatUint(machine.Id, headscale.Base10),
 			machine.Name,
 			machine.GetGivenName(),
-			nodeKey.ShortString(),
+			nodeKeyStr,
 			namespace,
-			strings.Join([]string{IPV4Address, IPV6Address}, ", "),
-			strconv.FormatBool(ephemeral),
+			joinNonEmpty(append([]string{IPV4Address, IPV6Address}, invalidAddresses...)...),
+			strconv.FormatBool(machine.GetEphemeral()),
 			lastSeenTime,
 			online,
 			expired,
 		}
-		if showTags {
+		if options.showAuthKey {
+			nodeData = append(nodeData, authKeyPrefix(machine))
+		}
+		if options.showQuarantined {
+			nodeData = append(nodeData, quarantined)
+		}
+		if options.showManaged {
+			nodeData = append(nodeData, strconv.FormatBool(machine.GetManaged()))
+		}
+		if options.showDERP || options.showDERPNames {
+			nodeData = append(nodeData, derpRegionString(machine, options.showDERPNames))
+		}
+		if options.showFlaps {
+			nodeData = append(nodeData, strconv.FormatUint(uint64(machine.GetFlapCount()), headscale.Base10))
+		}
+		if options.showTags {
 			nodeData = append(nodeData, []string{forcedTags, invalidTags, validTags}...)
 		}
+		if options.showAck {
+			var lastAck string
+			if machine.GetLastSuccessfulUpdate() != nil {
+				lastAck = machine.GetLastSuccessfulUpdate().AsTime().Format(HeadscaleDateTimeFormat)
+			}
+
+			netmap := pterm.LightRed("outdated")
+			if machine.GetNetmapUpToDate() {
+				netmap = pterm.LightGreen("up to date")
+			}
+
+			nodeData = append(nodeData, lastAck, netmap)
+		}
+		if options.showIdleExpiry {
+			deadline, _ := idleExpiryDeadline(machine)
+			nodeData = append(nodeData, deadline.Format(HeadscaleDateTimeFormat))
+		}
+		if options.showInventory {
+			nodeData = append(
+				nodeData,
+				valueOrDash(machine.GetOs()),
+				valueOrDash(machine.GetOsVersion()),
+				valueOrDash(machine.GetArch()),
+				valueOrDash(machine.GetDeviceModel()),
+				valueOrDash(machine.GetClientPackage()),
+				valueOrDash(machine.GetClientVersion()),
+			)
+		}
+		if options.showClockSkew {
+			clockSkew := clockSkewString(machine)
+			if machine.GetClockSkewed() {
+				clockSkew = pterm.LightRed(clockSkew)
+			}
+			nodeData = append(nodeData, clockSkew)
+		}
+		if options.showEndpoints {
+			nodeData = append(nodeData, valueOrDash(strings.Join(machine.GetEndpoints(), ", ")))
+		}
+		if options.showPTR {
+			nodeData = append(nodeData, valueOrDash(strings.Join(machine.GetPtrNames(), ", ")))
+		}
+		if options.showIPColumns {
+			nodeData = append(nodeData, IPV4Address, IPV6Address)
+		}
+		if options.showExpiry {
+			nodeData = append(nodeData, expiryString(expiry))
+		}
+		if options.showShared {
+			nodeData = append(nodeData, strconv.FormatBool(shared))
+		}
+		if options.showRoutesUpdated {
+			var routesUpdated string
+			if machine.GetRoutesUpdated() != nil {
+				routesUpdated = machine.GetRoutesUpdated().AsTime().Format("2006-01-02 15:04:05")
+				if options.relativeTime {
+					routesUpdated = relativeTimeString(machine.GetRoutesUpdated().AsTime())
+				}
+			}
+			nodeData = append(nodeData, routesUpdated)
+		}
 		tableData = append(
 			tableData,
 			nodeData,
@@ -588,37 +2742,131 @@ func nodesToPtables(
 	return tableData, nil
 }
 
+// nodeSorts are the orders of nodes list --sort. The times are compared
+// rather than their formatting, the nodes never seen come first and the
+// ones which never expire last.
+var nodeSorts = map[string]func(a, b *v1.Machine) bool{
+	"id": func(a, b *v1.Machine) bool {
+		return a.GetId() < b.GetId()
+	},
+	"name": func(a, b *v1.Machine) bool {
+		return a.GetGivenName() < b.GetGivenName()
+	},
+	"hostname": func(a, b *v1.Machine) bool {
+		return a.GetName() < b.GetName()
+	},
+	"namespace": func(a, b *v1.Machine) bool {
+		return a.GetNamespace().GetName() < b.GetNamespace().GetName()
+	},
+	"last_seen": func(a, b *v1.Machine) bool {
+		var lastSeenA, lastSeenB time.Time
+		if a.GetLastSeen() != nil {
+			lastSeenA = a.GetLastSeen().AsTime()
+		}
+		if b.GetLastSeen() != nil {
+			lastSeenB = b.GetLastSeen().AsTime()
+		}
+
+		return lastSeenA.Before(lastSeenB)
+	},
+	"expired": func(a, b *v1.Machine) bool {
+		expiryA, expiryB := a.GetExpiry().AsTime(), b.GetExpiry().AsTime()
+		switch {
+		case a.GetExpiry() == nil || expiryA.IsZero():
+			return false
+		case b.GetExpiry() == nil || expiryB.IsZero():
+			return true
+		}
+
+		return expiryA.Before(expiryB)
+	},
+	"online": func(a, b *v1.Machine) bool {
+		return nodeOnline(a, defaultOnlineThreshold) && !nodeOnline(b, defaultOnlineThreshold)
+	},
+	"flaps": func(a, b *v1.Machine) bool {
+		return a.GetFlapCount() > b.GetFlapCount()
+	},
+}
+
 var tagCmd = &cobra.Command{
-	Use:     "tag",
-	Short:   "Manage the tags of a node",
+	Use:   "tag",
+	Short: "Manage the tags of a node",
+	Long: `
+Set the tags forced on a node, replacing the forced ones; an empty --tags
+clears them. Every tag must start with 'tag:'. 'headscale nodes untag'
+removes some forced tags and keeps the others.`,
 	Aliases: []string{"tags", "t"},
 	Run: func(cmd *cobra.Command, args []string) {
 		output, _ := cmd.Flags().GetString("output")
-		ctx, client, conn, cancel := getHeadscaleCLIClient()
-		defer cancel()
-		defer conn.Close()
 
-		// retrieve flags from CLI
-		identifier, err := cmd.Flags().GetUint64("identifier")
+		tagsToSet, err := cmd.Flags().GetStringSlice("tags")
 		if err != nil {
 			ErrorOutput(
 				err,
-				fmt.Sprintf("Error converting ID to integer: %s", err),
+				fmt.Sprintf("Error retrieving list of tags to add to machine, %v", err),
 				output,
 			)
 
 			return
 		}
-		tagsToSet, err := cmd.Flags().GetStringSlice("tags")
+		if err := validateTags(tagsToSet); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+
+		if cmd.Flags().Changed("selector") {
+			// The workers do not read the configuration concurrently.
+			force := forceExecution(cmd)
+			runOnSelectedNodes(cmd, "tag", "tagged", false, func(
+				ctx context.Context,
+				client v1.HeadscaleServiceClient,
+				machine *v1.Machine,
+			) error {
+				if err := checkManagedNode(machine, force); err != nil {
+					return err
+				}
+
+				_, err := client.SetTags(
+					ctx,
+					&v1.SetTagsRequest{
+						MachineId: machine.GetId(),
+						Tags:      tagsToSet,
+					},
+				)
+
+				return err
+			})
+
+			return
+		}
+
+		// retrieve flags from CLI
+		identifier, err := cmd.Flags().GetUint64("identifier")
 		if err != nil {
 			ErrorOutput(
 				err,
-				fmt.Sprintf("Error retrieving list of tags to add to machine, %v", err),
+				fmt.Sprintf("Error converting ID to integer: %s", err),
 				output,
 			)
 
 			return
 		}
+		if identifier == 0 {
+			missingNodeTarget(output)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		if err := checkManagedNodeByID(ctx, client, identifier, forceExecution(cmd)); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
 
 		// Sending tags to machine
 		request := &v1.SetTagsRequest{
@@ -639,7 +2887,7 @@ var tagCmd = &cobra.Command{
 		if resp != nil {
 			SuccessOutput(
 				resp.GetMachine(),
-				"Machine updated",
+				tagsUpdatedMessage(resp.GetMachine()),
 				output,
 			)
 		}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func init() {
+	applyNodeCmd.Flags().StringP("file", "f", "", "Path to the JSON spec of the node")
+	err := applyNodeCmd.MarkFlagRequired("file")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	applyNodeCmd.Flags().Bool("dry-run", false, "Show the changes without applying them")
+	nodeCmd.AddCommand(applyNodeCmd)
+}
+
+// nodeSpec is the JSON spec read by nodes apply.
+type nodeSpec struct {
+	Namespace string            `json:"namespace"`
+	Key       string            `json:"key"`
+	Name      string            `json:"name"`
+	Tags      []string          `json:"tags"`
+	Routes    []string          `json:"routes"`
+	Labels    map[string]string `json:"labels"`
+	Expiry    *time.Time        `json:"expiry"`
+}
+
+func readNodeSpec(path string) (*v1.MachineSpec, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var spec nodeSpec
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return nil, err
+	}
+
+	machineSpec := &v1.MachineSpec{
+		Namespace: spec.Namespace,
+		Key:       spec.Key,
+		Name:      spec.Name,
+		Tags:      spec.Tags,
+		Routes:    spec.Routes,
+		Labels:    spec.Labels,
+	}
+	if spec.Expiry != nil {
+		machineSpec.Expiry = timestamppb.New(*spec.Expiry)
+	}
+
+	return machineSpec, nil
+}
+
+var applyNodeCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Register or update a node to match a JSON spec",
+	Long: `
+Register the node described by a JSON spec if it does not exist yet, or
+update it to match the spec. The node is identified by its key, as given
+to nodes register. For example:
+
+  {
+    "namespace": "servers",
+    "key": "mkey:...",
+    "name": "web-1",
+    "tags": ["tag:web"],
+    "routes": ["10.0.0.0/24"],
+    "labels": {"env": "production"},
+    "expiry": "2023-01-01T00:00:00Z"
+  }
+
+Missing tags, routes or labels remove the current ones, and a missing
+expiry means that the node never expires. A missing name keeps the
+current one. Unknown fields are rejected.
+
+The node is marked as managed: the commands changing it by hand (move,
+rename, tag, untag, approve-routes, set-expiry, routes enable and disable)
+then require --force, as the next apply reverts their changes. 'headscale
+nodes unmanage' clears the flag, until a spec is applied to the node
+again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		path, _ := cmd.Flags().GetString("file")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		spec, err := readNodeSpec(path)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error reading node spec: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.ApplyMachineRequest{
+			Spec:   spec,
+			DryRun: dryRun,
+		}
+
+		response, err := client.ApplyMachine(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot apply node spec: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response, "", output)
+
+			return
+		}
+
+		result := response.GetResult()
+		if dryRun {
+			result = "would be " + result
+		}
+		if response.GetMachine() != nil {
+			fmt.Printf("Node %s %s\n", response.GetMachine().GetGivenName(), result)
+		} else {
+			fmt.Printf("Node %s\n", result)
+		}
+		for _, change := range response.GetChanges() {
+			fmt.Printf("  %s\n", change)
+		}
+	},
+}
@@ -0,0 +1,200 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+const errRouteNotAdvertised = Error("route is not advertised by the node")
+
+func init() {
+	approveRoutesCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err := approveRoutesCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	approveRoutesCmd.Flags().
+		StringSlice("routes", []string{}, "List (or repeated flags) of the advertised routes to approve")
+	approveRoutesCmd.Flags().Bool("enable-all", false, "Approve all the routes advertised by the node")
+	approveRoutesCmd.Flags().Bool("disable-all", false, "Disable all the enabled routes of the node")
+	nodeCmd.AddCommand(approveRoutesCmd)
+}
+
+// approvedRoute is a route advertised by a node and whether it is enabled,
+// before and after approve-routes.
+type approvedRoute struct {
+	Route         string `json:"route"`
+	EnabledBefore bool   `json:"enabled_before"`
+	Enabled       bool   `json:"enabled"`
+}
+
+// routeEnabledString is the colour coded state of a route, the one of
+// nodes get.
+func routeEnabledString(enabled bool) string {
+	if enabled {
+		return pterm.LightGreen("yes")
+	}
+
+	return pterm.LightRed("no")
+}
+
+var approveRoutesCmd = &cobra.Command{
+	Use:     "approve-routes",
+	Short:   "Approve some of the routes advertised by a node",
+	Aliases: []string{"set-routes"},
+	Long: `
+Enable the given routes of a node, keeping the routes already enabled.
+Unlike 'headscale nodes routes enable', which replaces the enabled routes,
+this only adds to them. Every route must be a valid CIDR the node
+advertises, nothing is enabled otherwise.
+
+--enable-all approves all the routes advertised by the node instead, and
+--disable-all disables all its routes. The state of the routes before and
+after is printed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		routesArgs, _ := cmd.Flags().GetStringSlice("routes")
+		enableAll, _ := cmd.Flags().GetBool("enable-all")
+		disableAll, _ := cmd.Flags().GetBool("disable-all")
+		modes := 0
+		for _, set := range []bool{len(routesArgs) > 0, enableAll, disableAll} {
+			if set {
+				modes++
+			}
+		}
+		if modes != 1 {
+			ErrorOutput(
+				errMissingParameter,
+				"Please specify one of --routes, --enable-all or --disable-all",
+				output,
+			)
+
+			return
+		}
+
+		routesToApprove, err := normalizeRoutes(routesArgs)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error parsing routes: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		if err := checkManagedNodeByID(ctx, client, identifier, forceExecution(cmd)); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+
+		routesResponse, err := client.GetMachineRoute(ctx, &v1.GetMachineRouteRequest{
+			MachineId: identifier,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get machine routes: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+		routes := routesResponse.GetRoutes()
+
+		enabled := append([]string{}, routes.GetEnabledRoutes()...)
+		switch {
+		case enableAll:
+			routesToApprove = routes.GetAdvertisedRoutes()
+		case disableAll:
+			enabled = []string{}
+		}
+		for _, route := range routesToApprove {
+			if !isStringInSlice(routes.GetAdvertisedRoutes(), route) {
+				err := fmt.Errorf("%s: %w", route, errRouteNotAdvertised)
+				ErrorOutput(err, fmt.Sprintf("Cannot approve routes: %s", err), output)
+
+				return
+			}
+			if !isStringInSlice(enabled, route) {
+				enabled = append(enabled, route)
+			}
+		}
+
+		response, err := client.EnableMachineRoutes(ctx, &v1.EnableMachineRoutesRequest{
+			MachineId: identifier,
+			Routes:    enabled,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot approve machine routes: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		approved := make([]approvedRoute, 0, len(response.GetRoutes().GetAdvertisedRoutes()))
+		for _, route := range response.GetRoutes().GetAdvertisedRoutes() {
+			approved = append(approved, approvedRoute{
+				Route:         route,
+				EnabledBefore: isStringInSlice(routes.GetEnabledRoutes(), route),
+				Enabled:       isStringInSlice(response.GetRoutes().GetEnabledRoutes(), route),
+			})
+		}
+
+		if output != "" {
+			SuccessOutput(approved, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{{"Route", "Enabled before", "Enabled"}}
+		for _, route := range approved {
+			tableData = append(tableData, []string{
+				route.Route,
+				routeEnabledString(route.EnabledBefore),
+				routeEnabledString(route.Enabled),
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
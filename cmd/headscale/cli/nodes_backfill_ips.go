@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	backfillIPsCmd.Flags().Bool("dry-run", false, "Only show the addresses that would be assigned")
+	nodeCmd.AddCommand(backfillIPsCmd)
+}
+
+var backfillIPsCmd = &cobra.Command{
+	Use:   "backfill-ips",
+	Short: "Assign addresses to the nodes missing one",
+	Long: `
+Give the nodes without an IPv4 or IPv6 address one from the configured
+prefixes, as their registration would have. The nodes which have their
+addresses are left unchanged, so it can be run again safely.
+Use --dry-run to preview the addresses each node would get.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.BackfillMachineIPs(
+			ctx,
+			&v1.BackfillMachineIPsRequest{DryRun: dryRun},
+		)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot assign addresses: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.GetAssignments(), "", output)
+
+			return
+		}
+
+		header := "Assigned IPs"
+		if dryRun {
+			header = "IPs to assign"
+		}
+		tableData := pterm.TableData{{"ID", "Hostname", "Name", header}}
+		for _, assignment := range response.GetAssignments() {
+			tableData = append(tableData, []string{
+				strconv.FormatUint(assignment.GetMachine().GetId(), headscale.Base10),
+				assignment.GetMachine().GetName(),
+				assignment.GetMachine().GetGivenName(),
+				strings.Join(assignment.GetAssignedIps(), ", "),
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	compareNodesCmd.Flags().Uint64("a", 0, "Identifier (ID) of the first node")
+	err := compareNodesCmd.MarkFlagRequired("a")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	compareNodesCmd.Flags().Uint64("b", 0, "Identifier (ID) of the second node")
+	err = compareNodesCmd.MarkFlagRequired("b")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(compareNodesCmd)
+}
+
+// nodeComparison is a field of two compared nodes.
+type nodeComparison struct {
+	Field string `json:"field"`
+	A     string `json:"a"`
+	B     string `json:"b"`
+}
+
+func (comparison nodeComparison) differs() bool {
+	return comparison.A != comparison.B
+}
+
+var compareNodesCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare two nodes side by side",
+	Long: "Compare the namespace, tags, routes, state, expiry, DERP region and client " +
+		"version of two nodes, highlighting the differences. " +
+		"The json and yaml outputs only contain the differing fields.",
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		identifierA, _ := cmd.Flags().GetUint64("a")
+		identifierB, _ := cmd.Flags().GetUint64("b")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		machineA, routesA, err := getNodeWithRoutes(ctx, client, identifierA)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get node %d: %s",
+					identifierA,
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		machineB, routesB, err := getNodeWithRoutes(ctx, client, identifierB)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get node %d: %s",
+					identifierB,
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		comparisons := compareNodes(machineA, routesA, machineB, routesB)
+
+		if output != "" {
+			differences := []nodeComparison{}
+			for _, comparison := range comparisons {
+				if comparison.differs() {
+					differences = append(differences, comparison)
+				}
+			}
+			SuccessOutput(differences, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{
+			{"", nodeTitle(machineA), nodeTitle(machineB)},
+		}
+		for _, comparison := range comparisons {
+			row := []string{comparison.Field, comparison.A, comparison.B}
+			if comparison.differs() {
+				row = []string{
+					pterm.LightYellow(comparison.Field),
+					pterm.LightYellow(comparison.A),
+					pterm.LightYellow(comparison.B),
+				}
+			}
+			tableData = append(tableData, row)
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+func getNodeWithRoutes(
+	ctx context.Context,
+	client v1.HeadscaleServiceClient,
+	identifier uint64,
+) (*v1.Machine, *v1.Routes, error) {
+	machineResponse, err := client.GetMachine(
+		ctx,
+		&v1.GetMachineRequest{MachineId: identifier},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	routesResponse, err := client.GetMachineRoute(
+		ctx,
+		&v1.GetMachineRouteRequest{MachineId: identifier},
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return machineResponse.GetMachine(), routesResponse.GetRoutes(), nil
+}
+
+func compareNodes(
+	machineA *v1.Machine,
+	routesA *v1.Routes,
+	machineB *v1.Machine,
+	routesB *v1.Routes,
+) []nodeComparison {
+	field := func(name string, value func(*v1.Machine, *v1.Routes) string) nodeComparison {
+		return nodeComparison{
+			Field: name,
+			A:     value(machineA, routesA),
+			B:     value(machineB, routesB),
+		}
+	}
+
+	return []nodeComparison{
+		field("Hostname", func(machine *v1.Machine, _ *v1.Routes) string {
+			return machine.GetName()
+		}),
+		field("Namespace", func(machine *v1.Machine, _ *v1.Routes) string {
+			return machine.GetNamespace().GetName()
+		}),
+		field("Forced tags", func(machine *v1.Machine, _ *v1.Routes) string {
+			return joinSorted(machine.GetForcedTags())
+		}),
+		field("Valid tags", func(machine *v1.Machine, _ *v1.Routes) string {
+			return joinSorted(machine.GetValidTags())
+		}),
+		field("Invalid tags", func(machine *v1.Machine, _ *v1.Routes) string {
+			return joinSorted(machine.GetInvalidTags())
+		}),
+		field("Labels", func(machine *v1.Machine, _ *v1.Routes) string {
+			labels := make([]string, 0, len(machine.GetLabels()))
+			for key, value := range machine.GetLabels() {
+				labels = append(labels, key+"="+value)
+			}
+
+			return joinSorted(labels)
+		}),
+		field("Advertised routes", func(_ *v1.Machine, routes *v1.Routes) string {
+			return joinSorted(routes.GetAdvertisedRoutes())
+		}),
+		field("Enabled routes", func(_ *v1.Machine, routes *v1.Routes) string {
+			return joinSorted(routes.GetEnabledRoutes())
+		}),
+		field("Online", func(machine *v1.Machine, _ *v1.Routes) string {
+			return strconv.FormatBool(nodeOnline(machine, defaultOnlineThreshold))
+		}),
+		field("Expiry", func(machine *v1.Machine, _ *v1.Routes) string {
+			if machine.GetExpiry() == nil || machine.GetExpiry().AsTime().IsZero() {
+				return "never"
+			}
+
+			return machine.GetExpiry().AsTime().Format(HeadscaleDateTimeFormat)
+		}),
+		field("Expired", func(machine *v1.Machine, _ *v1.Routes) string {
+			expired := machine.GetExpiry() != nil &&
+				!machine.GetExpiry().AsTime().IsZero() &&
+				machine.GetExpiry().AsTime().Before(time.Now())
+
+			return strconv.FormatBool(expired)
+		}),
+		field("Quarantined", func(machine *v1.Machine, _ *v1.Routes) string {
+			return strconv.FormatBool(machine.GetQuarantined())
+		}),
+		field("DERP region", func(machine *v1.Machine, _ *v1.Routes) string {
+			return strconv.Itoa(int(machine.GetPreferredDerpRegion()))
+		}),
+		field("Forced DERP region", func(machine *v1.Machine, _ *v1.Routes) string {
+			if machine.GetForcedDerpRegion() == 0 {
+				return "-"
+			}
+
+			return strconv.Itoa(int(machine.GetForcedDerpRegion()))
+		}),
+		field("Pre-auth key", func(machine *v1.Machine, _ *v1.Routes) string {
+			return authKeyPrefix(machine)
+		}),
+		field("Netmap up to date", func(machine *v1.Machine, _ *v1.Routes) string {
+			return strconv.FormatBool(machine.GetNetmapUpToDate())
+		}),
+		field("Client version", func(machine *v1.Machine, _ *v1.Routes) string {
+			return machine.GetClientVersion()
+		}),
+		field("Clock skew", func(machine *v1.Machine, _ *v1.Routes) string {
+			if machine.GetClockSkewed() {
+				return clockSkewString(machine) + " (skewed, expiry and last seen may look wrong)"
+			}
+
+			return clockSkewString(machine)
+		}),
+		field("OS", func(machine *v1.Machine, _ *v1.Routes) string {
+			return valueOrDash(machine.GetOs())
+		}),
+		field("OS version", func(machine *v1.Machine, _ *v1.Routes) string {
+			return valueOrDash(machine.GetOsVersion())
+		}),
+		field("Arch", func(machine *v1.Machine, _ *v1.Routes) string {
+			return valueOrDash(machine.GetArch())
+		}),
+		field("Device model", func(machine *v1.Machine, _ *v1.Routes) string {
+			return valueOrDash(machine.GetDeviceModel())
+		}),
+		field("Package", func(machine *v1.Machine, _ *v1.Routes) string {
+			return valueOrDash(machine.GetClientPackage())
+		}),
+		field("Endpoints", func(machine *v1.Machine, _ *v1.Routes) string {
+			return valueOrDash(strings.Join(machine.GetEndpoints(), ", "))
+		}),
+		field("PTR names", func(machine *v1.Machine, _ *v1.Routes) string {
+			return valueOrDash(strings.Join(machine.GetPtrNames(), ", "))
+		}),
+	}
+}
+
+func nodeTitle(machine *v1.Machine) string {
+	return fmt.Sprintf(
+		"%s (%s)",
+		machine.GetGivenName(),
+		strconv.FormatUint(machine.GetId(), headscale.Base10),
+	)
+}
+
+func joinSorted(values []string) string {
+	sorted := append([]string{}, values...)
+	sort.Strings(sorted)
+
+	return strings.Join(sorted, ", ")
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/prometheus/common/model"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func init() {
+	nodeConnectionsCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err := nodeConnectionsCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeConnectionsCmd.Flags().
+		String("since", "7d", "Human-readable period to show the connections of (e.g. 24h, 7d)")
+	nodeCmd.AddCommand(nodeConnectionsCmd)
+}
+
+var nodeConnectionsCmd = &cobra.Command{
+	Use:   "connections",
+	Short: "Show the connections and disconnections of a node",
+	Long: `
+Show when a node opened and closed its connection to Headscale, with the
+address it came from and its preferred DERP region, oldest first.
+The events are kept for connection_log_retention (30 days by default).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		sinceStr, _ := cmd.Flags().GetString("since")
+		since, err := model.ParseDuration(sinceStr)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Could not parse duration: %s\n", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.ListMachineConnectionsRequest{
+			MachineId: identifier,
+			Since:     timestamppb.New(time.Now().Add(-time.Duration(since))),
+		}
+
+		response, err := client.ListMachineConnections(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get node connections: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.Events, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{{"Time", "Event", "Endpoint", "DERP region"}}
+		for _, event := range response.GetEvents() {
+			eventStr := pterm.LightRed(event.GetEvent())
+			if event.GetEvent() == headscale.ConnectionEventConnect {
+				eventStr = pterm.LightGreen(event.GetEvent())
+			}
+
+			derpRegion := "-"
+			if event.GetDerpRegion() != 0 {
+				derpRegion = strconv.Itoa(int(event.GetDerpRegion()))
+			}
+
+			tableData = append(tableData, []string{
+				event.GetTime().AsTime().Format(HeadscaleDateTimeFormat),
+				eventStr,
+				valueOrDash(event.GetEndpoint()),
+				derpRegion,
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
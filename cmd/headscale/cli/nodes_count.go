@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+const errConflictingOnline = Error("--online and --offline cannot be used together")
+
+func init() {
+	countNodesCmd.Flags().StringP("namespace", "n", "", "Only count the nodes of this namespace")
+	countNodesCmd.Flags().Bool("online", false, "Only count the online nodes")
+	countNodesCmd.Flags().Bool("offline", false, "Only count the offline nodes")
+	nodeCmd.AddCommand(countNodesCmd)
+}
+
+var countNodesCmd = &cobra.Command{
+	Use:   "count",
+	Short: "Print the number of nodes",
+	Long: `
+Print the number of nodes, of a namespace with --namespace, for the
+dashboards and the alerting scripts. --online and --offline only count the
+nodes seen, or not seen, during the last five minutes, like nodes list.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		namespace, _ := cmd.Flags().GetString("namespace")
+		online, _ := cmd.Flags().GetBool("online")
+		offline, _ := cmd.Flags().GetBool("offline")
+		if online && offline {
+			ErrorOutput(errConflictingOnline, errConflictingOnline.Error(), output)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		machines, err := listMachines(ctx, client, &v1.ListMachinesRequest{Namespace: namespace}, 0)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		count := 0
+		for _, machine := range machines {
+			if (online || offline) && nodeOnline(machine, defaultOnlineThreshold) != online {
+				continue
+			}
+			count++
+		}
+
+		SuccessOutput(map[string]int{"count": count}, strconv.Itoa(count), output)
+	},
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/prometheus/common/model"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	deregisterStaleCmd.Flags().
+		String("older-than", "", "Delete the nodes not seen for longer than this human-readable duration (e.g. 30d)")
+	err := deregisterStaleCmd.MarkFlagRequired("older-than")
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	deregisterStaleCmd.Flags().StringP("namespace", "n", "", "Only delete the nodes of this namespace")
+	deregisterStaleCmd.Flags().Bool("ephemeral-only", false, "Only delete the ephemeral nodes")
+	deregisterStaleCmd.Flags().Bool("dry-run", false, "Only list the nodes that would be deleted")
+	addNodeConcurrencyFlag(deregisterStaleCmd)
+	nodeCmd.AddCommand(deregisterStaleCmd)
+}
+
+// nodeSeenBefore tells if a node was last seen before a time, the nodes
+// never seen by their registration time.
+func nodeSeenBefore(machine *v1.Machine, before time.Time) bool {
+	lastSeen := machine.GetCreatedAt().AsTime()
+	if machine.GetLastSeen() != nil && !machine.GetLastSeen().AsTime().IsZero() {
+		lastSeen = machine.GetLastSeen().AsTime()
+	}
+
+	return lastSeen.Before(before)
+}
+
+var deregisterStaleCmd = &cobra.Command{
+	Use:   "deregister-stale",
+	Short: "Delete the nodes not seen for a while",
+	Long: `
+Delete the nodes not seen for longer than --older-than, such as the
+ephemeral nodes which crashed without logging out. The nodes never seen are
+deleted when they were registered longer ago.
+
+The nodes are listed and the deletion is confirmed first, unless --force is
+set. Use --dry-run to only list them.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		namespace, _ := cmd.Flags().GetString("namespace")
+		ephemeralOnly, _ := cmd.Flags().GetBool("ephemeral-only")
+		olderThanStr, _ := cmd.Flags().GetString("older-than")
+
+		olderThan, err := model.ParseDuration(olderThanStr)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Could not parse duration: %s", err),
+				output,
+			)
+
+			return
+		}
+		seenBefore := time.Now().Add(-time.Duration(olderThan))
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		allMachines, err := listMachines(
+			ctx,
+			client,
+			&v1.ListMachinesRequest{Namespace: namespace},
+			0,
+		)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		machines := []*v1.Machine{}
+		for _, machine := range allMachines {
+			// Nodes shared into the namespace belong to another one.
+			if namespace != "" && machine.GetNamespace().GetName() != namespace {
+				continue
+			}
+			if ephemeralOnly && !machine.GetEphemeral() {
+				continue
+			}
+			if nodeSeenBefore(machine, seenBefore) {
+				machines = append(machines, machine)
+			}
+		}
+
+		if len(machines) == 0 {
+			SuccessOutput(
+				[]nodeResult{},
+				fmt.Sprintf("No node was seen more than %s ago", olderThanStr),
+				output,
+			)
+
+			return
+		}
+
+		runOnNodes(client, cmd, machines, "delete", "deleted", true, func(
+			ctx context.Context,
+			client v1.HeadscaleServiceClient,
+			machine *v1.Machine,
+		) error {
+			_, err := client.DeleteMachine(
+				ctx,
+				&v1.DeleteMachineRequest{MachineId: machine.GetId()},
+			)
+
+			return err
+		})
+	},
+}
@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	nodeDiffAdded    = "added"
+	nodeDiffRemoved  = "removed"
+	nodeDiffModified = "modified"
+)
+
+func init() {
+	diffNodesCmd.Flags().String("old", "", "Path to the older JSON file written by nodes export")
+	err := diffNodesCmd.MarkFlagRequired("old")
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	diffNodesCmd.Flags().String("new", "", "Path to the newer JSON file written by nodes export")
+	err = diffNodesCmd.MarkFlagRequired("new")
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	nodeCmd.AddCommand(diffNodesCmd)
+}
+
+// nodeDiff is a node added, removed or modified between two nodes exports,
+// recognised by its machine key.
+type nodeDiff struct {
+	MachineKey string            `json:"machine_key"`
+	Hostname   string            `json:"hostname"`
+	Namespace  string            `json:"namespace"`
+	Change     string            `json:"change"`
+	Fields     []nodeFieldChange `json:"fields,omitempty"`
+}
+
+// nodeFieldChange is a field of a modified node, with the values added to
+// and removed from the lists.
+type nodeFieldChange struct {
+	Field   string   `json:"field"`
+	Old     string   `json:"old,omitempty"`
+	New     string   `json:"new,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// String is the change of a field in the table of nodes diff.
+func (change nodeFieldChange) String() string {
+	if change.Added == nil && change.Removed == nil {
+		return fmt.Sprintf("%s: %s → %s", change.Field, valueOrDash(change.Old), valueOrDash(change.New))
+	}
+
+	values := make([]string, 0, len(change.Added)+len(change.Removed))
+	for _, value := range change.Added {
+		values = append(values, pterm.LightGreen("+"+value))
+	}
+	for _, value := range change.Removed {
+		values = append(values, pterm.LightRed("-"+value))
+	}
+
+	return fmt.Sprintf("%s: %s", change.Field, strings.Join(values, " "))
+}
+
+// listDelta is the values of after which are not in before, and the ones
+// of before which are not in after, sorted.
+func listDelta(before []string, after []string) ([]string, []string) {
+	var added, removed []string
+	for _, value := range after {
+		if !contains(before, value) {
+			added = append(added, value)
+		}
+	}
+	for _, value := range before {
+		if !contains(after, value) {
+			removed = append(removed, value)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	return added, removed
+}
+
+// diffNodeExports compares two nodes exports, the nodes sorted by
+// hostname.
+func diffNodeExports(before *nodesExport, after *nodesExport) []nodeDiff {
+	oldNodes := make(map[string]nodeExport, len(before.Nodes))
+	for _, node := range before.Nodes {
+		oldNodes[node.MachineKey] = node
+	}
+
+	diffs := []nodeDiff{}
+	for _, node := range after.Nodes {
+		oldNode, existed := oldNodes[node.MachineKey]
+		delete(oldNodes, node.MachineKey)
+
+		diff := nodeDiff{
+			MachineKey: node.MachineKey,
+			Hostname:   node.Hostname,
+			Namespace:  node.Namespace,
+			Change:     nodeDiffAdded,
+		}
+		if existed {
+			diff.Change = nodeDiffModified
+			diff.Fields = diffNodeFields(oldNode, node)
+			if len(diff.Fields) == 0 {
+				continue
+			}
+		}
+		diffs = append(diffs, diff)
+	}
+	for _, node := range oldNodes {
+		diffs = append(diffs, nodeDiff{
+			MachineKey: node.MachineKey,
+			Hostname:   node.Hostname,
+			Namespace:  node.Namespace,
+			Change:     nodeDiffRemoved,
+		})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Hostname != diffs[j].Hostname {
+			return diffs[i].Hostname < diffs[j].Hostname
+		}
+
+		return diffs[i].MachineKey < diffs[j].MachineKey
+	})
+
+	return diffs
+}
+
+// diffNodeFields is the fields changed between two exports of a node.
+func diffNodeFields(before nodeExport, after nodeExport) []nodeFieldChange {
+	changes := []nodeFieldChange{}
+	for _, field := range []struct {
+		name          string
+		before, after string
+	}{
+		{"namespace", before.Namespace, after.Namespace},
+		{"hostname", before.Hostname, after.Hostname},
+		{"given_name", before.GivenName, after.GivenName},
+		{"node_key", before.NodeKey, after.NodeKey},
+		{"expiry", exportExpiryString(before), exportExpiryString(after)},
+	} {
+		if field.before != field.after {
+			changes = append(changes, nodeFieldChange{
+				Field: field.name,
+				Old:   field.before,
+				New:   field.after,
+			})
+		}
+	}
+
+	for _, field := range []struct {
+		name          string
+		before, after []string
+	}{
+		{"ip_addresses", before.IPAddresses, after.IPAddresses},
+		{"forced_tags", before.ForcedTags, after.ForcedTags},
+		{"enabled_routes", before.EnabledRoutes, after.EnabledRoutes},
+	} {
+		added, removed := listDelta(field.before, field.after)
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, nodeFieldChange{
+				Field:   field.name,
+				Added:   added,
+				Removed: removed,
+			})
+		}
+	}
+
+	return changes
+}
+
+func exportExpiryString(node nodeExport) string {
+	if node.Expiry == nil {
+		return ""
+	}
+
+	return node.Expiry.UTC().Format(HeadscaleDateTimeFormat)
+}
+
+var diffNodesCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two files written by nodes export",
+	Long: `
+List the nodes added, removed and modified between two files written by
+'headscale nodes export', recognising the nodes by their machine key, to
+audit what changed between two snapshots.
+
+The namespace moves, the changes of IP addresses, forced tags and enabled
+routes are highlighted. Use 'headscale nodes compare' to compare two
+registered nodes.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		oldPath, _ := cmd.Flags().GetString("old")
+		newPath, _ := cmd.Flags().GetString("new")
+
+		oldExport, err := readNodesExport(oldPath)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error reading %s: %s", oldPath, err), output)
+
+			return
+		}
+		newExport, err := readNodesExport(newPath)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error reading %s: %s", newPath, err), output)
+
+			return
+		}
+
+		diffs := diffNodeExports(oldExport, newExport)
+
+		if output != "" {
+			SuccessOutput(diffs, "", output)
+
+			return
+		}
+
+		if len(diffs) == 0 {
+			SuccessOutput(diffs, "No node changed", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{{"Change", "Hostname", "Namespace", "Details"}}
+		for _, diff := range diffs {
+			var change string
+			switch diff.Change {
+			case nodeDiffAdded:
+				change = pterm.LightGreen(diff.Change)
+			case nodeDiffRemoved:
+				change = pterm.LightRed(diff.Change)
+			default:
+				change = pterm.LightYellow(diff.Change)
+			}
+
+			details := make([]string, len(diff.Fields))
+			for index, field := range diff.Fields {
+				details[index] = field.String()
+				if field.Field == "namespace" {
+					details[index] = pterm.LightYellow(details[index])
+				}
+			}
+
+			tableData = append(tableData, []string{
+				change,
+				diff.Hostname,
+				diff.Namespace,
+				valueOrDash(strings.Join(details, "; ")),
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
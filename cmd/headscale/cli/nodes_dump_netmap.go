@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	dumpNetmapCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err := dumpNetmapCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(dumpNetmapCmd)
+}
+
+// nodeNetmap is the map of a node printed by nodes dump-netmap, in the JSON
+// of the Tailscale protocol.
+type nodeNetmap struct {
+	Node         interface{} `json:"node" yaml:"node"`
+	Peers        interface{} `json:"peers" yaml:"peers"`
+	PacketFilter interface{} `json:"packet_filter" yaml:"packet_filter"`
+	DNSConfig    interface{} `json:"dns_config" yaml:"dns_config"`
+}
+
+var dumpNetmapCmd = &cobra.Command{
+	Use:   "dump-netmap",
+	Short: "Print the map the server would send to a node",
+	Long: `
+Print, as JSON, the node, peers, packet filter and DNS configuration of the
+map the server would send to a node now, with the current ACL rules, to
+find out why it cannot see a peer. Nothing is changed nor sent to the node.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.DumpMachineNetmap(
+			ctx,
+			&v1.DumpMachineNetmapRequest{MachineId: identifier},
+		)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get the netmap of the node: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		var netmap nodeNetmap
+		for _, part := range []struct {
+			encoded string
+			decoded *interface{}
+		}{
+			{response.GetNode(), &netmap.Node},
+			{response.GetPeers(), &netmap.Peers},
+			{response.GetPacketFilter(), &netmap.PacketFilter},
+			{response.GetDnsConfig(), &netmap.DNSConfig},
+		} {
+			if err := json.Unmarshal([]byte(part.encoded), part.decoded); err != nil {
+				ErrorOutput(err, fmt.Sprintf("Cannot decode the netmap: %s", err), output)
+
+				return
+			}
+		}
+
+		if output != "" {
+			SuccessOutput(netmap, "", output)
+
+			return
+		}
+
+		// The map is only readable as JSON.
+		encoded, err := json.MarshalIndent(netmap, "", "\t")
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Cannot encode the netmap: %s", err), output)
+
+			return
+		}
+		SuccessOutput(netmap, string(encoded), output)
+	},
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/prometheus/common/model"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	errInvalidNodeExpiry = Error(
+		"invalid expiry, must be an RFC 3339 time, a duration (e.g. 720h, 30d) or never",
+	)
+	errNodeExpiryInPast = Error("expiry is in the past: pass --force to set it anyway")
+
+	nodeExpiryNever = "never"
+)
+
+func init() {
+	setExpiryCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err := setExpiryCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	setExpiryCmd.Flags().
+		String("expiry", "", "RFC 3339 time (e.g. 2023-01-31T12:00:00Z), duration from now (e.g. 720h, 30d) or never")
+	err = setExpiryCmd.MarkFlagRequired("expiry")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(setExpiryCmd)
+}
+
+// parseNodeExpiry parses the --expiry of nodes set-expiry, nil meaning the
+// key never expires.
+func parseNodeExpiry(value string, now time.Time) (*time.Time, error) {
+	if value == nodeExpiryNever {
+		return nil, nil
+	}
+
+	if expiry, err := time.Parse(time.RFC3339, value); err == nil {
+		return &expiry, nil
+	}
+
+	duration, err := model.ParseDuration(value)
+	if err != nil {
+		return nil, errInvalidNodeExpiry
+	}
+	expiry := now.Add(time.Duration(duration))
+
+	return &expiry, nil
+}
+
+var setExpiryCmd = &cobra.Command{
+	Use:   "set-expiry",
+	Short: "Set when the key of a node expires",
+	Long: `
+Set when the key of a node expires, as an RFC 3339 time or a duration from
+now, or never with --expiry never. Unlike 'headscale nodes expire', which
+logs the node out now, this schedules the expiry. A time in the past
+requires --force.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		expiryStr, _ := cmd.Flags().GetString("expiry")
+		expiry, err := parseNodeExpiry(expiryStr, time.Now())
+		if err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+		if expiry != nil && expiry.Before(time.Now()) && !forceExecution(cmd) {
+			ErrorOutput(errNodeExpiryInPast, errNodeExpiryInPast.Error(), output)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		if err := checkManagedNodeByID(ctx, client, identifier, forceExecution(cmd)); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+
+		request := &v1.SetMachineExpiryRequest{MachineId: identifier}
+		if expiry != nil {
+			request.Expiry = timestamppb.New(*expiry)
+		}
+
+		response, err := client.SetMachineExpiry(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot set the expiry of the node: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		expiryMessage := nodeExpiryNever
+		if expiry != nil {
+			expiryMessage = response.GetMachine().GetExpiry().AsTime().Format(HeadscaleDateTimeFormat)
+		}
+		SuccessOutput(
+			response.GetMachine(),
+			fmt.Sprintf("Expiry of %s set to %s", nodeTitle(response.GetMachine()), expiryMessage),
+			output,
+		)
+	},
+}
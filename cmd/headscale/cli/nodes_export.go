@@ -0,0 +1,328 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	errInvalidNodesExport     = Error("invalid nodes export")
+	errUnsupportedNodesExport = Error("unsupported version of the nodes export")
+
+	// nodesExportVersion is the version of the format written by nodes
+	// export, increased when it changes.
+	nodesExportVersion = 1
+
+	nodeImportCreated = "created"
+	nodeImportSkipped = "skipped, already exists"
+	nodeImportFailed  = "failed"
+)
+
+func init() {
+	exportNodesCmd.Flags().
+		StringP("file", "f", "", "Path of the JSON file to write, standard output if not set")
+	nodeCmd.AddCommand(exportNodesCmd)
+
+	importNodesCmd.Flags().StringP("file", "f", "", "Path to the JSON file written by nodes export")
+	err := importNodesCmd.MarkFlagRequired("file")
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
+	nodeCmd.AddCommand(importNodesCmd)
+}
+
+// nodesExport is the JSON document written by nodes export and read by
+// nodes import.
+type nodesExport struct {
+	Version int          `json:"version"`
+	Nodes   []nodeExport `json:"nodes"`
+}
+
+// nodeExport is a node of nodesExport, with what is needed to recognise
+// it when it connects to another server.
+type nodeExport struct {
+	MachineKey    string     `json:"machine_key"`
+	NodeKey       string     `json:"node_key,omitempty"`
+	DiscoKey      string     `json:"disco_key,omitempty"`
+	Hostname      string     `json:"hostname"`
+	GivenName     string     `json:"given_name,omitempty"`
+	Namespace     string     `json:"namespace"`
+	IPAddresses   []string   `json:"ip_addresses,omitempty"`
+	ForcedTags    []string   `json:"forced_tags,omitempty"`
+	EnabledRoutes []string   `json:"enabled_routes,omitempty"`
+	Expiry        *time.Time `json:"expiry,omitempty"`
+}
+
+// nodeImportResult is the outcome of the import of a node.
+type nodeImportResult struct {
+	Hostname           string      `json:"hostname"`
+	Result             string      `json:"result"`
+	Error              string      `json:"error,omitempty"`
+	IPAddressesChanged bool        `json:"ip_addresses_changed,omitempty"`
+	Machine            *v1.Machine `json:"machine,omitempty"`
+}
+
+// readNodesExport reads and validates a file written by nodes export, so
+// nothing is imported when one of the nodes is invalid.
+func readNodesExport(path string) (*nodesExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var export nodesExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	if export.Version != nodesExportVersion {
+		return nil, fmt.Errorf(
+			"version %d, expected %d: %w",
+			export.Version,
+			nodesExportVersion,
+			errUnsupportedNodesExport,
+		)
+	}
+
+	machineKeys := make(map[string]bool, len(export.Nodes))
+	for _, node := range export.Nodes {
+		if node.MachineKey == "" || node.Namespace == "" {
+			return nil, fmt.Errorf(
+				"node %q: machine_key and namespace are required: %w",
+				node.Hostname,
+				errInvalidNodesExport,
+			)
+		}
+		if machineKeys[node.MachineKey] {
+			return nil, fmt.Errorf(
+				"machine key of %q is defined twice: %w",
+				node.Hostname,
+				errInvalidNodesExport,
+			)
+		}
+		machineKeys[node.MachineKey] = true
+	}
+
+	return &export, nil
+}
+
+var exportNodesCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the nodes of every namespace as JSON",
+	Long: `
+Write the nodes of every namespace, with their keys, IP addresses, forced
+tags, enabled routes and expiry, in the JSON format read by
+'headscale nodes import', to rebuild them on another server.
+
+The file contains the keys of the nodes: keep it safe.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		path, _ := cmd.Flags().GetString("file")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		machines, err := listMachines(ctx, client, &v1.ListMachinesRequest{}, 0)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		export := nodesExport{
+			Version: nodesExportVersion,
+			Nodes:   make([]nodeExport, len(machines)),
+		}
+		for index, machine := range machines {
+			routesResponse, err := client.GetMachineRoute(
+				ctx,
+				&v1.GetMachineRouteRequest{MachineId: machine.GetId()},
+			)
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf(
+						"Cannot get routes of %s: %s",
+						nodeTitle(machine),
+						status.Convert(err).Message(),
+					),
+					output,
+				)
+
+				return
+			}
+
+			export.Nodes[index] = nodeExport{
+				MachineKey:    machine.GetMachineKey(),
+				NodeKey:       machine.GetNodeKey(),
+				DiscoKey:      machine.GetDiscoKey(),
+				Hostname:      machine.GetName(),
+				GivenName:     machine.GetGivenName(),
+				Namespace:     machine.GetNamespace().GetName(),
+				IPAddresses:   machine.GetIpAddresses(),
+				ForcedTags:    machine.GetForcedTags(),
+				EnabledRoutes: routesResponse.GetRoutes().GetEnabledRoutes(),
+			}
+			if machine.GetExpiry() != nil && !machine.GetExpiry().AsTime().IsZero() {
+				expiry := machine.GetExpiry().AsTime()
+				export.Nodes[index].Expiry = &expiry
+			}
+		}
+
+		data, err := json.MarshalIndent(export, "", "\t")
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Cannot encode nodes: %s", err), output)
+
+			return
+		}
+
+		if path == "" {
+			fmt.Println(string(data))
+
+			return
+		}
+
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			ErrorOutput(err, fmt.Sprintf("Cannot write nodes: %s", err), output)
+
+			return
+		}
+
+		SuccessOutput(
+			export,
+			fmt.Sprintf("%d nodes written to %s", len(export.Nodes), path),
+			output,
+		)
+	},
+}
+
+var importNodesCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Recreate the nodes written by nodes export",
+	Long: `
+Recreate the nodes written by 'headscale nodes export', so they are
+recognised when they connect with their keys, skipping the ones whose
+machine key is already registered. Their namespaces must exist, see
+'headscale namespaces import'.
+
+A node keeps its IP addresses unless another node uses them, then it gets
+new ones. Every node is validated before any is imported.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		path, _ := cmd.Flags().GetString("file")
+
+		export, err := readNodesExport(path)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error reading nodes: %s", err), output)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		machines, err := listMachines(ctx, client, &v1.ListMachinesRequest{}, 0)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+		existing := make(map[string]bool, len(machines))
+		for _, machine := range machines {
+			existing[machine.GetMachineKey()] = true
+		}
+
+		created := 0
+		results := make([]nodeImportResult, len(export.Nodes))
+		for index, node := range export.Nodes {
+			results[index] = nodeImportResult{Hostname: node.Hostname, Result: nodeImportCreated}
+			if existing[node.MachineKey] {
+				results[index].Result = nodeImportSkipped
+
+				continue
+			}
+
+			request := &v1.ImportMachineRequest{
+				MachineKey:    node.MachineKey,
+				NodeKey:       node.NodeKey,
+				DiscoKey:      node.DiscoKey,
+				Name:          node.Hostname,
+				GivenName:     node.GivenName,
+				Namespace:     node.Namespace,
+				IpAddresses:   node.IPAddresses,
+				ForcedTags:    node.ForcedTags,
+				EnabledRoutes: node.EnabledRoutes,
+			}
+			if node.Expiry != nil {
+				request.Expiry = timestamppb.New(*node.Expiry)
+			}
+
+			response, err := client.ImportMachine(ctx, request)
+			if err != nil {
+				setExitCode(errorExitCode(err))
+				results[index].Result = nodeImportFailed
+				results[index].Error = status.Convert(err).Message()
+
+				continue
+			}
+			results[index].Machine = response.GetMachine()
+			results[index].IPAddressesChanged = response.GetIpAddressesChanged()
+			created++
+		}
+
+		if output != "" {
+			SuccessOutput(results, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{{"Hostname", "IP addresses", "Result"}}
+		for _, result := range results {
+			outcome := pterm.LightGreen(result.Result)
+			switch {
+			case result.Error != "":
+				outcome = pterm.LightRed(fmt.Sprintf("%s: %s", result.Result, result.Error))
+			case result.Result == nodeImportSkipped:
+				outcome = pterm.LightYellow(result.Result)
+			case result.IPAddressesChanged:
+				outcome = pterm.LightYellow(result.Result + ", IP addresses changed")
+			}
+
+			tableData = append(tableData, []string{
+				result.Hostname,
+				valueOrDash(joinNonEmpty(result.Machine.GetIpAddresses()...)),
+				outcome,
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		fmt.Printf("%d of %d nodes imported\n", created, len(export.Nodes))
+	},
+}
@@ -0,0 +1,276 @@
+package cli
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	errInvalidNodeFilter       = Error("invalid filter")
+	errInvalidNamespacePattern = Error("invalid namespace pattern")
+	errInvalidLastSeen         = Error("invalid last seen time, must be an RFC 3339 time or a duration (e.g. 30d)")
+)
+
+// nodeFilter is a predicate of nodes list --filter: the field of key is
+// equal to value, or contains it when substring is set.
+type nodeFilter struct {
+	key       string
+	value     string
+	substring bool
+}
+
+// nodeFilterFields are the fields nodes list --filter can match, as shown
+// in the table.
+var nodeFilterFields = map[string]func(machine *v1.Machine, onlineThreshold time.Duration) string{
+	"id": func(machine *v1.Machine, _ time.Duration) string {
+		return strconv.FormatUint(machine.GetId(), headscale.Base10)
+	},
+	"name": func(machine *v1.Machine, _ time.Duration) string {
+		return machine.GetGivenName()
+	},
+	"hostname": func(machine *v1.Machine, _ time.Duration) string {
+		return machine.GetName()
+	},
+	"namespace": func(machine *v1.Machine, _ time.Duration) string {
+		return machine.GetNamespace().GetName()
+	},
+	"ip": func(machine *v1.Machine, _ time.Duration) string {
+		return strings.Join(machine.GetIpAddresses(), ", ")
+	},
+	"online": func(machine *v1.Machine, onlineThreshold time.Duration) string {
+		return strconv.FormatBool(nodeOnline(machine, onlineThreshold))
+	},
+	"expired": func(machine *v1.Machine, _ time.Duration) string {
+		expired := machine.GetExpiry() != nil &&
+			!machine.GetExpiry().AsTime().IsZero() &&
+			machine.GetExpiry().AsTime().Before(time.Now())
+
+		return strconv.FormatBool(expired)
+	},
+	"ephemeral": func(machine *v1.Machine, _ time.Duration) string {
+		return strconv.FormatBool(machine.GetEphemeral())
+	},
+	"quarantined": func(machine *v1.Machine, _ time.Duration) string {
+		return strconv.FormatBool(machine.GetQuarantined())
+	},
+	"managed": func(machine *v1.Machine, _ time.Duration) string {
+		return strconv.FormatBool(machine.GetManaged())
+	},
+	"os": func(machine *v1.Machine, _ time.Duration) string {
+		return machine.GetOs()
+	},
+	"arch": func(machine *v1.Machine, _ time.Duration) string {
+		return machine.GetArch()
+	},
+}
+
+func nodeFilterKeys() []string {
+	keys := make([]string, 0, len(nodeFilterFields))
+	for key := range nodeFilterFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// parseNodeFilters parses the key=value and key~substring predicates of
+// nodes list --filter.
+func parseNodeFilters(args []string) ([]nodeFilter, error) {
+	filters := make([]nodeFilter, 0, len(args))
+	for _, arg := range args {
+		index := strings.IndexAny(arg, "=~")
+		if index <= 0 {
+			return nil, fmt.Errorf(
+				"%q must be key=value or key~substring: %w",
+				arg,
+				errInvalidNodeFilter,
+			)
+		}
+
+		filter := nodeFilter{
+			key:       arg[:index],
+			value:     arg[index+1:],
+			substring: arg[index] == '~',
+		}
+		if _, ok := nodeFilterFields[filter.key]; !ok {
+			return nil, fmt.Errorf(
+				"unknown key %q, must be one of: %s: %w",
+				filter.key,
+				strings.Join(nodeFilterKeys(), ", "),
+				errInvalidNodeFilter,
+			)
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// matchNodeFilters tells if machine matches all the filters.
+func matchNodeFilters(
+	machine *v1.Machine,
+	filters []nodeFilter,
+	onlineThreshold time.Duration,
+) bool {
+	for _, filter := range filters {
+		value := nodeFilterFields[filter.key](machine, onlineThreshold)
+		if filter.substring && !strings.Contains(value, filter.value) {
+			return false
+		}
+		if !filter.substring && value != filter.value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// namespacePattern returns the --namespace of nodes list when it is a glob
+// pattern, matched on the client with path.Match, and "" when it is the
+// name of a namespace, which cannot contain the characters of a pattern.
+func namespacePattern(namespace string) (string, error) {
+	if !strings.ContainsAny(namespace, `*?[\`) {
+		return "", nil
+	}
+	if _, err := path.Match(namespace, ""); err != nil {
+		return "", fmt.Errorf("%q: %w", namespace, errInvalidNamespacePattern)
+	}
+
+	return namespace, nil
+}
+
+// matchNamespacePattern keeps the machines of the namespaces matching
+// pattern.
+func matchNamespacePattern(machines []*v1.Machine, pattern string) []*v1.Machine {
+	matching := []*v1.Machine{}
+	for _, machine := range machines {
+		// The pattern was checked by namespacePattern.
+		if matched, _ := path.Match(pattern, machine.GetNamespace().GetName()); matched {
+			matching = append(matching, machine)
+		}
+	}
+
+	return matching
+}
+
+// parseLastSeen parses the --last-seen-before and --last-seen-after of
+// nodes list, an RFC 3339 time or a duration before now. An empty value is
+// the zero time, meaning no bound.
+func parseLastSeen(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if lastSeen, err := time.Parse(time.RFC3339, value); err == nil {
+		return lastSeen, nil
+	}
+
+	duration, err := model.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q: %w", value, errInvalidLastSeen)
+	}
+
+	return now.Add(-time.Duration(duration)), nil
+}
+
+// nodeLastSeenBetween tells if a machine was last seen before before and
+// after after, the zero times not bounding. The machines never seen only
+// match a before bound alone.
+func nodeLastSeenBetween(machine *v1.Machine, before time.Time, after time.Time) bool {
+	if machine.GetLastSeen() == nil || machine.GetLastSeen().AsTime().IsZero() {
+		return !before.IsZero() && after.IsZero()
+	}
+
+	lastSeen := machine.GetLastSeen().AsTime()
+
+	return (before.IsZero() || lastSeen.Before(before)) &&
+		(after.IsZero() || lastSeen.After(after))
+}
+
+// nodeListFilter is the filters of nodes list, the zero values not
+// filtering.
+type nodeListFilter struct {
+	// pattern is a pattern of namespaces, see namespacePattern.
+	pattern string
+	// status is online or offline, following onlineThreshold.
+	status           string
+	onlineThreshold  time.Duration
+	lastSeenBefore   time.Time
+	lastSeenAfter    time.Time
+	filters          []nodeFilter
+	arch             string
+	endpointContains string
+	keyPrefix        string
+	tags             []string
+	ephemeralOnly    bool
+	persistentOnly   bool
+}
+
+// filterNodes keeps the machines matching all the filters of filter.
+func filterNodes(machines []*v1.Machine, filter nodeListFilter) []*v1.Machine {
+	if filter.pattern != "" {
+		machines = matchNamespacePattern(machines, filter.pattern)
+	}
+
+	matching := []*v1.Machine{}
+	for _, machine := range machines {
+		if filter.matches(machine) {
+			matching = append(matching, machine)
+		}
+	}
+
+	return matching
+}
+
+func (filter nodeListFilter) matches(machine *v1.Machine) bool {
+	if filter.status != "" &&
+		nodeOnline(machine, filter.onlineThreshold) != (filter.status == "online") {
+		return false
+	}
+	if (!filter.lastSeenBefore.IsZero() || !filter.lastSeenAfter.IsZero()) &&
+		!nodeLastSeenBetween(machine, filter.lastSeenBefore, filter.lastSeenAfter) {
+		return false
+	}
+	if len(filter.filters) > 0 &&
+		!matchNodeFilters(machine, filter.filters, filter.onlineThreshold) {
+		return false
+	}
+	if filter.arch != "" && machine.GetArch() != filter.arch {
+		return false
+	}
+	if filter.endpointContains != "" {
+		found := false
+		for _, endpoint := range machine.GetEndpoints() {
+			if strings.Contains(endpoint, filter.endpointContains) {
+				found = true
+
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if filter.keyPrefix != "" &&
+		!strings.HasPrefix(machine.GetPreAuthKey().GetKey(), filter.keyPrefix) {
+		return false
+	}
+	if len(filter.tags) > 0 && !nodeHasTags(machine, filter.tags) {
+		return false
+	}
+	if (filter.ephemeralOnly || filter.persistentOnly) &&
+		machine.GetEphemeral() != filter.ephemeralOnly {
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestFilterNodes(t *testing.T) {
+	now := time.Now()
+	node := func(id uint64, namespace string, lastSeen time.Duration) *v1.Machine {
+		return &v1.Machine{
+			Id:        id,
+			Namespace: &v1.Namespace{Name: namespace},
+			LastSeen:  timestamppb.New(now.Add(-lastSeen)),
+		}
+	}
+	machines := []*v1.Machine{
+		node(1, "team-a", time.Minute),
+		node(2, "team-a", time.Hour),
+		node(3, "team-b", time.Minute),
+		node(4, "other", time.Minute),
+		node(5, "other", time.Hour),
+	}
+
+	tests := []struct {
+		name   string
+		filter nodeListFilter
+		want   []uint64
+	}{
+		{
+			name:   "no filter",
+			filter: nodeListFilter{},
+			want:   []uint64{1, 2, 3, 4, 5},
+		},
+		{
+			name:   "namespace pattern",
+			filter: nodeListFilter{pattern: "team-*"},
+			want:   []uint64{1, 2, 3},
+		},
+		{
+			name: "online",
+			filter: nodeListFilter{
+				status:          "online",
+				onlineThreshold: defaultOnlineThreshold,
+			},
+			want: []uint64{1, 3, 4},
+		},
+		{
+			name: "namespace pattern and online",
+			filter: nodeListFilter{
+				pattern:         "team-*",
+				status:          "online",
+				onlineThreshold: defaultOnlineThreshold,
+			},
+			want: []uint64{1, 3},
+		},
+		{
+			name: "namespace pattern and offline",
+			filter: nodeListFilter{
+				pattern:         "team-*",
+				status:          "offline",
+				onlineThreshold: defaultOnlineThreshold,
+			},
+			want: []uint64{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []uint64{}
+			for _, machine := range filterNodes(machines, tt.filter) {
+				got = append(got, machine.GetId())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("filterNodes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"inet.af/netaddr"
+)
+
+func init() {
+	nodeCmd.AddCommand(findNodeByIPCmd)
+}
+
+// findNodeByIP is the node of machines which has the IP address ip, nil if
+// none has it. The addresses are compared parsed, so an IPv6 address
+// matches whatever its notation.
+func findNodeByIP(machines []*v1.Machine, ip netaddr.IP) *v1.Machine {
+	for _, machine := range machines {
+		for _, addr := range machine.GetIpAddresses() {
+			if machineIP, err := netaddr.ParseIP(addr); err == nil && machineIP == ip {
+				return machine
+			}
+		}
+	}
+
+	return nil
+}
+
+var findNodeByIPCmd = &cobra.Command{
+	Use:   "find-by-ip ADDRESS",
+	Short: "Show the details of the node which has an IP address",
+	Long: `
+Find the node which has an IPv4 or IPv6 address of the tailnet, for
+instance one of an alert, and show its details like 'headscale nodes get'.
+The command fails with the not found exit code when no node has it.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ip, err := netaddr.ParseIP(args[0])
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Invalid IP address: %s", err), output)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		machines, err := listMachines(ctx, client, &v1.ListMachinesRequest{}, 0)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		machine := findNodeByIP(machines, ip)
+		if machine == nil {
+			err := status.Errorf(codes.NotFound, "no node has the IP address %s", ip)
+			ErrorOutput(err, fmt.Sprintf("Cannot find node: %s", status.Convert(err).Message()), output)
+
+			return
+		}
+
+		identifier := machine.GetId()
+		machine, routes, err := getNodeWithRoutes(ctx, client, identifier)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get node %d: %s",
+					identifier,
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(machine, "", output)
+
+			return
+		}
+
+		err = pterm.DefaultTable.WithData(nodeToDetailsPtable(machine, routes)).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
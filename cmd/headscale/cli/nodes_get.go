@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	getNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err := getNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(getNodeCmd)
+}
+
+var getNodeCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Show the details of a node",
+	Long: `
+Show every field of a node, one per line, with its tags and its advertised
+routes, in green when enabled and in red otherwise. The json and yaml
+outputs are the node as returned by the API.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		machine, routes, err := getNodeWithRoutes(ctx, client, identifier)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get node %d: %s",
+					identifier,
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(machine, "", output)
+
+			return
+		}
+
+		err = pterm.DefaultTable.WithData(nodeToDetailsPtable(machine, routes)).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+// nodeToDetailsPtable converts a node to a table of two columns, the name
+// of each field and its value, coloured like in nodes list.
+func nodeToDetailsPtable(machine *v1.Machine, routes *v1.Routes) pterm.TableData {
+	var expiry time.Time
+	if machine.GetExpiry() != nil {
+		expiry = machine.GetExpiry().AsTime()
+	}
+	expired := pterm.LightGreen("no")
+	if !expiry.IsZero() && expiry.Before(time.Now()) {
+		expired = pterm.LightRed("yes")
+	}
+
+	lastSeen := "-"
+	if machine.GetLastSeen() != nil {
+		lastSeen = machine.GetLastSeen().AsTime().Format(HeadscaleDateTimeFormat)
+	}
+	online := pterm.LightRed("offline")
+	if nodeOnline(machine, defaultOnlineThreshold) {
+		online = pterm.LightGreen("online")
+	}
+
+	quarantined := pterm.LightGreen("no")
+	if machine.GetQuarantined() {
+		quarantined = pterm.LightRed("yes")
+	}
+
+	invalidTags := make([]string, 0, len(machine.GetInvalidTags()))
+	for _, tag := range machine.GetInvalidTags() {
+		invalidTags = append(invalidTags, pterm.LightRed(tag))
+	}
+	validTags := make([]string, 0, len(machine.GetValidTags()))
+	for _, tag := range machine.GetValidTags() {
+		validTags = append(validTags, pterm.LightGreen(tag))
+	}
+
+	labels := make([]string, 0, len(machine.GetLabels()))
+	for key, value := range machine.GetLabels() {
+		labels = append(labels, key+"="+value)
+	}
+
+	advertisedRoutes := make([]string, 0, len(routes.GetAdvertisedRoutes()))
+	for _, route := range routes.GetAdvertisedRoutes() {
+		if isStringInSlice(routes.GetEnabledRoutes(), route) {
+			advertisedRoutes = append(advertisedRoutes, pterm.LightGreen(route))
+		} else {
+			advertisedRoutes = append(advertisedRoutes, pterm.LightRed(route))
+		}
+	}
+
+	lastAck := "-"
+	if machine.GetLastSuccessfulUpdate() != nil {
+		lastAck = machine.GetLastSuccessfulUpdate().AsTime().Format(HeadscaleDateTimeFormat)
+	}
+	netmap := pterm.LightRed("outdated")
+	if machine.GetNetmapUpToDate() {
+		netmap = pterm.LightGreen("up to date")
+	}
+
+	keyRefresh := "-"
+	if machine.GetKeyRefreshRequested() != nil {
+		keyRefresh = pterm.LightYellow(
+			"pending since " +
+				machine.GetKeyRefreshRequested().AsTime().Format(HeadscaleDateTimeFormat),
+		)
+	}
+
+	idleExpiry := "-"
+	if deadline, ok := idleExpiryDeadline(machine); ok {
+		idleExpiry = deadline.Format(HeadscaleDateTimeFormat)
+	}
+
+	clockSkew := clockSkewString(machine)
+	if machine.GetClockSkewed() {
+		clockSkew = pterm.LightRed(clockSkew)
+	}
+
+	return pterm.TableData{
+		{"ID", strconv.FormatUint(machine.GetId(), headscale.Base10)},
+		{"Name", machine.GetGivenName()},
+		{"Hostname", machine.GetName()},
+		{"NodeKey", machine.GetNodeKey()},
+		{"Namespace", pterm.LightMagenta(machine.GetNamespace().GetName())},
+		{"IP addresses", strings.Join(machine.GetIpAddresses(), ", ")},
+		{"Ephemeral", strconv.FormatBool(machine.GetEphemeral())},
+		{"Auth key", authKeyPrefix(machine)},
+		{"Last seen", lastSeen},
+		{"Online", online},
+		{"Expiry", expiryString(expiry)},
+		{"Expired", expired},
+		{"Idle expiry", idleExpiry},
+		{"Quarantined", quarantined},
+		{"Managed", strconv.FormatBool(machine.GetManaged())},
+		{"DERP", derpRegionString(machine, true)},
+		{"Flaps", strconv.FormatUint(uint64(machine.GetFlapCount()), headscale.Base10)},
+		{"ForcedTags", valueOrDash(joinSorted(machine.GetForcedTags()))},
+		{"InvalidTags", valueOrDash(strings.Join(invalidTags, ", "))},
+		{"ValidTags", valueOrDash(strings.Join(validTags, ", "))},
+		{"Labels", valueOrDash(joinSorted(labels))},
+		{"Routes", valueOrDash(strings.Join(advertisedRoutes, ", "))},
+		{"Last ack", lastAck},
+		{"Netmap", netmap},
+		{"Key refresh", keyRefresh},
+		{"Clock skew", clockSkew},
+		{"Client version", valueOrDash(machine.GetClientVersion())},
+		{"OS", valueOrDash(machine.GetOs())},
+		{"OS version", valueOrDash(machine.GetOsVersion())},
+		{"Arch", valueOrDash(machine.GetArch())},
+		{"Device", valueOrDash(machine.GetDeviceModel())},
+		{"Package", valueOrDash(machine.GetClientPackage())},
+		{"Endpoints", valueOrDash(strings.Join(machine.GetEndpoints(), ", "))},
+		{"PTR names", valueOrDash(strings.Join(machine.GetPtrNames(), ", "))},
+	}
+}
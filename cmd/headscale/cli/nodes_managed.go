@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+const errManagedNode = Error(
+	"node is managed by nodes apply, the next reconcile reverts manual changes: pass --force to change it anyway",
+)
+
+func init() {
+	unmanageNodeCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err := unmanageNodeCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(unmanageNodeCmd)
+}
+
+// checkManagedNode refuses to change a node managed by nodes apply, as the
+// change would be reverted at the next reconcile, unless force is set (see
+// forceExecution).
+func checkManagedNode(machine *v1.Machine, force bool) error {
+	if !machine.GetManaged() {
+		return nil
+	}
+	if !force {
+		return fmt.Errorf("%s: %w", nodeTitle(machine), errManagedNode)
+	}
+
+	fmt.Fprintf(
+		os.Stderr,
+		"Warning: %s is managed by nodes apply, the next reconcile reverts this change\n",
+		nodeTitle(machine),
+	)
+
+	return nil
+}
+
+// checkManagedNodeByID is checkManagedNode for the commands given the
+// identifier of a node.
+func checkManagedNodeByID(
+	ctx context.Context,
+	client v1.HeadscaleServiceClient,
+	identifier uint64,
+	force bool,
+) error {
+	response, err := client.GetMachine(ctx, &v1.GetMachineRequest{MachineId: identifier})
+	if err != nil {
+		return fmt.Errorf("cannot get node: %s", status.Convert(err).Message())
+	}
+
+	return checkManagedNode(response.GetMachine(), force)
+}
+
+var unmanageNodeCmd = &cobra.Command{
+	Use:   "unmanage",
+	Short: "Release a node from nodes apply",
+	Long: `
+Clear the managed flag set on a node by 'headscale nodes apply', so it can
+be changed by hand without --force. Applying a spec to the node marks it
+as managed again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.UnmanageMachine(
+			ctx,
+			&v1.UnmanageMachineRequest{MachineId: identifier},
+		)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot unmanage node: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response.Machine, "Node is no longer managed", output)
+	},
+}
@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	namespaceRouteApproved       = "approved"
+	namespaceRouteAlreadyEnabled = "already enabled"
+	namespaceRouteNotAdvertised  = "not advertised"
+)
+
+func init() {
+	namespaceRoutesCmd.Flags().StringP("namespace", "n", "", "Namespace of the nodes")
+	err := namespaceRoutesCmd.MarkFlagRequired("namespace")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	err = namespaceRoutesCmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	namespaceRoutesCmd.Flags().
+		StringSlice("routes", []string{}, "List (or repeated flags) of the routes to approve (e.g. 10.0.0.0/24)")
+	err = namespaceRoutesCmd.MarkFlagRequired("routes")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(namespaceRoutesCmd)
+}
+
+// namespaceRoutesResult is the outcome of set-namespace-default-routes for
+// a node: the state of each route, or why the node was not changed.
+type namespaceRoutesResult struct {
+	ID     uint64            `json:"id"`
+	Name   string            `json:"name"`
+	Routes map[string]string `json:"routes,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// namespaceRouteString is the colour coded state of a route in the table
+// of set-namespace-default-routes.
+func namespaceRouteString(state string) string {
+	switch state {
+	case namespaceRouteApproved:
+		return pterm.LightGreen(state)
+	case namespaceRouteNotAdvertised:
+		return pterm.LightYellow(state)
+	}
+
+	return state
+}
+
+var namespaceRoutesCmd = &cobra.Command{
+	Use:   "set-namespace-default-routes",
+	Short: "Approve some routes on every node of a namespace advertising them",
+	Long: `
+Enable the given routes on every node of a namespace advertising them, for
+the subnet routers of a namespace sharing the same routes. The routes
+already enabled are kept, like with 'headscale nodes approve-routes'.
+
+The nodes not advertising a route are skipped for that route, with a
+warning. A matrix of the nodes and routes shows which routes were
+approved. The nodes shared into the namespace are not changed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		namespace, _ := cmd.Flags().GetString("namespace")
+		routesArgs, _ := cmd.Flags().GetStringSlice("routes")
+
+		routes, err := normalizeRoutes(routesArgs)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error parsing routes: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		if err := checkNamespaceExists(ctx, client, namespace); err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		machines, err := listMachines(ctx, client, &v1.ListMachinesRequest{Namespace: namespace}, 0)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		force := forceExecution(cmd)
+		results := []namespaceRoutesResult{}
+		for _, machine := range machines {
+			// Nodes shared into the namespace belong to another one.
+			if machine.GetNamespace().GetName() != namespace {
+				continue
+			}
+
+			result := namespaceRoutesResult{ID: machine.GetId(), Name: machine.GetGivenName()}
+			if err := checkManagedNode(machine, force); err != nil {
+				setExitCode(errorExitCode(err))
+				result.Error = err.Error()
+				results = append(results, result)
+
+				continue
+			}
+
+			routesResponse, err := client.GetMachineRoute(
+				ctx,
+				&v1.GetMachineRouteRequest{MachineId: machine.GetId()},
+			)
+			if err != nil {
+				setExitCode(errorExitCode(err))
+				result.Error = status.Convert(err).Message()
+				results = append(results, result)
+
+				continue
+			}
+			machineRoutes := routesResponse.GetRoutes()
+
+			result.Routes = make(map[string]string, len(routes))
+			enabled := append([]string{}, machineRoutes.GetEnabledRoutes()...)
+			var notAdvertised []string
+			for _, route := range routes {
+				switch {
+				case !isStringInSlice(machineRoutes.GetAdvertisedRoutes(), route):
+					result.Routes[route] = namespaceRouteNotAdvertised
+					notAdvertised = append(notAdvertised, route)
+				case isStringInSlice(enabled, route):
+					result.Routes[route] = namespaceRouteAlreadyEnabled
+				default:
+					result.Routes[route] = namespaceRouteApproved
+					enabled = append(enabled, route)
+				}
+			}
+			if len(notAdvertised) > 0 {
+				fmt.Fprintf(
+					os.Stderr,
+					"Warning: %s does not advertise %s, skipped\n",
+					nodeTitle(machine),
+					strings.Join(notAdvertised, ", "),
+				)
+			}
+
+			if len(enabled) > len(machineRoutes.GetEnabledRoutes()) {
+				_, err := client.EnableMachineRoutes(ctx, &v1.EnableMachineRoutesRequest{
+					MachineId: machine.GetId(),
+					Routes:    enabled,
+				})
+				if err != nil {
+					setExitCode(errorExitCode(err))
+					result.Routes = nil
+					result.Error = status.Convert(err).Message()
+				}
+			}
+			results = append(results, result)
+		}
+
+		if output != "" {
+			SuccessOutput(results, "", output)
+
+			return
+		}
+
+		if len(results) == 0 {
+			SuccessOutput(results, fmt.Sprintf("Namespace %s has no node", namespace), output)
+
+			return
+		}
+
+		tableData := pterm.TableData{append([]string{"ID", "Name"}, routes...)}
+		for _, result := range results {
+			row := []string{
+				strconv.FormatUint(result.ID, headscale.Base10),
+				result.Name,
+			}
+			for index, route := range routes {
+				switch {
+				case result.Error == "":
+					row = append(row, namespaceRouteString(result.Routes[route]))
+				case index == 0:
+					row = append(row, pterm.LightRed("failed: "+result.Error))
+				default:
+					row = append(row, "")
+				}
+			}
+			tableData = append(tableData, row)
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	refreshKeyCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err := refreshKeyCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(refreshKeyCmd)
+}
+
+var refreshKeyCmd = &cobra.Command{
+	Use:   "refresh-key",
+	Short: "Make a node rotate its node key",
+	Long: `
+Expire the node key of a node so its client generates a new one, accepted
+without logging in again. The node keeps its IP addresses, name, routes and
+expiry.
+
+The node must be online to notice the expiry and submit its new key, it is
+listed with a pending key refresh until then. A client which does not
+submit it on its own completes the rotation with 'tailscale up'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.RefreshMachineKey(
+			ctx,
+			&v1.RefreshMachineKeyRequest{MachineId: identifier},
+		)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot refresh the key of the node: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(
+			response.GetMachine(),
+			fmt.Sprintf(
+				"Key refresh of %s requested, pending until the node submits its new key",
+				nodeTitle(response.GetMachine()),
+			),
+			output,
+		)
+	},
+}
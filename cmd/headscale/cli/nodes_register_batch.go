@@ -0,0 +1,293 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	errConflictingKeys = Error("only one of --key, --keys-file and --from-yaml can be used")
+	errKeysFileWait    = Error("--wait-for-routes cannot be used with --keys-file or --from-yaml")
+	errKeysFileIP      = Error("--ip cannot be used with --keys-file or --from-yaml")
+	errInvalidNodeSpec = Error("invalid node spec")
+
+	machineKeyRegistered = "registered"
+	machineKeyFailed     = "failed"
+)
+
+// machineKeyResult is the outcome of the registration of a key of
+// --keys-file.
+type machineKeyResult struct {
+	Key     string      `json:"key"`
+	Result  string      `json:"result"`
+	Error   string      `json:"error,omitempty"`
+	Machine *v1.Machine `json:"machine,omitempty"`
+}
+
+// machineRegistration is a registration of a batch, with the name to give
+// to the machine once registered.
+type machineRegistration struct {
+	request *v1.RegisterMachineRequest
+	name    string
+}
+
+// nodeRegistrationSpec is an entry of the YAML file of nodes register
+// --from-yaml.
+type nodeRegistrationSpec struct {
+	Key       string   `yaml:"key"`
+	Namespace string   `yaml:"namespace"`
+	Name      string   `yaml:"name"`
+	Tags      []string `yaml:"tags"`
+
+	line int
+}
+
+// nodeRegistrationSpecFields are the fields of a nodeRegistrationSpec.
+var nodeRegistrationSpecFields = []string{"key", "namespace", "name", "tags"}
+
+// UnmarshalYAML decodes an entry, refusing the unknown fields, and
+// records its line for the errors.
+func (spec *nodeRegistrationSpec) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: an entry must be a mapping: %w", value.Line, errInvalidNodeSpec)
+	}
+	for index := 0; index < len(value.Content); index += 2 {
+		field := value.Content[index]
+		if !contains(nodeRegistrationSpecFields, field.Value) {
+			return fmt.Errorf(
+				"line %d: unknown field %q, expected one of %s: %w",
+				field.Line,
+				field.Value,
+				strings.Join(nodeRegistrationSpecFields, ", "),
+				errInvalidNodeSpec,
+			)
+		}
+	}
+
+	type plain nodeRegistrationSpec
+	if err := value.Decode((*plain)(spec)); err != nil {
+		return err
+	}
+	spec.line = value.Line
+
+	return nil
+}
+
+// readNodeRegistrationSpecs reads and validates the entries of the YAML
+// file of --from-yaml, so nothing is registered when one of them is
+// invalid. The entries without a namespace get defaultNamespace, and must
+// have one when requireNamespace is set.
+func readNodeRegistrationSpecs(
+	path string,
+	defaultNamespace string,
+	requireNamespace bool,
+) ([]nodeRegistrationSpec, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var specs []nodeRegistrationSpec
+	if err := yaml.NewDecoder(file).Decode(&specs); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]int, len(specs))
+	for index := range specs {
+		spec := &specs[index]
+		if spec.Namespace == "" {
+			spec.Namespace = defaultNamespace
+		}
+
+		switch {
+		case spec.Key == "":
+			return nil, fmt.Errorf("line %d: key is required: %w", spec.line, errInvalidNodeSpec)
+		case requireNamespace && spec.Namespace == "":
+			return nil, fmt.Errorf(
+				"line %d: namespace is required without --namespace: %w",
+				spec.line,
+				errInvalidNodeSpec,
+			)
+		}
+		if line, ok := keys[spec.Key]; ok {
+			return nil, fmt.Errorf(
+				"line %d: key is already the one of line %d: %w",
+				spec.line,
+				line,
+				errInvalidNodeSpec,
+			)
+		}
+		keys[spec.Key] = spec.line
+
+		if spec.Name != "" {
+			if err := headscale.CheckForFQDNRules(spec.Name); err != nil {
+				return nil, fmt.Errorf("line %d: name: %s: %w", spec.line, err, errInvalidNodeSpec)
+			}
+		}
+		if err := validateTags(spec.Tags); err != nil {
+			return nil, fmt.Errorf("line %d: tags: %s: %w", spec.line, err, errInvalidNodeSpec)
+		}
+	}
+
+	return specs, nil
+}
+
+// nodeSpecRegistrations are the registrations of the entries of
+// --from-yaml, with the settings of request. The tags of an entry replace
+// the ones of request.
+func nodeSpecRegistrations(
+	specs []nodeRegistrationSpec,
+	request *v1.RegisterMachineRequest,
+) []machineRegistration {
+	registrations := make([]machineRegistration, len(specs))
+	for index, spec := range specs {
+		specRequest := proto.Clone(request).(*v1.RegisterMachineRequest)
+		specRequest.Key = spec.Key
+		specRequest.Namespace = spec.Namespace
+		if len(spec.Tags) > 0 {
+			specRequest.Tags = spec.Tags
+		}
+		registrations[index] = machineRegistration{request: specRequest, name: spec.Name}
+	}
+
+	return registrations
+}
+
+// readMachineKeys reads the machine keys of a file, one per line, skipping
+// the blank lines and the comments starting with #.
+func readMachineKeys(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	keys := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// machineKeyRegistrations are the registrations of keys with the settings
+// of request.
+func machineKeyRegistrations(
+	keys []string,
+	request *v1.RegisterMachineRequest,
+) []machineRegistration {
+	registrations := make([]machineRegistration, len(keys))
+	for index, key := range keys {
+		keyRequest := proto.Clone(request).(*v1.RegisterMachineRequest)
+		keyRequest.Key = key
+		registrations[index] = machineRegistration{request: keyRequest}
+	}
+
+	return registrations
+}
+
+// registerMachineKeys registers the machines of a batch, renaming the
+// ones with a name, going on after a failure, and reports the result of
+// each key. It exits with the code of the first failure, for the
+// provisioning scripts.
+func registerMachineKeys(
+	ctx context.Context,
+	client v1.HeadscaleServiceClient,
+	registrations []machineRegistration,
+	dryRun bool,
+	output string,
+) {
+	registered := 0
+	results := make([]machineKeyResult, len(registrations))
+	for index, registration := range registrations {
+		results[index] = machineKeyResult{
+			Key:    registration.request.GetKey(),
+			Result: machineKeyRegistered,
+		}
+		if dryRun {
+			results[index].Result = "would be " + machineKeyRegistered
+		}
+
+		response, err := client.RegisterMachine(ctx, registration.request)
+		if err != nil {
+			setExitCode(errorExitCode(err))
+			results[index].Result = machineKeyFailed
+			results[index].Error = status.Convert(err).Message()
+
+			continue
+		}
+		results[index].Machine = response.GetMachine()
+		registered++
+
+		if registration.name == "" {
+			continue
+		}
+		if dryRun {
+			results[index].Machine.GivenName = registration.name
+
+			continue
+		}
+		renameResponse, err := client.RenameMachine(ctx, &v1.RenameMachineRequest{
+			MachineId: response.GetMachine().GetId(),
+			NewName:   registration.name,
+		})
+		if err != nil {
+			setExitCode(errorExitCode(err))
+			results[index].Error = "cannot rename: " + status.Convert(err).Message()
+
+			continue
+		}
+		results[index].Machine = renameResponse.GetMachine()
+	}
+
+	if output != "" {
+		SuccessOutput(results, "", output)
+	} else {
+		tableData := pterm.TableData{{"Key", "Name", "Result"}}
+		for _, result := range results {
+			outcome := pterm.LightGreen(result.Result)
+			if result.Error != "" {
+				outcome = pterm.LightRed(fmt.Sprintf("%s: %s", result.Result, result.Error))
+			}
+
+			tableData = append(tableData, []string{
+				result.Key,
+				valueOrDash(result.Machine.GetGivenName()),
+				outcome,
+			})
+		}
+		err := pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+		}
+
+		if dryRun {
+			fmt.Printf("%d of %d machines would be registered\n", registered, len(registrations))
+		} else {
+			fmt.Printf("%d of %d machines registered\n", registered, len(registrations))
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	errNoNodeMatched      = Error("no node matched the selector")
+	errConflictingTarget  = Error("--identifier and --selector cannot be used together")
+	errInvalidConcurrency = Error("invalid concurrency, must be positive")
+
+	// defaultNodeConcurrency is the default of --concurrency.
+	defaultNodeConcurrency = 4
+)
+
+// nodeResult is the outcome of an operation on one of the nodes matched
+// by a selector.
+type nodeResult struct {
+	ID       uint64 `json:"id"`
+	Hostname string `json:"hostname"`
+	Result   string `json:"result"`
+	Error    string `json:"error,omitempty"`
+}
+
+type nodeOperation func(
+	ctx context.Context,
+	client v1.HeadscaleServiceClient,
+	machine *v1.Machine,
+) error
+
+func addNodeSelectorFlags(cmd *cobra.Command) {
+	cmd.Flags().
+		String("selector", "", "Target the nodes matching these labels instead of --identifier (e.g. env=staging,team=web)")
+	cmd.Flags().
+		Bool("allow-empty", false, "Do not fail when the selector matches no node")
+	addNodeConcurrencyFlag(cmd)
+}
+
+// addNodeConcurrencyFlag adds the --concurrency of the commands changing
+// several nodes with runOnNodes.
+func addNodeConcurrencyFlag(cmd *cobra.Command) {
+	cmd.Flags().
+		Int("concurrency", defaultNodeConcurrency, "How many nodes to change at the same time")
+}
+
+// missingNodeTarget reports, as a command error, that neither --identifier
+// nor --selector was given.
+func missingNodeTarget(output string) {
+	ErrorOutput(
+		errMissingParameter,
+		"Please specify either --identifier or --selector",
+		output,
+	)
+}
+
+// runOnSelectedNodes applies operation to every node matching the
+// --selector of the command and reports the result of each node.
+// Destructive operations are confirmed first, unless forced (see
+// forceExecution).
+func runOnSelectedNodes(
+	cmd *cobra.Command,
+	action string,
+	done string,
+	destructive bool,
+	operation nodeOperation,
+) {
+	output, _ := cmd.Flags().GetString("output")
+	selector, _ := cmd.Flags().GetString("selector")
+	allowEmpty, _ := cmd.Flags().GetBool("allow-empty")
+
+	if cmd.Flags().Changed("identifier") {
+		ErrorOutput(errConflictingTarget, errConflictingTarget.Error(), output)
+
+		return
+	}
+
+	ctx, client, conn, cancel := getHeadscaleCLIClient()
+	defer cancel()
+	defer conn.Close()
+
+	machines, err := listMachines(
+		ctx,
+		client,
+		&v1.ListMachinesRequest{LabelSelector: selector},
+		0,
+	)
+	if err != nil {
+		ErrorOutput(
+			err,
+			fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+			output,
+		)
+
+		return
+	}
+
+	if len(machines) == 0 {
+		if allowEmpty {
+			SuccessOutput([]nodeResult{}, "No node matched the selector", output)
+
+			return
+		}
+
+		ErrorOutput(
+			errNoNodeMatched,
+			fmt.Sprintf("No node matched the selector %q, use --allow-empty to ignore", selector),
+			output,
+		)
+
+		return
+	}
+
+	runOnNodes(client, cmd, machines, action, done, destructive, operation)
+}
+
+// runOnNodes applies operation to machines and reports the result of each
+// node. Destructive operations are confirmed first, unless forced (see
+// forceExecution).
+// When the command has a --dry-run flag and it is set, the nodes are only
+// listed. The operations run on --concurrency nodes at the same time, the
+// results keep the order of machines.
+func runOnNodes(
+	client v1.HeadscaleServiceClient,
+	cmd *cobra.Command,
+	machines []*v1.Machine,
+	action string,
+	done string,
+	destructive bool,
+	operation nodeOperation,
+) {
+	output, _ := cmd.Flags().GetString("output")
+	force := forceExecution(cmd)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	if concurrency < 1 {
+		ErrorOutput(errInvalidConcurrency, errInvalidConcurrency.Error(), output)
+
+		return
+	}
+
+	if destructive && !force && !dryRun {
+		tableData := pterm.TableData{{"ID", "Hostname", "Name", "Namespace"}}
+		for _, machine := range machines {
+			tableData = append(tableData, []string{
+				strconv.FormatUint(machine.GetId(), headscale.Base10),
+				machine.GetName(),
+				machine.GetGivenName(),
+				machine.GetNamespace().GetName(),
+			})
+		}
+		err := pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Do you want to %s these %d nodes?", action, len(machines)),
+		}
+		err = survey.AskOne(prompt, &confirm)
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error reading the confirmation: %s", err), output)
+
+			return
+		}
+		if !confirm {
+			SuccessOutput([]nodeResult{}, "No node changed", output)
+
+			return
+		}
+	}
+
+	errs := make([]error, len(machines))
+	if !dryRun {
+		// The timeout applies to each operation, a large batch of nodes
+		// must not fail for exceeding it as a whole.
+		cfg, err := headscale.GetHeadscaleConfig()
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Error loading the configuration: %s", err), output)
+
+			return
+		}
+		errs = runNodeOperations(client, machines, concurrency, cfg.CLI.Timeout, operation)
+	}
+
+	succeeded := 0
+	results := make([]nodeResult, len(machines))
+	for index, machine := range machines {
+		results[index] = nodeResult{
+			ID:       machine.GetId(),
+			Hostname: machine.GetName(),
+			Result:   done,
+		}
+
+		if dryRun {
+			results[index].Result = "would be " + done
+
+			continue
+		}
+
+		if err := errs[index]; err != nil {
+			setExitCode(errorExitCode(err))
+			results[index].Result = "failed"
+			results[index].Error = status.Convert(err).Message()
+
+			continue
+		}
+		succeeded++
+	}
+
+	if output != "" {
+		SuccessOutput(results, "", output)
+
+		return
+	}
+
+	tableData := pterm.TableData{{"ID", "Hostname", "Result"}}
+	for _, result := range results {
+		outcome := pterm.LightGreen(result.Result)
+		if result.Error != "" {
+			outcome = pterm.LightRed(fmt.Sprintf("%s: %s", result.Result, result.Error))
+		}
+
+		tableData = append(tableData, []string{
+			strconv.FormatUint(result.ID, headscale.Base10),
+			result.Hostname,
+			outcome,
+		})
+	}
+	err := pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+	if err != nil {
+		ErrorOutput(
+			err,
+			fmt.Sprintf("Failed to render pterm table: %s", err),
+			output,
+		)
+
+		return
+	}
+
+	if dryRun {
+		fmt.Printf("%d nodes would be %s\n", len(machines), done)
+	} else {
+		fmt.Printf("%d of %d nodes %s\n", succeeded, len(machines), done)
+	}
+}
+
+// runNodeOperations applies operation to machines with a pool of
+// concurrency workers, and returns the error of each machine, in the order
+// of machines. Each operation runs within its own timeout.
+func runNodeOperations(
+	client v1.HeadscaleServiceClient,
+	machines []*v1.Machine,
+	concurrency int,
+	timeout time.Duration,
+	operation nodeOperation,
+) []error {
+	errs := make([]error, len(machines))
+	indexes := make(chan int)
+
+	var workers sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			// Each index is sent once, so the workers never write the
+			// same error.
+			for index := range indexes {
+				ctx, cancel := context.WithTimeout(context.Background(), timeout)
+				errs[index] = operation(ctx, client, machines[index])
+				cancel()
+			}
+		}()
+	}
+
+	for index := range machines {
+		indexes <- index
+	}
+	close(indexes)
+	workers.Wait()
+
+	return errs
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+const errInvalidTag = Error("invalid tag, tags must start with 'tag:'")
+
+func init() {
+	untagCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err := untagCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	untagCmd.Flags().
+		StringSliceP("tags", "t", []string{}, "List of forced tags to remove from the node")
+	err = untagCmd.MarkFlagRequired("tags")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(untagCmd)
+}
+
+// validateTags checks the tags follow the Tailscale convention, so they
+// are refused before any node is changed.
+func validateTags(tags []string) error {
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, "tag:") {
+			return fmt.Errorf("%q: %w", tag, errInvalidTag)
+		}
+	}
+
+	return nil
+}
+
+// effectiveTags are the tags a node has: its forced tags and the tags it
+// requested which are allowed by the ACL policy.
+func effectiveTags(machine *v1.Machine) []string {
+	tags := append([]string{}, machine.GetForcedTags()...)
+	for _, tag := range machine.GetValidTags() {
+		if !contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	return tags
+}
+
+// nodeHasTags tells if a node has all the tags, forced or requested by the
+// node itself.
+func nodeHasTags(machine *v1.Machine, tags []string) bool {
+	for _, tag := range tags {
+		if !contains(machine.GetForcedTags(), tag) &&
+			!contains(machine.GetValidTags(), tag) &&
+			!contains(machine.GetInvalidTags(), tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tagsUpdatedMessage is the confirmation of a change of the tags of a node.
+func tagsUpdatedMessage(machine *v1.Machine) string {
+	return fmt.Sprintf(
+		"Machine updated, tags of %s: %s",
+		nodeTitle(machine),
+		valueOrDash(strings.Join(effectiveTags(machine), ", ")),
+	)
+}
+
+var untagCmd = &cobra.Command{
+	Use:   "untag",
+	Short: "Remove forced tags from a node",
+	Long: `
+Remove the given tags from the tags forced on a node, keeping the other
+ones. The tags requested by the node itself cannot be removed here, as they
+come from its configuration and are checked against the ACL policy.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		tagsToRemove, _ := cmd.Flags().GetStringSlice("tags")
+		if err := validateTags(tagsToRemove); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.GetMachine(ctx, &v1.GetMachineRequest{MachineId: identifier})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get node: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+		machine := response.GetMachine()
+
+		if err := checkManagedNode(machine, forceExecution(cmd)); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+
+		for _, tag := range tagsToRemove {
+			if !contains(machine.GetForcedTags(), tag) {
+				fmt.Fprintf(os.Stderr, "Warning: %s is not forced on %s\n", tag, nodeTitle(machine))
+			}
+		}
+		tags := []string{}
+		for _, tag := range machine.GetForcedTags() {
+			if !contains(tagsToRemove, tag) {
+				tags = append(tags, tag)
+			}
+		}
+
+		tagsResponse, err := client.SetTags(ctx, &v1.SetTagsRequest{
+			MachineId: identifier,
+			Tags:      tags,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Error while sending tags to headscale: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(tagsResponse.GetMachine(), tagsUpdatedMessage(tagsResponse.GetMachine()), output)
+	},
+}
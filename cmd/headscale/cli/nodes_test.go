@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"tailscale.com/types/key"
+)
+
+func TestNodesToPtablesColumns(t *testing.T) {
+	machines := []*v1.Machine{{
+		Id:        1,
+		NodeKey:   headscale.NodePublicKeyStripPrefix(key.NewNode().Public()),
+		Namespace: &v1.Namespace{Name: "test"},
+	}}
+	defaultColumns := []string{
+		"ID",
+		"Hostname",
+		"Name",
+		"NodeKey",
+		"Namespace",
+		"IP addresses",
+		"Ephemeral",
+		"Last seen",
+		"Online",
+		"Expired",
+	}
+
+	tests := []struct {
+		name    string
+		options nodeTableOptions
+		want    []string
+	}{
+		{
+			name:    "default columns",
+			options: nodeTableOptions{},
+			want:    defaultColumns,
+		},
+		{
+			name: "optional columns",
+			options: nodeTableOptions{
+				showAuthKey:     true,
+				showQuarantined: true,
+				showManaged:     true,
+				showDERP:        true,
+				showFlaps:       true,
+			},
+			want: append(
+				append([]string{}, defaultColumns...),
+				"Auth key",
+				"Quarantined",
+				"Managed",
+				"DERP",
+				"Flaps",
+			),
+		},
+		{
+			name:    "DERP names",
+			options: nodeTableOptions{showDERPNames: true},
+			want:    append(append([]string{}, defaultColumns...), "DERP"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tableData, err := nodesToPtables(tt.options, machines)
+			if err != nil {
+				t.Fatalf("nodesToPtables() error = %v", err)
+			}
+			if !reflect.DeepEqual(tableData[0], tt.want) {
+				t.Errorf("nodesToPtables() header = %v, want %v", tableData[0], tt.want)
+			}
+			if len(tableData[1]) != len(tt.want) {
+				t.Errorf("nodesToPtables() row has %d cells, want %d", len(tableData[1]), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestUniqueIdentifiers(t *testing.T) {
+	got := uniqueIdentifiers([]uint{3, 1, 3, 2, 1})
+	if want := []uint{3, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("uniqueIdentifiers() = %v, want %v", got, want)
+	}
+}
+
+func TestRunNodeOperationsOrder(t *testing.T) {
+	machines := make([]*v1.Machine, 5)
+	for index := range machines {
+		machines[index] = &v1.Machine{Id: uint64(index + 1)}
+	}
+
+	// The first machines take the longest, so they finish last.
+	errs := runNodeOperations(nil, machines, 3, time.Minute, func(
+		ctx context.Context,
+		client v1.HeadscaleServiceClient,
+		machine *v1.Machine,
+	) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Errorf("operation of machine %d has no deadline", machine.GetId())
+		}
+		time.Sleep(time.Duration(len(machines)-int(machine.GetId())) * 10 * time.Millisecond)
+
+		return fmt.Errorf("machine %d", machine.GetId())
+	})
+
+	if len(errs) != len(machines) {
+		t.Fatalf("runNodeOperations() returned %d errors, want %d", len(errs), len(machines))
+	}
+	for index, err := range errs {
+		if want := fmt.Sprintf("machine %d", index+1); err == nil || err.Error() != want {
+			t.Errorf("runNodeOperations() error %d = %v, want %s", index, err, want)
+		}
+	}
+}
+
+func TestApplyColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr error
+	}{
+		{name: "no columns", args: []string{"--tags"}, want: []string{"tags"}},
+		{name: "default", args: []string{"--columns", "default"}, want: []string{}},
+		{name: "all", args: []string{"--columns", "all"}, want: nodeColumnFlags},
+		{
+			name: "list",
+			args: []string{"--columns", "derp,flaps", "--tags"},
+			want: []string{"derp", "flaps", "tags"},
+		},
+		{
+			name:    "all with a column flag",
+			args:    []string{"--columns", "all", "--tags"},
+			wantErr: errConflictingColumns,
+		},
+		{
+			name:    "default with a column",
+			args:    []string{"--columns", "default,tags"},
+			wantErr: errConflictingColumns,
+		},
+		{
+			name:    "unknown column",
+			args:    []string{"--columns", "foo"},
+			wantErr: errUnknownColumn,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addNodeTableFlags(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("ParseFlags() error = %v", err)
+			}
+
+			err := applyColumns(cmd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyColumns() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			got := []string{}
+			for _, name := range nodeColumnFlags {
+				if set, _ := cmd.Flags().GetBool(name); set {
+					got = append(got, name)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("applyColumns() set %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
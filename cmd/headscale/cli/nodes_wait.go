@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"inet.af/netaddr"
+)
+
+const (
+	errRoutesNotAdvertised = Error("routes not advertised by the node before the timeout")
+
+	waitForRoutesInterval = time.Second
+)
+
+// routesWaitResult reports the outcome of waiting for the routes of a
+// newly registered node.
+type routesWaitResult struct {
+	Machine    *v1.Machine `json:"machine"`
+	Advertised []string    `json:"advertised"`
+	Missing    []string    `json:"missing"`
+	Enabled    []string    `json:"enabled"`
+	TimedOut   bool        `json:"timed_out"`
+}
+
+// normalizeRoutes parses the routes given on the command line, so they
+// compare equal to the ones reported by the server.
+func normalizeRoutes(routes []string) ([]string, error) {
+	normalized := make([]string, 0, len(routes))
+	for _, routeStr := range routes {
+		route, err := netaddr.ParseIPPrefix(routeStr)
+		if err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, route.String())
+	}
+
+	return normalized, nil
+}
+
+// waitForRoutes polls the routes of a machine until it advertises all the
+// given routes or the timeout expires, then enables them when enable is
+// set, keeping the routes already enabled.
+func waitForRoutes(
+	client v1.HeadscaleServiceClient,
+	machine *v1.Machine,
+	routes []string,
+	timeout time.Duration,
+	enable bool,
+) (*routesWaitResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result := &routesWaitResult{Machine: machine}
+
+	ticker := time.NewTicker(waitForRoutesInterval)
+	defer ticker.Stop()
+
+	var machineRoutes *v1.Routes
+	for {
+		response, err := client.GetMachineRoute(ctx, &v1.GetMachineRouteRequest{
+			MachineId: machine.GetId(),
+		})
+		if err != nil && ctx.Err() == nil {
+			return nil, err
+		}
+		if err == nil {
+			machineRoutes = response.GetRoutes()
+		}
+
+		result.Advertised = []string{}
+		result.Missing = []string{}
+		for _, route := range routes {
+			if contains(machineRoutes.GetAdvertisedRoutes(), route) {
+				result.Advertised = append(result.Advertised, route)
+			} else {
+				result.Missing = append(result.Missing, route)
+			}
+		}
+		if len(result.Missing) == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			result.TimedOut = true
+
+			return result, fmt.Errorf(
+				"%w: missing %v after %s",
+				errRoutesNotAdvertised,
+				result.Missing,
+				timeout,
+			)
+		case <-ticker.C:
+		}
+	}
+
+	if !enable {
+		return result, nil
+	}
+
+	enabledRoutes := machineRoutes.GetEnabledRoutes()
+	for _, route := range routes {
+		if !contains(enabledRoutes, route) {
+			enabledRoutes = append(enabledRoutes, route)
+		}
+	}
+
+	enableCtx, enableCancel := context.WithTimeout(context.Background(), timeout)
+	defer enableCancel()
+
+	response, err := client.EnableMachineRoutes(enableCtx, &v1.EnableMachineRoutesRequest{
+		MachineId: machine.GetId(),
+		Routes:    enabledRoutes,
+	})
+	if err != nil {
+		return result, err
+	}
+	result.Enabled = response.GetRoutes().GetEnabledRoutes()
+
+	return result, nil
+}
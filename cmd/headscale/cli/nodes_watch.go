@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	errInvalidWatchInterval = Error("invalid interval, must be positive")
+
+	// clearScreen moves the cursor to the top left corner of the terminal
+	// and clears it.
+	clearScreen = "\033[H\033[2J"
+)
+
+func init() {
+	watchNodesCmd.Flags().
+		StringP("namespace", "n", "", "Filter by namespace, or by a pattern of namespaces (e.g. 'team-*')")
+	watchNodesCmd.Flags().Duration("interval", 5*time.Second, "How often to refresh the nodes")
+	addNodeTableFlags(watchNodesCmd)
+	nodeCmd.AddCommand(watchNodesCmd)
+}
+
+var watchNodesCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "List the nodes, refreshed every --interval",
+	Long: `
+List the nodes like 'headscale nodes list', refreshing the table in place
+every --interval until interrupted with Ctrl-C, for a dashboard. It takes
+the --namespace and the column flags of nodes list.
+
+With --output, the nodes are printed again at each refresh instead, one
+document per refresh with json-line.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+		if err := applyColumns(cmd); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+		namespace, _ := cmd.Flags().GetString("namespace")
+		pattern, err := namespacePattern(namespace)
+		if err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+		if pattern != "" {
+			namespace = ""
+		}
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			ErrorOutput(errInvalidWatchInterval, errInvalidWatchInterval.Error(), output)
+
+			return
+		}
+
+		cfg, err := headscale.GetHeadscaleConfig()
+		if err != nil {
+			ErrorOutput(err, fmt.Sprintf("Failed to load configuration: %s", err), output)
+
+			return
+		}
+
+		cmdCtx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		if namespace != "" {
+			if err := checkNamespaceExists(cmdCtx, client, namespace); err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+					output,
+				)
+
+				return
+			}
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			// Each refresh has the timeout of a command, the one of
+			// getHeadscaleCLIClient expiring during the watch.
+			refreshCtx, refreshCancel := context.WithTimeout(ctx, cfg.CLI.Timeout)
+			err := refreshNodes(
+				refreshCtx,
+				cmd,
+				client,
+				namespace,
+				pattern,
+				cfg.CLI.HideKeysByDefault,
+				output,
+			)
+			refreshCancel()
+			if err != nil && ctx.Err() == nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf("Cannot get nodes: %s", status.Convert(err).Message()),
+					output,
+				)
+
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// refreshNodes prints the nodes once for nodes watch, clearing the
+// terminal before the table. A pattern keeps the nodes of the matching
+// namespaces, see namespacePattern.
+func refreshNodes(
+	ctx context.Context,
+	cmd *cobra.Command,
+	client v1.HeadscaleServiceClient,
+	namespace string,
+	pattern string,
+	hideKeys bool,
+	output string,
+) error {
+	machines, err := listMachines(ctx, client, &v1.ListMachinesRequest{Namespace: namespace}, 0)
+	if err != nil {
+		return err
+	}
+	if pattern != "" {
+		machines = matchNamespacePattern(machines, pattern)
+	}
+
+	if output != "" {
+		SuccessOutput(machines, "", output)
+
+		return nil
+	}
+
+	tableData, err := nodesTable(cmd, namespace, hideKeys, false, defaultOnlineThreshold, machines)
+	if err != nil {
+		return err
+	}
+	if err := truncateTable(cmd, tableData); err != nil {
+		return err
+	}
+	alignTable(tableData, nodeColumnAlignments)
+
+	if isTerminal() {
+		fmt.Print(clearScreen)
+	}
+	fmt.Printf("Every %s, last refresh %s\n\n", cmd.Flag("interval").Value, time.Now().Format(HeadscaleDateTimeFormat))
+
+	return pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+}
@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"inet.af/netaddr"
+	"tailscale.com/types/key"
+)
+
+func init() {
+	wireGuardConfigCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	err := wireGuardConfigCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+	nodeCmd.AddCommand(wireGuardConfigCmd)
+}
+
+// wireGuardPeer is a node described as a peer of a WireGuard interface.
+type wireGuardPeer struct {
+	PublicKey  string   `json:"public_key"`
+	AllowedIPs []string `json:"allowed_ips"`
+	// Endpoint is empty when the node has not reported any.
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+var wireGuardConfigCmd = &cobra.Command{
+	Use:   "wg-config",
+	Short: "Print a node as a WireGuard [Peer] section",
+	Long: `
+Print the public key, IP addresses and endpoint of a node in the [Peer]
+format of a WireGuard configuration, to reach it from a plain WireGuard
+peer configured by hand.
+
+This is a best-effort interop aid, not a supported way to join the
+tailnet: the node only accepts the peers it is told about by Headscale,
+and its endpoint changes with its network. The first endpoint reported by
+the node is used, and the Endpoint line is left out when it has not
+reported any.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		identifier, err := cmd.Flags().GetUint64("identifier")
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error converting ID to integer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.GetMachine(ctx, &v1.GetMachineRequest{MachineId: identifier})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get node: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		}
+
+		peer, err := nodeToWireGuardPeer(response.GetMachine())
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot convert node to a WireGuard peer: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(peer, "", output)
+
+			return
+		}
+
+		fmt.Printf("# %s, best-effort interop only\n", nodeTitle(response.GetMachine()))
+		fmt.Println("[Peer]")
+		fmt.Printf("PublicKey = %s\n", peer.PublicKey)
+		fmt.Printf("AllowedIPs = %s\n", strings.Join(peer.AllowedIPs, ", "))
+		if peer.Endpoint == "" {
+			fmt.Println("# No endpoint reported by the node")
+		} else {
+			fmt.Printf("Endpoint = %s\n", peer.Endpoint)
+		}
+	},
+}
+
+// nodeToWireGuardPeer converts the node key to the base64 encoding of
+// WireGuard and its IP addresses to host prefixes.
+func nodeToWireGuardPeer(machine *v1.Machine) (wireGuardPeer, error) {
+	var nodeKey key.NodePublic
+	err := nodeKey.UnmarshalText(
+		[]byte(headscale.NodePublicKeyEnsurePrefix(machine.GetNodeKey())),
+	)
+	if err != nil {
+		return wireGuardPeer{}, err
+	}
+	raw := nodeKey.Raw32()
+
+	peer := wireGuardPeer{
+		PublicKey:  base64.StdEncoding.EncodeToString(raw[:]),
+		AllowedIPs: make([]string, 0, len(machine.GetIpAddresses())),
+	}
+	for _, address := range machine.GetIpAddresses() {
+		ip, err := netaddr.ParseIP(address)
+		if err != nil {
+			return wireGuardPeer{}, err
+		}
+		peer.AllowedIPs = append(peer.AllowedIPs, netaddr.IPPrefixFrom(ip, ip.BitLen()).String())
+	}
+	if len(machine.GetEndpoints()) > 0 {
+		peer.Endpoint = machine.GetEndpoints()[0]
+	}
+
+	return peer, nil
+}
@@ -1,7 +1,12 @@
 package cli
 
 import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/pterm/pterm"
 )
@@ -17,3 +22,121 @@ func ColourTime(date time.Time) string {
 
 	return dateStr
 }
+
+// columnAlignment is how the cells of a column of a table are aligned.
+type columnAlignment int
+
+const (
+	alignLeft columnAlignment = iota
+	alignRight
+)
+
+// alignTable pads the cells of the columns of tableData right aligned in
+// alignments, by the columnKey of their header, to the width of the
+// column. pterm pads the cells on the right, aligning the other columns on
+// the left.
+func alignTable(tableData pterm.TableData, alignments map[string]columnAlignment) {
+	if len(tableData) == 0 {
+		return
+	}
+
+	for index, header := range tableData[0] {
+		if alignments[columnKey(header)] != alignRight {
+			continue
+		}
+
+		width := 0
+		for _, row := range tableData {
+			if index < len(row) {
+				if cellWidth := utf8.RuneCountInString(pterm.RemoveColorFromString(row[index])); cellWidth > width {
+					width = cellWidth
+				}
+			}
+		}
+		for _, row := range tableData {
+			if index < len(row) {
+				padding := width - utf8.RuneCountInString(pterm.RemoveColorFromString(row[index]))
+				row[index] = strings.Repeat(" ", padding) + row[index]
+			}
+		}
+	}
+}
+
+// colourCode matches an escape sequence of pterm setting a colour at the
+// start of a string.
+var colourCode = regexp.MustCompile(`^\x1b\[[0-9;]*m`)
+
+// truncateCell shortens a cell longer than width characters, replacing its
+// end by an ellipsis. The colour codes are kept and not counted.
+func truncateCell(cell string, width int) string {
+	plain := pterm.RemoveColorFromString(cell)
+	if width <= 0 || utf8.RuneCountInString(plain) <= width {
+		return cell
+	}
+
+	var truncated strings.Builder
+	visible := 0
+	for rest := cell; rest != ""; {
+		if code := colourCode.FindString(rest); code != "" {
+			truncated.WriteString(code)
+			rest = rest[len(code):]
+
+			continue
+		}
+		if visible == width-1 {
+			break
+		}
+		char, size := utf8.DecodeRuneInString(rest)
+		truncated.WriteRune(char)
+		visible++
+		rest = rest[size:]
+	}
+	truncated.WriteString("…")
+	// The codes resetting the colours may have been cut.
+	if plain != cell {
+		truncated.WriteString("\x1b[0m")
+	}
+
+	return truncated.String()
+}
+
+// isTerminal tells if the standard output is a terminal, where the
+// colours of the tables are rendered.
+func isTerminal() bool {
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// setColours disables the colours of the output with --no-color, when the
+// NO_COLOR environment variable is set (https://no-color.org) or when the
+// standard output is not a terminal, so piped output stays plain text.
+func setColours(noColour bool) {
+	if noColour || os.Getenv("NO_COLOR") != "" || !isTerminal() {
+		pterm.DisableColor()
+	}
+}
+
+// nodesLegend explains the colours of the table of nodesToPtables.
+var nodesLegend = [][2]string{
+	{pterm.LightGreen("green"), "online, not expired, valid tag, netmap up to date, seen within 5 minutes"},
+	{pterm.LightRed("red"), "offline, expired, quarantined, invalid tag, netmap outdated, not seen for an hour"},
+	{pterm.LightMagenta("magenta"), "namespace of the node"},
+	{pterm.LightYellow("yellow"), "namespace shared into the listed one, forced DERP region, expiring within 24 hours, seen within an hour"},
+}
+
+// printLegend prints the meaning of the colours of a table, only when they
+// are shown.
+func printLegend(legend [][2]string) {
+	if !isTerminal() || !pterm.PrintColor {
+		return
+	}
+
+	fmt.Println("Legend:")
+	for _, entry := range legend {
+		fmt.Printf("  %s: %s\n", entry[0], entry[1])
+	}
+}
@@ -4,24 +4,54 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"time"
 
 	"github.com/juanfont/headscale"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/tcnksm/go-latest"
 )
 
 var cfgFile string = ""
 
+// logFormat is the --log-format of the command, see logOutput.
+var logFormat = logFormatText
+
+// retries is the --retries of the command, see retryInterceptor.
+var retries = defaultRetries
+
+const (
+	// defaultCLITimeout is the default of cli.timeout.
+	defaultCLITimeout = 5 * time.Second
+
+	// defaultRetries is the default of --retries.
+	defaultRetries = 3
+
+	errInvalidRetries = Error("invalid --retries, must not be negative")
+)
+
 func init() {
 	cobra.OnInitialize(initConfig)
 	rootCmd.PersistentFlags().
 		StringVarP(&cfgFile, "config", "c", "", "config file (default is /etc/headscale/config.yaml)")
 	rootCmd.PersistentFlags().
-		StringP("output", "o", "", "Output format. Empty for human-readable, 'json', 'json-line' or 'yaml'")
+		StringP("output", "o", "", "Output format. Empty for human-readable, 'json', 'json-line', 'yaml' or 'csv' (nodes list)")
+	rootCmd.PersistentFlags().
+		Bool("force", false, "Disable prompts and forces the execution (default from HEADSCALE_CLI_ASSUME_YES)")
+	rootCmd.PersistentFlags().
+		Duration("timeout", defaultCLITimeout, "How long to wait for the server to answer (overrides cli.timeout)")
+	rootCmd.PersistentFlags().
+		StringVar(&logFormat, "log-format", logFormatText, "Also log the result of the command as a JSON line on the standard error with 'json'")
 	rootCmd.PersistentFlags().
-		Bool("force", false, "Disable prompts and forces the execution")
+		Bool("no-color", false, "Disable the colours of the output (default when NO_COLOR is set or not on a terminal)")
+	rootCmd.PersistentFlags().
+		IntVar(&retries, "retries", defaultRetries, "How many times to retry the read-only requests when the server is unavailable, 0 to disable")
+	err := viper.BindPFlag("cli.timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
 }
 
 func initConfig() {
@@ -42,8 +72,18 @@ func initConfig() {
 		log.Fatal().Caller().Err(err)
 	}
 
+	if logFormat != logFormatText && logFormat != logFormatJSON {
+		log.Fatal().Str("log-format", logFormat).Msg(errInvalidLogFormat.Error())
+	}
+	if retries < 0 {
+		log.Fatal().Int("retries", retries).Msg(errInvalidRetries.Error())
+	}
+
 	machineOutput := HasMachineOutputFlag()
 
+	noColour, _ := rootCmd.PersistentFlags().GetBool("no-color")
+	setColours(noColour)
+
 	zerolog.SetGlobalLevel(cfg.LogLevel)
 
 	// If the user has requested a "machine" readable format,
@@ -52,7 +92,11 @@ func initConfig() {
 		zerolog.SetGlobalLevel(zerolog.Disabled)
 	}
 
-	if !cfg.DisableUpdateCheck && !machineOutput {
+	// The output of the shell completions is parsed by the shell.
+	completion := len(os.Args) > 1 &&
+		(os.Args[1] == cobra.ShellCompRequestCmd || os.Args[1] == cobra.ShellCompNoDescRequestCmd)
+
+	if !cfg.DisableUpdateCheck && !machineOutput && !completion {
 		if (runtime.GOOS == "linux" || runtime.GOOS == "darwin") &&
 			Version != "dev" {
 			githubTag := &latest.GithubTag{
@@ -81,9 +125,13 @@ headscale is an open source implementation of the Tailscale control server
 https://github.com/juanfont/headscale`,
 }
 
+// exitCode is the exit code of the command, set by ErrorOutput.
+var exitCode = exitCodeSuccess
+
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCodeError)
 	}
+	os.Exit(exitCode)
 }
@@ -33,6 +33,19 @@ func init() {
 
 	routesCmd.AddCommand(enableRouteCmd)
 
+	disableRouteCmd.Flags().
+		StringSliceP("route", "r", []string{}, "List (or repeated flags) of routes to disable")
+	disableRouteCmd.Flags().Uint64P("identifier", "i", 0, "Node identifier (ID)")
+	disableRouteCmd.Flags().BoolP("all", "a", false, "All the enabled routes of the node")
+	disableRouteCmd.Flags().Bool("dry-run", false, "Only show the routes that would be disabled")
+
+	err = disableRouteCmd.MarkFlagRequired("identifier")
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	routesCmd.AddCommand(disableRouteCmd)
+
 	nodeCmd.AddCommand(routesCmd)
 }
 
@@ -131,6 +144,12 @@ omit the route you do not want to enable.
 		defer cancel()
 		defer conn.Close()
 
+		if err := checkManagedNodeByID(ctx, client, machineID, forceExecution(cmd)); err != nil {
+			ErrorOutput(err, err.Error(), output)
+
+			return
+		}
+
 		var routes []string
 
 		isAll, _ := cmd.Flags().GetBool("all")
@@ -209,6 +228,148 @@ omit the route you do not want to enable.
 	},
 }
 
+// disabledRoute reports what disabling a route did.
+type disabledRoute struct {
+	Route  string `json:"route"`
+	Result string `json:"result"`
+}
+
+var disableRouteCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable some or all the enabled routes of a given node",
+	Long: `
+Disable the given routes of a node, or all its enabled routes with --all,
+for instance before deleting a subnet router.
+
+The enabled routes the node does not advertise anymore are disabled too, as
+they cannot be kept enabled.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		machineID, _ := cmd.Flags().GetUint64("identifier")
+		isAll, _ := cmd.Flags().GetBool("all")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		routesArgs, _ := cmd.Flags().GetStringSlice("route")
+		if isAll == (len(routesArgs) > 0) {
+			ErrorOutput(
+				errMissingParameter,
+				"Please specify either --route or --all",
+				output,
+			)
+
+			return
+		}
+
+		routesToDisable, err := normalizeRoutes(routesArgs)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error parsing routes: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		if !dryRun {
+			if err := checkManagedNodeByID(ctx, client, machineID, forceExecution(cmd)); err != nil {
+				ErrorOutput(err, err.Error(), output)
+
+				return
+			}
+		}
+
+		response, err := client.GetMachineRoute(ctx, &v1.GetMachineRouteRequest{
+			MachineId: machineID,
+		})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get machine routes: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+		routes := response.GetRoutes()
+
+		disabled := []disabledRoute{}
+		kept := []string{}
+		for _, route := range routes.GetEnabledRoutes() {
+			switch {
+			case isAll || isStringInSlice(routesToDisable, route):
+				disabled = append(disabled, disabledRoute{Route: route, Result: "disabled"})
+			case !isStringInSlice(routes.GetAdvertisedRoutes(), route):
+				disabled = append(disabled, disabledRoute{
+					Route:  route,
+					Result: "disabled, not advertised anymore",
+				})
+			default:
+				kept = append(kept, route)
+			}
+		}
+		for _, route := range routesToDisable {
+			if !isStringInSlice(routes.GetEnabledRoutes(), route) {
+				disabled = append(disabled, disabledRoute{Route: route, Result: "not enabled"})
+			}
+		}
+
+		if dryRun {
+			for index := range disabled {
+				if disabled[index].Result != "not enabled" {
+					disabled[index].Result = "would be " + disabled[index].Result
+				}
+			}
+		} else {
+			_, err = client.EnableMachineRoutes(ctx, &v1.EnableMachineRoutesRequest{
+				MachineId: machineID,
+				Routes:    kept,
+			})
+			if err != nil {
+				ErrorOutput(
+					err,
+					fmt.Sprintf(
+						"Cannot disable machine routes: %s",
+						status.Convert(err).Message(),
+					),
+					output,
+				)
+
+				return
+			}
+		}
+
+		if output != "" {
+			SuccessOutput(disabled, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{{"Route", "Result"}}
+		for _, route := range disabled {
+			tableData = append(tableData, []string{route.Route, route.Result})
+		}
+
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
 // routesToPtables converts the list of routes to a nice table.
 func routesToPtables(routes *v1.Routes) pterm.TableData {
 	tableData := pterm.TableData{{"Route", "Enabled"}}
@@ -3,10 +3,18 @@ package cli
 import (
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func init() {
 	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().
+		Bool("normalize-names", true, "Derive DNS-safe node names from their hostname (overrides normalize_names)")
+	err := viper.BindPFlag("normalize_names", serveCmd.Flags().Lookup("normalize-names"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("")
+	}
 }
 
 var serveCmd = &cobra.Command{
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/prometheus/common/model"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	DefaultStatsPeriod = "30d"
+	DefaultStatsBucket = "1d"
+
+	statsChartWidth = 40
+)
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsNodesCmd.Flags().
+		String("since", DefaultStatsPeriod, "Human-readable period to look back (e.g. 24h, 30d)")
+	statsNodesCmd.Flags().
+		String("bucket", DefaultStatsBucket, "Human-readable interval of the buckets (e.g. 1h, 1d)")
+	statsNodesCmd.Flags().StringP("namespace", "n", "", "Only count the nodes of this namespace")
+	statsNodesCmd.Flags().
+		Bool("per-namespace", false, "Break the counts down per namespace")
+	statsCmd.AddCommand(statsNodesCmd)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about Headscale",
+}
+
+var statsNodesCmd = &cobra.Command{
+	Use:   "nodes",
+	Short: "Show the number of registered and active nodes over time",
+	Long: `
+Show the number of registered and active (connected and not expired) nodes
+over time, grouped in buckets. The counts come from snapshots taken by the
+server every 15 minutes, buckets without snapshot are not shown.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		sinceStr, _ := cmd.Flags().GetString("since")
+		since, err := model.ParseDuration(sinceStr)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Could not parse duration: %s\n", err),
+				output,
+			)
+
+			return
+		}
+
+		bucketStr, _ := cmd.Flags().GetString("bucket")
+		bucket, err := model.ParseDuration(bucketStr)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Could not parse duration: %s\n", err),
+				output,
+			)
+
+			return
+		}
+
+		namespace, _ := cmd.Flags().GetString("namespace")
+		perNamespace, _ := cmd.Flags().GetBool("per-namespace")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.GetNodeStatsRequest{
+			Since:        timestamppb.New(time.Now().UTC().Add(-time.Duration(since))),
+			Bucket:       durationpb.New(time.Duration(bucket)),
+			Namespace:    namespace,
+			PerNamespace: perNamespace,
+		}
+
+		response, err := client.GetNodeStats(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get node statistics: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.Buckets, "", output)
+
+			return
+		}
+
+		if len(response.GetBuckets()) == 0 {
+			fmt.Println("No node count snapshot in this period")
+
+			return
+		}
+
+		fmt.Print(nodeStatsChart(response.GetBuckets()))
+	},
+}
+
+// nodeStatsChart renders the buckets as horizontal bars, one section
+// per namespace.
+func nodeStatsChart(buckets []*v1.NodeCountBucket) string {
+	maximum := uint32(1)
+	for _, bucket := range buckets {
+		if bucket.GetRegistered() > maximum {
+			maximum = bucket.GetRegistered()
+		}
+	}
+
+	bar := func(count uint32, char string) string {
+		return strings.Repeat(char, int(count*statsChartWidth/maximum))
+	}
+
+	// The buckets are sorted by time, group them by namespace.
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return buckets[i].GetNamespace() < buckets[j].GetNamespace()
+	})
+
+	var chart strings.Builder
+	namespace := ""
+	for index, bucket := range buckets {
+		if bucket.GetNamespace() != "" &&
+			(index == 0 || bucket.GetNamespace() != namespace) {
+			namespace = bucket.GetNamespace()
+			if index != 0 {
+				chart.WriteString("\n")
+			}
+			fmt.Fprintf(&chart, "%s\n", namespace)
+		}
+
+		start := bucket.GetStart().AsTime().Local().Format("2006-01-02 15:04")
+		fmt.Fprintf(
+			&chart,
+			"%s registered |%-*s %d\n",
+			start,
+			statsChartWidth,
+			bar(bucket.GetRegistered(), "#"),
+			bucket.GetRegistered(),
+		)
+		fmt.Fprintf(
+			&chart,
+			"%s active     |%-*s %d\n",
+			strings.Repeat(" ", len(start)),
+			statsChartWidth,
+			bar(bucket.GetActive(), "="),
+			bucket.GetActive(),
+		)
+	}
+
+	return chart.String()
+}
@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/prometheus/common/model"
+	"github.com/pterm/pterm"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+
+	createTemplateCmd.Flags().StringSlice("tags", []string{}, "Tags forced on the nodes (e.g. tag:web)")
+	createTemplateCmd.Flags().
+		StringSlice("enable-routes", []string{}, "Routes to enable on the nodes, when they advertise them")
+	createTemplateCmd.Flags().
+		StringArrayP("label", "l", []string{}, "Label to set on the nodes, as key=value (can be repeated)")
+	createTemplateCmd.Flags().
+		String("expiry", "", "Human-readable validity of the registrations (e.g. 90d), no expiry if empty")
+	templatesCmd.AddCommand(createTemplateCmd)
+
+	templatesCmd.AddCommand(listTemplatesCmd)
+	templatesCmd.AddCommand(deleteTemplateCmd)
+}
+
+var templatesCmd = &cobra.Command{
+	Use:     "templates",
+	Short:   "Manage the templates applied to the nodes at registration",
+	Aliases: []string{"template", "tpl"},
+}
+
+// parseExpiry converts the human-readable --expiry flag of a command to
+// a duration, nil when the flag is empty.
+func parseExpiry(cmd *cobra.Command) (*durationpb.Duration, error) {
+	expiryStr, _ := cmd.Flags().GetString("expiry")
+	if expiryStr == "" {
+		return nil, nil
+	}
+
+	expiry, err := model.ParseDuration(expiryStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return durationpb.New(time.Duration(expiry)), nil
+}
+
+var createTemplateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Creates a node template",
+	Long: `
+Create a named set of settings to apply to the nodes registered with
+'headscale nodes register --template NAME'. The flags of nodes register
+override the settings of the template.`,
+	Aliases: []string{"c", "new"},
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+		routes, _ := cmd.Flags().GetStringSlice("enable-routes")
+		labelsArgs, _ := cmd.Flags().GetStringArray("label")
+		labels, err := parseLabels(labelsArgs)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Error parsing labels: %s", err),
+				output,
+			)
+
+			return
+		}
+
+		expiry, err := parseExpiry(cmd)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Could not parse duration: %s\n", err),
+				output,
+			)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.CreateNodeTemplateRequest{
+			Name:   args[0],
+			Tags:   tags,
+			Routes: routes,
+			Labels: labels,
+			Expiry: expiry,
+		}
+
+		response, err := client.CreateNodeTemplate(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot create node template: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response.Template, "Node template created", output)
+	},
+}
+
+var listTemplatesCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the node templates",
+	Aliases: []string{"ls", "show"},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		response, err := client.ListNodeTemplates(ctx, &v1.ListNodeTemplatesRequest{})
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot get node templates: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		if output != "" {
+			SuccessOutput(response.Templates, "", output)
+
+			return
+		}
+
+		tableData := pterm.TableData{
+			{"ID", "Name", "Tags", "Routes", "Labels", "Expiry", "Created"},
+		}
+		for _, template := range response.GetTemplates() {
+			labels := make([]string, 0, len(template.GetLabels()))
+			for key, value := range template.GetLabels() {
+				labels = append(labels, key+"="+value)
+			}
+
+			expiry := "never"
+			if template.GetExpiry() != nil {
+				expiry = model.Duration(template.GetExpiry().AsDuration()).String()
+			}
+
+			tableData = append(tableData, []string{
+				strconv.FormatUint(template.GetId(), headscale.Base10),
+				template.GetName(),
+				strings.Join(template.GetTags(), ", "),
+				strings.Join(template.GetRoutes(), ", "),
+				joinSorted(labels),
+				expiry,
+				template.GetCreatedAt().AsTime().Format(HeadscaleDateTimeFormat),
+			})
+		}
+		err = pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Failed to render pterm table: %s", err),
+				output,
+			)
+
+			return
+		}
+	},
+}
+
+var deleteTemplateCmd = &cobra.Command{
+	Use:     "delete NAME",
+	Short:   "Deletes a node template",
+	Long:    "Deleting a node template does not change the nodes registered with it.",
+	Aliases: []string{"remove", "rm", "destroy"},
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 1 {
+			return errMissingParameter
+		}
+
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		templateName := args[0]
+
+		confirm := false
+		force := forceExecution(cmd)
+		if !force {
+			prompt := &survey.Confirm{
+				Message: fmt.Sprintf(
+					"Do you want to delete the node template '%s'?",
+					templateName,
+				),
+			}
+			err := survey.AskOne(prompt, &confirm)
+			if err != nil {
+				ErrorOutput(err, fmt.Sprintf("Error reading the confirmation: %s", err), output)
+
+				return
+			}
+		}
+
+		if !confirm && !force {
+			SuccessOutput(map[string]string{"Result": "Node template not deleted"}, "Node template not deleted", output)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		request := &v1.DeleteNodeTemplateRequest{Name: templateName}
+
+		response, err := client.DeleteNodeTemplate(ctx, request)
+		if err != nil {
+			ErrorOutput(
+				err,
+				fmt.Sprintf(
+					"Cannot delete node template: %s",
+					status.Convert(err).Message(),
+				),
+				output,
+			)
+
+			return
+		}
+
+		SuccessOutput(response, "Node template deleted", output)
+	},
+}
@@ -3,22 +3,55 @@ package cli
 import (
 	"context"
 	"crypto/tls"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/juanfont/headscale"
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
 	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v2"
 )
 
 const (
 	HeadscaleDateTimeFormat = "2006-01-02 15:04:05"
+
+	// Exit codes of the commands, derived from the gRPC status of the
+	// error by errorExitCode.
+	exitCodeSuccess          = 0
+	exitCodeError            = 1
+	exitCodeInvalidArgument  = 2
+	exitCodeNotFound         = 3
+	exitCodePermissionDenied = 4
+	exitCodeUnavailable      = 5
+
+	// timeoutHint is added to the errors of the calls which timed out.
+	timeoutHint = "the server did not answer in time, raise the timeout with --timeout"
+
+	// outputCSV is the output format of the tables as CSV, supported by
+	// nodes list.
+	outputCSV = "csv"
+
+	errMissingAPIKey    = Error("HEADSCALE_CLI_API_KEY environment variable needs to be set")
+	errInvalidLogFormat = Error("invalid log format, must be one of: text, json")
+
+	// Formats of --log-format, json logging the outcome of the command on
+	// the standard error in addition to its output.
+	logFormatText = "text"
+	logFormatJSON = "json"
 )
 
 func getHeadscaleApp() (*headscale.Headscale, error) {
@@ -31,6 +64,7 @@ func getHeadscaleApp() (*headscale.Headscale, error) {
 	if err != nil {
 		return nil, err
 	}
+	app.Version = Version
 
 	// We are doing this here, as in the future could be cool to have it also hot-reload
 
@@ -58,12 +92,28 @@ func getHeadscaleCLIClient() (context.Context, v1.HeadscaleServiceClient, *grpc.
 		os.Exit(-1) // we get here if logging is suppressed (i.e., json output)
 	}
 
+	ctx, client, conn, cancel, err := newHeadscaleCLIClient(cfg)
+	if errors.Is(err, errMissingAPIKey) {
+		log.Fatal().Caller().Msgf("HEADSCALE_CLI_API_KEY environment variable needs to be set.")
+	}
+	if err != nil {
+		log.Error().Caller().Err(err).Msgf("Could not connect: %v", err)
+		os.Exit(exitCodeUnavailable)
+	}
+
+	return ctx, client, conn, cancel
+}
+
+// newHeadscaleCLIClient connects to the server of cfg like
+// getHeadscaleCLIClient, returning the errors instead of exiting for the
+// callers which must not fail, such as the shell completions.
+func newHeadscaleCLIClient(
+	cfg *headscale.Config,
+) (context.Context, v1.HeadscaleServiceClient, *grpc.ClientConn, context.CancelFunc, error) {
 	log.Debug().
 		Dur("timeout", cfg.CLI.Timeout).
 		Msgf("Setting timeout")
 
-	ctx, cancel := context.WithTimeout(context.Background(), cfg.CLI.Timeout)
-
 	grpcOptions := []grpc.DialOption{
 		grpc.WithBlock(),
 	}
@@ -87,7 +137,7 @@ func getHeadscaleCLIClient() (context.Context, v1.HeadscaleServiceClient, *grpc.
 		// If we are not connecting to a local server, require an API key for authentication
 		apiKey := cfg.CLI.APIKey
 		if apiKey == "" {
-			log.Fatal().Caller().Msgf("HEADSCALE_CLI_API_KEY environment variable needs to be set.")
+			return nil, nil, nil, nil, errMissingAPIKey
 		}
 		grpcOptions = append(grpcOptions,
 			grpc.WithPerRPCCredentials(tokenAuth{
@@ -113,19 +163,163 @@ func getHeadscaleCLIClient() (context.Context, v1.HeadscaleServiceClient, *grpc.
 		}
 	}
 
+	grpcOptions = append(
+		grpcOptions,
+		grpc.WithChainUnaryInterceptor(unimplementedInterceptor, retryInterceptor),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.CLI.Timeout)
+
 	log.Trace().Caller().Str("address", address).Msg("Connecting via gRPC")
 	conn, err := grpc.DialContext(ctx, address, grpcOptions...)
 	if err != nil {
-		log.Fatal().Caller().Err(err).Msgf("Could not connect: %v", err)
-		os.Exit(-1) // we get here if logging is suppressed (i.e., json output)
+		cancel()
+
+		return nil, nil, nil, nil, err
 	}
 
 	client := v1.NewHeadscaleServiceClient(conn)
 
-	return ctx, client, conn, cancel
+	return ctx, client, conn, cancel, nil
+}
+
+// unimplementedInterceptor explains the Unimplemented errors of the
+// servers older than the CLI, which do not have the RPCs of its newer
+// features.
+func unimplementedInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	conn *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	err := invoker(ctx, method, req, reply, conn, opts...)
+	if status.Code(err) == codes.Unimplemented {
+		return status.Errorf(
+			codes.Unimplemented,
+			"the server does not support %s, it is probably older than this CLI (%s), see 'headscale version --remote'",
+			method[strings.LastIndex(method, "/")+1:],
+			Version,
+		)
+	}
+
+	return err
+}
+
+// retryBackoff is the wait before the first retry of retryInterceptor,
+// doubled at each retry.
+var retryBackoff = 250 * time.Millisecond
+
+// retriedMethods are the RPCs, by their full name, retried by
+// retryInterceptor. They only read the state, so they can be sent again
+// safely. A new RPC is not retried until it is listed here.
+var retriedMethods = map[string]bool{
+	"/headscale.v1.HeadscaleService/GetNamespace":             true,
+	"/headscale.v1.HeadscaleService/ListNamespaces":           true,
+	"/headscale.v1.HeadscaleService/GetNamespaceImpact":       true,
+	"/headscale.v1.HeadscaleService/ListPreAuthKeys":          true,
+	"/headscale.v1.HeadscaleService/GetMachine":               true,
+	"/headscale.v1.HeadscaleService/DumpMachineNetmap":        true,
+	"/headscale.v1.HeadscaleService/ListPendingRegistrations": true,
+	"/headscale.v1.HeadscaleService/ListMachines":             true,
+	"/headscale.v1.HeadscaleService/ListMachineConnections":   true,
+	"/headscale.v1.HeadscaleService/GetMachineRoute":          true,
+	"/headscale.v1.HeadscaleService/ListExitNodes":            true,
+	"/headscale.v1.HeadscaleService/ListApiKeys":              true,
+	"/headscale.v1.HeadscaleService/ListACLReferences":        true,
+	"/headscale.v1.HeadscaleService/CheckACL":                 true,
+	"/headscale.v1.HeadscaleService/ListACLGroups":            true,
+	"/headscale.v1.HeadscaleService/GetACLGroup":              true,
+	"/headscale.v1.HeadscaleService/ListDNSRecords":           true,
+	"/headscale.v1.HeadscaleService/GetNodeStats":             true,
+	"/headscale.v1.HeadscaleService/ListNodeTemplates":        true,
+	"/headscale.v1.HeadscaleService/ListAutoTags":             true,
+	"/headscale.v1.HeadscaleService/GetVersion":               true,
+}
+
+// retryInterceptor retries --retries times, with an exponential backoff,
+// the requests failing because the server is unavailable, for instance
+// over a flaky link. Only the retriedMethods, which change nothing, are
+// retried: a mutation may have been applied before the connection broke.
+// The retries happen within the timeout of the command.
+func retryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	conn *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	name := method[strings.LastIndex(method, "/")+1:]
+
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := invoker(ctx, method, req, reply, conn, opts...)
+		if status.Code(err) != codes.Unavailable || !retriedMethods[method] || attempt >= retries {
+			return err
+		}
+
+		log.Debug().
+			Err(err).
+			Str("method", name).
+			Int("attempt", attempt+1).
+			Dur("backoff", backoff).
+			Msg("Server unavailable, retrying")
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// forceExecution reports whether a destructive command runs without
+// prompting. An explicit --force, true or false, takes precedence over
+// cli.assume_yes (HEADSCALE_CLI_ASSUME_YES).
+func forceExecution(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("force") {
+		force, _ := cmd.Flags().GetBool("force")
+
+		return force
+	}
+
+	cfg, err := headscale.GetHeadscaleConfig()
+	if err != nil {
+		return false
+	}
+
+	return cfg.CLI.AssumeYes
+}
+
+// writeCSV writes a table as CSV (RFC 4180), its first row being the
+// header. The colors of the cells are removed.
+func writeCSV(writer io.Writer, tableData pterm.TableData) error {
+	csvWriter := csv.NewWriter(writer)
+	for _, row := range tableData {
+		record := make([]string, len(row))
+		for index, cell := range row {
+			record[index] = pterm.RemoveColorFromString(cell)
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+
+	return csvWriter.Error()
 }
 
 func SuccessOutput(result interface{}, override string, outputFormat string) {
+	logOutput("info", override, nil)
+	printOutput(result, override, outputFormat)
+}
+
+// printOutput prints result in outputFormat, or override when it is
+// empty.
+func printOutput(result interface{}, override string, outputFormat string) {
 	var jsonBytes []byte
 	var err error
 	switch outputFormat {
@@ -155,17 +349,98 @@ func SuccessOutput(result interface{}, override string, outputFormat string) {
 	fmt.Println(string(jsonBytes))
 }
 
+// ErrorOutput prints the error like SuccessOutput and sets the exit code of
+// the command from its gRPC status.
 func ErrorOutput(errResult error, override string, outputFormat string) {
 	type errOutput struct {
 		Error string `json:"error"`
 	}
 
-	SuccessOutput(errOutput{errResult.Error()}, override, outputFormat)
+	message := errResult.Error()
+	if status.Code(errResult) == codes.DeadlineExceeded {
+		override = fmt.Sprintf("%s (%s)", override, timeoutHint)
+		message = fmt.Sprintf("%s (%s)", message, timeoutHint)
+	}
+
+	setExitCode(errorExitCode(errResult))
+	if override != "" {
+		logOutput("error", override, errResult)
+	} else {
+		logOutput("error", message, errResult)
+	}
+	printOutput(errOutput{message}, override, outputFormat)
+}
+
+// outputLog is the line written by logOutput.
+type outputLog struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	Code    string    `json:"code"`
+	Command string    `json:"command"`
+}
+
+// logOutput writes the outcome of the command to the standard error as a
+// JSON line with --log-format json, for the log aggregators. code is the
+// gRPC code of err, OK without error.
+func logOutput(level string, message string, err error) {
+	if logFormat != logFormatJSON {
+		return
+	}
+
+	command := rootCmd.Name()
+	if cmd, _, findErr := rootCmd.Find(os.Args[1:]); findErr == nil {
+		command = cmd.CommandPath()
+	}
+
+	line, marshalErr := json.Marshal(outputLog{
+		Time:    time.Now().UTC(),
+		Level:   level,
+		Message: message,
+		Code:    status.Code(err).String(),
+		Command: command,
+	})
+	if marshalErr != nil {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, string(line))
+}
+
+// errorExitCode is the exit code of an error, so the scripts can tell, for
+// example, a missing node from an unreachable server.
+func errorExitCode(err error) int {
+	switch status.Code(err) {
+	case codes.OK:
+		return exitCodeSuccess
+	case codes.InvalidArgument:
+		return exitCodeInvalidArgument
+	case codes.NotFound:
+		return exitCodeNotFound
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return exitCodePermissionDenied
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return exitCodeUnavailable
+	default:
+		return exitCodeError
+	}
+}
+
+// setExitCode sets the exit code of the command, keeping the one of the
+// first failure.
+func setExitCode(code int) {
+	if exitCode == exitCodeSuccess {
+		exitCode = code
+	}
 }
 
 func HasMachineOutputFlag() bool {
-	for _, arg := range os.Args {
-		if arg == "json" || arg == "json-line" || arg == "yaml" {
+	for index, arg := range os.Args {
+		// --log-format json leaves the output readable.
+		if index > 0 && os.Args[index-1] == "--log-format" {
+			continue
+		}
+		if arg == "json" || arg == "json-line" || arg == "yaml" || arg == outputCSV {
 			return true
 		}
 	}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryInterceptor(t *testing.T) {
+	defer func(backoff time.Duration, count int) {
+		retryBackoff = backoff
+		retries = count
+	}(retryBackoff, retries)
+	retryBackoff = time.Millisecond
+	retries = 2
+
+	tests := []struct {
+		method string
+		want   int
+	}{
+		{method: "/headscale.v1.HeadscaleService/ListMachines", want: 3},
+		{method: "/headscale.v1.HeadscaleService/GetMachine", want: 3},
+		{method: "/headscale.v1.HeadscaleService/DeleteMachine", want: 1},
+		{method: "/headscale.v1.HeadscaleService/RegisterMachine", want: 1},
+		{method: "/headscale.v1.HeadscaleService/GetUnknown", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			calls := 0
+			invoker := func(
+				ctx context.Context,
+				method string,
+				req, reply interface{},
+				conn *grpc.ClientConn,
+				opts ...grpc.CallOption,
+			) error {
+				calls++
+
+				return status.Error(codes.Unavailable, "unavailable")
+			}
+
+			err := retryInterceptor(context.Background(), tt.method, nil, nil, nil, invoker)
+			if status.Code(err) != codes.Unavailable {
+				t.Errorf("retryInterceptor() error = %v, want Unavailable", err)
+			}
+			if calls != tt.want {
+				t.Errorf("retryInterceptor() called the server %d times, want %d", calls, tt.want)
+			}
+		})
+	}
+}
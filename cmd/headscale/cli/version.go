@@ -1,21 +1,100 @@
 package cli
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/juanfont/headscale"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/pterm/pterm"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var Version = "dev"
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().
+		Bool("remote", false, "Also show the version and the capabilities of the server")
+}
+
+// versionInfo is the version of the CLI and, with --remote, the one of
+// the server.
+type versionInfo struct {
+	Version       string   `json:"version"`
+	ServerVersion string   `json:"server_version,omitempty"`
+	Capabilities  []string `json:"capabilities,omitempty"`
+	// MissingCapabilities are the RPCs of the CLI the server does not
+	// have, the commands using them fail.
+	MissingCapabilities []string `json:"missing_capabilities,omitempty"`
 }
 
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version.",
-	Long:  "The version of headscale.",
+	Long: `
+The version of headscale. With --remote, the version of the server is
+shown too, along with its capabilities (the RPCs it implements), and a
+warning lists the ones this CLI needs but the server is missing.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		output, _ := cmd.Flags().GetString("output")
-		SuccessOutput(map[string]string{"version": Version}, Version, output)
+		remote, _ := cmd.Flags().GetBool("remote")
+
+		if !remote {
+			SuccessOutput(map[string]string{"version": Version}, Version, output)
+
+			return
+		}
+
+		ctx, client, conn, cancel := getHeadscaleCLIClient()
+		defer cancel()
+		defer conn.Close()
+
+		info := versionInfo{Version: Version}
+
+		response, err := client.GetVersion(ctx, &v1.GetVersionRequest{})
+		switch {
+		case status.Code(err) == codes.Unimplemented:
+			// The servers older than the GetVersion RPC cannot report
+			// what they support.
+			info.ServerVersion = "unknown"
+			info.MissingCapabilities = []string{"GetVersion"}
+		case err != nil:
+			ErrorOutput(
+				err,
+				fmt.Sprintf("Cannot get server version: %s", status.Convert(err).Message()),
+				output,
+			)
+
+			return
+		default:
+			info.ServerVersion = response.GetVersion()
+			info.Capabilities = response.GetCapabilities()
+			for _, capability := range headscale.Capabilities() {
+				if !contains(info.Capabilities, capability) {
+					info.MissingCapabilities = append(info.MissingCapabilities, capability)
+				}
+			}
+		}
+
+		if output != "" {
+			SuccessOutput(info, "", output)
+
+			return
+		}
+
+		fmt.Printf("CLI version:    %s\n", info.Version)
+		fmt.Printf("Server version: %s\n", info.ServerVersion)
+		if len(info.Capabilities) > 0 {
+			fmt.Printf("Capabilities:   %d RPCs\n", len(info.Capabilities))
+		}
+		if len(info.MissingCapabilities) > 0 {
+			fmt.Println(pterm.LightYellow(fmt.Sprintf(
+				"Warning: the server is older than the CLI, the commands using these RPCs fail: %s",
+				strings.Join(info.MissingCapabilities, ", "),
+			)))
+		}
 	},
 }
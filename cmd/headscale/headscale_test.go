@@ -149,6 +149,47 @@ func (*Suite) TestDNSConfigLoading(c *check.C) {
 	c.Assert(baseDomain, check.Equals, "example.com")
 }
 
+func (*Suite) TestCLIAssumeYesFromEnv(c *check.C) {
+	tmpDir, err := ioutil.TempDir("", "headscale")
+	if err != nil {
+		c.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	path, err := os.Getwd()
+	if err != nil {
+		c.Fatal(err)
+	}
+
+	err = os.Symlink(
+		filepath.Clean(path+"/../../config-example.yaml"),
+		filepath.Join(tmpDir, "config.yaml"),
+	)
+	if err != nil {
+		c.Fatal(err)
+	}
+
+	err = headscale.LoadConfig(tmpDir, false)
+	c.Assert(err, check.IsNil)
+
+	cfg, err := headscale.GetHeadscaleConfig()
+	c.Assert(err, check.IsNil)
+	c.Assert(cfg.CLI.AssumeYes, check.Equals, false)
+
+	os.Setenv("HEADSCALE_CLI_ASSUME_YES", "1")
+	defer os.Unsetenv("HEADSCALE_CLI_ASSUME_YES")
+
+	cfg, err = headscale.GetHeadscaleConfig()
+	c.Assert(err, check.IsNil)
+	c.Assert(cfg.CLI.AssumeYes, check.Equals, true)
+
+	os.Setenv("HEADSCALE_CLI_ASSUME_YES", "0")
+
+	cfg, err = headscale.GetHeadscaleConfig()
+	c.Assert(err, check.IsNil)
+	c.Assert(cfg.CLI.AssumeYes, check.Equals, false)
+}
+
 func writeConfig(c *check.C, tmpDir string, configYaml []byte) {
 	// Populate a custom config file
 	configFile := filepath.Join(tmpDir, "config.yaml")
@@ -32,6 +32,10 @@ type Config struct {
 	GRPCAllowInsecure              bool
 	EphemeralNodeInactivityTimeout time.Duration
 	NodeUpdateCheckInterval        time.Duration
+	DeletedNodesRetention          time.Duration
+	ConnectionLogRetention         time.Duration
+	NodeStatsRetention             time.Duration
+	ClockSkewThreshold             time.Duration
 	IPPrefixes                     []netaddr.IPPrefix
 	PrivateKeyPath                 string
 	BaseDomain                     string
@@ -63,9 +67,21 @@ type Config struct {
 	LogTail             LogTailConfig
 	RandomizeClientPort bool
 
+	// NormalizeNames derives the DNS name of a node from its hostname
+	// (lowercased, invalid characters removed). When disabled, nodes with a
+	// hostname that is not a valid DNS label are refused.
+	NormalizeNames bool
+	// SingleLabelNames also replaces the dots of the normalized names by
+	// hyphens and collapses the repeated hyphens, so that they are a single
+	// DNS label.
+	SingleLabelNames bool
+
 	CLI CLIConfig
 
 	ACL ACLConfig
+
+	// AutoTags are the autotag rules of the configuration file.
+	AutoTags []AutoTagRule
 }
 
 type TLSConfig struct {
@@ -92,6 +108,7 @@ type OIDCConfig struct {
 	AllowedDomains   []string
 	AllowedUsers     []string
 	StripEmaildomain bool
+	RequireApproval  bool
 }
 
 type DERPConfig struct {
@@ -115,6 +132,14 @@ type CLIConfig struct {
 	APIKey   string
 	Timeout  time.Duration
 	Insecure bool
+
+	// HideKeysByDefault masks the node keys in the tables printed by
+	// the CLI, for admins sharing their screen.
+	HideKeysByDefault bool
+
+	// AssumeYes confirms the destructive commands without prompting, as
+	// if --force was passed, for automation.
+	AssumeYes bool
 }
 
 type ACLConfig struct {
@@ -159,16 +184,24 @@ func LoadConfig(path string, isFile bool) error {
 
 	viper.SetDefault("cli.timeout", "5s")
 	viper.SetDefault("cli.insecure", false)
+	viper.SetDefault("cli.hide_keys_by_default", false)
+	viper.SetDefault("cli.assume_yes", false)
 
 	viper.SetDefault("oidc.scope", []string{oidc.ScopeOpenID, "profile", "email"})
 	viper.SetDefault("oidc.strip_email_domain", true)
 
 	viper.SetDefault("logtail.enabled", false)
 	viper.SetDefault("randomize_client_port", false)
+	viper.SetDefault("normalize_names", true)
+	viper.SetDefault("single_label_names", false)
 
 	viper.SetDefault("ephemeral_node_inactivity_timeout", "120s")
 
 	viper.SetDefault("node_update_check_interval", "10s")
+	viper.SetDefault("deleted_nodes_retention", "168h")
+	viper.SetDefault("connection_log_retention", "720h")
+	viper.SetDefault("node_stats_retention", "2160h")
+	viper.SetDefault("clock_skew_threshold", "1m")
 
 	if err := viper.ReadInConfig(); err != nil {
 		log.Warn().Err(err).Msg("Failed to read configuration from disk")
@@ -225,6 +258,16 @@ func LoadConfig(path string, isFile bool) error {
 		)
 	}
 
+	autoTags, err := GetAutoTagsConfig()
+	if err != nil {
+		errorText += fmt.Sprintf("Fatal config error: cannot parse autotags: %s\n", err)
+	}
+	for _, rule := range autoTags {
+		if err := rule.validate(); err != nil {
+			errorText += fmt.Sprintf("Fatal config error: autotags: %s\n", err)
+		}
+	}
+
 	maxNodeUpdateCheckInterval, _ := time.ParseDuration("60s")
 	if viper.GetDuration("node_update_check_interval") > maxNodeUpdateCheckInterval {
 		errorText += fmt.Sprintf(
@@ -420,6 +463,11 @@ func GetHeadscaleConfig() (*Config, error) {
 	logConfig := GetLogTailConfig()
 	randomizeClientPort := viper.GetBool("randomize_client_port")
 
+	autoTags, err := GetAutoTagsConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	configuredPrefixes := viper.GetStringSlice("ip_prefixes")
 	parsedPrefixes := make([]netaddr.IPPrefix, 0, len(configuredPrefixes)+1)
 
@@ -499,6 +547,18 @@ func GetHeadscaleConfig() (*Config, error) {
 			"node_update_check_interval",
 		),
 
+		ClockSkewThreshold: viper.GetDuration("clock_skew_threshold"),
+
+		DeletedNodesRetention: viper.GetDuration(
+			"deleted_nodes_retention",
+		),
+
+		ConnectionLogRetention: viper.GetDuration(
+			"connection_log_retention",
+		),
+
+		NodeStatsRetention: viper.GetDuration("node_stats_retention"),
+
 		DBtype: viper.GetString("db_type"),
 		DBpath: AbsolutePathFromConfigPath(viper.GetString("db_path")),
 		DBhost: viper.GetString("db_host"),
@@ -526,18 +586,26 @@ func GetHeadscaleConfig() (*Config, error) {
 			AllowedDomains:   viper.GetStringSlice("oidc.allowed_domains"),
 			AllowedUsers:     viper.GetStringSlice("oidc.allowed_users"),
 			StripEmaildomain: viper.GetBool("oidc.strip_email_domain"),
+			RequireApproval:  viper.GetBool("oidc.require_approval"),
 		},
 
 		LogTail:             logConfig,
 		RandomizeClientPort: randomizeClientPort,
+		NormalizeNames:      viper.GetBool("normalize_names"),
+		SingleLabelNames:    viper.GetBool("single_label_names"),
 
 		CLI: CLIConfig{
 			Address:  viper.GetString("cli.address"),
 			APIKey:   viper.GetString("cli.api_key"),
 			Timeout:  viper.GetDuration("cli.timeout"),
 			Insecure: viper.GetBool("cli.insecure"),
+
+			HideKeysByDefault: viper.GetBool("cli.hide_keys_by_default"),
+			AssumeYes:         viper.GetBool("cli.assume_yes"),
 		},
 
 		ACL: GetACLConfig(),
+
+		AutoTags: autoTags,
 	}, nil
 }
@@ -0,0 +1,116 @@
+package headscale
+
+import (
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	ConnectionEventConnect    = "connect"
+	ConnectionEventDisconnect = "disconnect"
+)
+
+// ConnectionEvent records a machine opening or closing its long-poll
+// connection, to investigate when and from where it was active.
+type ConnectionEvent struct {
+	ID        uint64 `gorm:"primary_key"`
+	MachineID uint64 `gorm:"index"`
+	Event     string
+	Time      time.Time `gorm:"index"`
+	// Endpoint is the address the connection came from, the address of
+	// the reverse proxy when there is one.
+	Endpoint   string
+	DERPRegion int
+}
+
+// recordConnectionEvent saves a connection event of a machine. Nothing is
+// saved when the connection log is disabled.
+func (h *Headscale) recordConnectionEvent(
+	machine *Machine,
+	event string,
+	endpoint string,
+	now time.Time,
+) {
+	if h.cfg.ConnectionLogRetention <= 0 {
+		return
+	}
+
+	connectionEvent := ConnectionEvent{
+		MachineID: machine.ID,
+		Event:     event,
+		Time:      now,
+		Endpoint:  endpoint,
+	}
+	if machine.HostInfo.NetInfo != nil {
+		connectionEvent.DERPRegion = machine.HostInfo.NetInfo.PreferredDERP
+	}
+
+	if err := h.db.Create(&connectionEvent).Error; err != nil {
+		log.Error().
+			Caller().
+			Err(err).
+			Str("machine", machine.Hostname).
+			Str("event", event).
+			Msg("Cannot save the connection event of the machine")
+	}
+}
+
+// ListConnectionEvents returns the connection events of a machine since
+// a time, oldest first.
+func (h *Headscale) ListConnectionEvents(
+	machineID uint64,
+	since time.Time,
+) ([]ConnectionEvent, error) {
+	events := []ConnectionEvent{}
+	err := h.db.
+		Where("machine_id = ? AND time >= ?", machineID, since).
+		Order("time, id").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// purgeConnectionEvents periodically removes the connection events older
+// than the retention window.
+func (h *Headscale) purgeConnectionEvents(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	for range ticker.C {
+		h.purgeConnectionEventsWorker()
+	}
+}
+
+func (h *Headscale) purgeConnectionEventsWorker() {
+	if h.cfg.ConnectionLogRetention <= 0 {
+		return
+	}
+
+	result := h.db.
+		Where("time < ?", time.Now().UTC().Add(-h.cfg.ConnectionLogRetention)).
+		Delete(&ConnectionEvent{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("Could not purge connection events")
+
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		log.Debug().
+			Int64("count", result.RowsAffected).
+			Msg("Purged connection events")
+	}
+}
+
+func (event ConnectionEvent) toProto() *v1.ConnectionEvent {
+	return &v1.ConnectionEvent{
+		Event:      event.Event,
+		Time:       timestamppb.New(event.Time),
+		Endpoint:   event.Endpoint,
+		DerpRegion: int32(event.DERPRegion),
+	}
+}
@@ -0,0 +1,48 @@
+package headscale
+
+import (
+	"time"
+
+	"gopkg.in/check.v1"
+	"tailscale.com/tailcfg"
+)
+
+func (s *Suite) TestConnectionEvents(c *check.C) {
+	machine := &Machine{
+		ID:       1,
+		Hostname: "testmachine",
+		HostInfo: HostInfo{NetInfo: &tailcfg.NetInfo{PreferredDERP: 3}},
+	}
+	now := time.Now().UTC()
+
+	// The connection log is disabled without retention.
+	app.recordConnectionEvent(machine, ConnectionEventConnect, "192.0.2.1:1234", now)
+	events, err := app.ListConnectionEvents(machine.ID, time.Time{})
+	c.Assert(err, check.IsNil)
+	c.Assert(events, check.HasLen, 0)
+
+	app.cfg.ConnectionLogRetention = 24 * time.Hour
+	app.recordConnectionEvent(
+		machine,
+		ConnectionEventConnect,
+		"192.0.2.1:1234",
+		now.Add(-48*time.Hour),
+	)
+	app.recordConnectionEvent(machine, ConnectionEventConnect, "192.0.2.1:1234", now.Add(-time.Hour))
+	app.recordConnectionEvent(machine, ConnectionEventDisconnect, "192.0.2.1:1234", now)
+	app.recordConnectionEvent(&Machine{ID: 2}, ConnectionEventConnect, "192.0.2.2:1234", now)
+
+	events, err = app.ListConnectionEvents(machine.ID, now.Add(-2*time.Hour))
+	c.Assert(err, check.IsNil)
+	c.Assert(events, check.HasLen, 2)
+	c.Assert(events[0].Event, check.Equals, ConnectionEventConnect)
+	c.Assert(events[0].Endpoint, check.Equals, "192.0.2.1:1234")
+	c.Assert(events[0].DERPRegion, check.Equals, 3)
+	c.Assert(events[1].Event, check.Equals, ConnectionEventDisconnect)
+
+	app.purgeConnectionEventsWorker()
+
+	events, err = app.ListConnectionEvents(machine.ID, time.Time{})
+	c.Assert(err, check.IsNil)
+	c.Assert(events, check.HasLen, 2)
+}
@@ -138,6 +138,26 @@ func (h *Headscale) initDB() error {
 		return err
 	}
 
+	err = db.AutoMigrate(&NodeCountSnapshot{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&NodeTemplate{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&AutoTag{})
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(&ConnectionEvent{})
+	if err != nil {
+		return err
+	}
+
 	err = h.setValue("db_version", dbVersion)
 
 	return err
@@ -302,3 +322,25 @@ func (i StringList) Value() (driver.Value, error) {
 
 	return string(bytes), err
 }
+
+type StringMap map[string]string
+
+func (i *StringMap) Scan(destination interface{}) error {
+	switch value := destination.(type) {
+	case []byte:
+		return json.Unmarshal(value, i)
+
+	case string:
+		return json.Unmarshal([]byte(value), i)
+
+	default:
+		return fmt.Errorf("%w: unexpected data type %T", errMachineAddressesInvalid, destination)
+	}
+}
+
+// Value return json value, implement driver.Valuer interface.
+func (i StringMap) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(i)
+
+	return string(bytes), err
+}
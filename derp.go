@@ -15,6 +15,8 @@ import (
 	"tailscale.com/tailcfg"
 )
 
+const errDERPRegionNotFound = Error("DERP region not found")
+
 func loadDERPMapFromPath(path string) (*tailcfg.DERPMap, error) {
 	derpFile, err := os.Open(path)
 	if err != nil {
@@ -156,3 +158,43 @@ func (h *Headscale) scheduledDERPMapUpdateWorker(cancelChan <-chan struct{}) {
 		}
 	}
 }
+
+// forceDERPRegion returns a copy of derpMap where every region but regionID
+// is to be avoided, so the clients honoring Avoid use regionID as home.
+// The other regions are kept, as they are needed to reach the peers
+// homed there.
+func forceDERPRegion(derpMap *tailcfg.DERPMap, regionID int) *tailcfg.DERPMap {
+	if _, ok := derpMap.Regions[regionID]; !ok {
+		log.Warn().
+			Int("region", regionID).
+			Msg("Forced DERP region is not in the DERP map, ignoring it")
+
+		return derpMap
+	}
+
+	forced := &tailcfg.DERPMap{
+		Regions:            make(map[int]*tailcfg.DERPRegion, len(derpMap.Regions)),
+		OmitDefaultRegions: derpMap.OmitDefaultRegions,
+	}
+	for id, region := range derpMap.Regions {
+		regionCopy := *region
+		regionCopy.Avoid = id != regionID
+		forced.Regions[id] = &regionCopy
+	}
+
+	return forced
+}
+
+// derpRegionCode returns the code of a region of derpMap, empty when the
+// region is not in the map.
+func derpRegionCode(derpMap *tailcfg.DERPMap, regionID int) string {
+	if derpMap == nil {
+		return ""
+	}
+	region, ok := derpMap.Regions[regionID]
+	if !ok {
+		return ""
+	}
+
+	return region.RegionCode
+}
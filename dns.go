@@ -2,9 +2,12 @@ package headscale
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	mapset "github.com/deckarep/golang-set/v2"
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/rs/zerolog/log"
 	"inet.af/netaddr"
 	"tailscale.com/tailcfg"
 	"tailscale.com/util/dnsname"
@@ -151,6 +154,30 @@ func generateIPv6DNSRootDomain(ipPrefix netaddr.IPPrefix) []dnsname.FQDN {
 	return fqdns
 }
 
+// reverseDNSName returns the name of the PTR record of an IP address,
+// in-addr.arpa for IPv4 and the nibble format of ip6.arpa for IPv6.
+func reverseDNSName(ip netaddr.IP) string {
+	if ip.Is4() {
+		octets := ip.As4()
+
+		return fmt.Sprintf(
+			"%d.%d.%d.%d.in-addr.arpa",
+			octets[3],
+			octets[2],
+			octets[1],
+			octets[0],
+		)
+	}
+
+	bytes := ip.As16()
+	nibbles := make([]string, 0, len(bytes)*2)
+	for i := len(bytes) - 1; i >= 0; i-- {
+		nibbles = append(nibbles, fmt.Sprintf("%x", bytes[i]&0x0f), fmt.Sprintf("%x", bytes[i]>>4))
+	}
+
+	return strings.Join(nibbles, ".") + ".ip6.arpa"
+}
+
 func getMapResponseDNSConfig(
 	dnsConfigOrig *tailcfg.DNSConfig,
 	baseDomain string,
@@ -163,11 +190,7 @@ func getMapResponseDNSConfig(
 		dnsConfig = dnsConfigOrig.Clone()
 		dnsConfig.Domains = append(
 			dnsConfig.Domains,
-			fmt.Sprintf(
-				"%s.%s",
-				machine.Namespace.Name,
-				baseDomain,
-			),
+			machine.Namespace.magicDNSDomain(baseDomain),
 		)
 
 		namespaceSet := mapset.NewSet[Namespace]()
@@ -176,7 +199,7 @@ func getMapResponseDNSConfig(
 			namespaceSet.Add(p.Namespace)
 		}
 		for _, namespace := range namespaceSet.ToSlice() {
-			dnsRoute := fmt.Sprintf("%v.%v", namespace.Name, baseDomain)
+			dnsRoute := namespace.magicDNSDomain(baseDomain)
 			dnsConfig.Routes[dnsRoute] = nil
 		}
 	} else {
@@ -185,3 +208,76 @@ func getMapResponseDNSConfig(
 
 	return dnsConfig
 }
+
+// DNSRecord is a record served by MagicDNS.
+type DNSRecord struct {
+	Name string
+	// Type is A or AAAA, headscale does not serve other records.
+	Type  string
+	Value string
+}
+
+// ListDNSRecords returns the MagicDNS records of the machines of a namespace,
+// or of all the namespaces when namespace is empty, as they are sent to the
+// clients in their netmap.
+func (h *Headscale) ListDNSRecords(namespace string) ([]DNSRecord, error) {
+	var machines []Machine
+	var err error
+	if namespace != "" {
+		machines, err = h.ListMachinesInNamespace(namespace)
+	} else {
+		machines, err = h.ListMachines()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := []DNSRecord{}
+	for _, machine := range machines {
+		hostname, err := machine.magicDNSName(h.cfg.BaseDomain)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("machine", machine.Hostname).
+				Msg("Machine has no valid MagicDNS name")
+
+			continue
+		}
+		name, err := dnsname.ToFQDN(hostname)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("machine", machine.Hostname).
+				Msg("Machine has no valid MagicDNS name")
+
+			continue
+		}
+
+		for _, address := range machine.IPAddresses {
+			recordType := "A"
+			if address.Is6() {
+				recordType = "AAAA"
+			}
+
+			records = append(records, DNSRecord{
+				Name:  name.WithTrailingDot(),
+				Type:  recordType,
+				Value: address.String(),
+			})
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Name < records[j].Name
+	})
+
+	return records, nil
+}
+
+func (record DNSRecord) toProto() *v1.DNSRecord {
+	return &v1.DNSRecord{
+		Name:  record.Name,
+		Type:  record.Type,
+		Value: record.Value,
+	}
+}
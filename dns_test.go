@@ -111,6 +111,19 @@ func (s *Suite) TestMagicDNSRootDomainsIPv6SingleMultiple(c *check.C) {
 	c.Assert(yieldsRoot("3.0.e.1.a.c.5.1.1.a.7.d.f.ip6.arpa."), check.Equals, true)
 }
 
+func (s *Suite) TestReverseDNSName(c *check.C) {
+	c.Assert(
+		reverseDNSName(netaddr.MustParseIP("100.64.0.5")),
+		check.Equals,
+		"5.0.64.100.in-addr.arpa",
+	)
+	c.Assert(
+		reverseDNSName(netaddr.MustParseIP("fd7a:115c:a1e0::1")),
+		check.Equals,
+		"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.e.1.a.c.5.1.1.a.7.d.f.ip6.arpa",
+	)
+}
+
 func (s *Suite) TestDNSConfigMapResponseWithMagicDNS(c *check.C) {
 	namespaceShared1, err := app.CreateNamespace("shared1")
 	c.Assert(err, check.IsNil)
@@ -384,3 +397,53 @@ func (s *Suite) TestDNSConfigMapResponseWithoutMagicDNS(c *check.C) {
 	c.Assert(len(dnsConfig.Routes), check.Equals, 0)
 	c.Assert(len(dnsConfig.Domains), check.Equals, 1)
 }
+
+func (s *Suite) TestListDNSRecords(c *check.C) {
+	app.cfg.BaseDomain = "headscale.net"
+
+	foo, err := app.CreateNamespace("foo")
+	c.Assert(err, check.IsNil)
+	bar, err := app.CreateNamespace("bar")
+	c.Assert(err, check.IsNil)
+	_, err = app.SetNamespaceDNSDomain(bar.Name, "bar.example.com")
+	c.Assert(err, check.IsNil)
+
+	machines := []Machine{
+		{
+			ID:          1,
+			MachineKey:  "foo",
+			GivenName:   "web",
+			NamespaceID: foo.ID,
+			IPAddresses: MachineAddresses{
+				netaddr.MustParseIP("100.64.0.1"),
+				netaddr.MustParseIP("fd7a:115c:a1e0::1"),
+			},
+		},
+		{
+			ID:          2,
+			MachineKey:  "bar",
+			GivenName:   "db",
+			NamespaceID: bar.ID,
+			IPAddresses: MachineAddresses{netaddr.MustParseIP("100.64.0.2")},
+		},
+	}
+	for index := range machines {
+		c.Assert(app.db.Save(&machines[index]).Error, check.IsNil)
+	}
+
+	records, err := app.ListDNSRecords(foo.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(records, check.DeepEquals, []DNSRecord{
+		{Name: "web.foo.headscale.net.", Type: "A", Value: "100.64.0.1"},
+		{Name: "web.foo.headscale.net.", Type: "AAAA", Value: "fd7a:115c:a1e0::1"},
+	})
+
+	records, err = app.ListDNSRecords("")
+	c.Assert(err, check.IsNil)
+	c.Assert(records, check.HasLen, 3)
+	c.Assert(records[0], check.DeepEquals, DNSRecord{
+		Name:  "db.bar.example.com.",
+		Type:  "A",
+		Value: "100.64.0.2",
+	})
+}
@@ -0,0 +1,66 @@
+package headscale
+
+import (
+	"sync"
+	"time"
+)
+
+// flapWindow is the period over which the disconnections of a machine
+// are counted.
+const flapWindow = time.Hour
+
+// flapTracker counts how often the machines drop their long-poll
+// connection, to spot the unstable ones. The counts are kept in memory
+// and reset when Headscale restarts.
+type flapTracker struct {
+	mu          sync.Mutex
+	disconnects map[uint64][]time.Time
+}
+
+// recordDisconnect notes that the machine closed its long-poll connection.
+func (tracker *flapTracker) recordDisconnect(machineID uint64, now time.Time) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	if tracker.disconnects == nil {
+		tracker.disconnects = make(map[uint64][]time.Time)
+	}
+
+	tracker.disconnects[machineID] = append(
+		tracker.recent(machineID, now),
+		now,
+	)
+}
+
+// count returns the number of disconnections of the machine during the
+// last flapWindow.
+func (tracker *flapTracker) count(machineID uint64, now time.Time) int {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	recent := tracker.recent(machineID, now)
+	if len(recent) == 0 {
+		delete(tracker.disconnects, machineID)
+	} else {
+		tracker.disconnects[machineID] = recent
+	}
+
+	return len(recent)
+}
+
+// recent drops the disconnections older than flapWindow, the caller must
+// hold the lock.
+func (tracker *flapTracker) recent(machineID uint64, now time.Time) []time.Time {
+	disconnects := tracker.disconnects[machineID]
+	for len(disconnects) > 0 && disconnects[0].Before(now.Add(-flapWindow)) {
+		disconnects = disconnects[1:]
+	}
+
+	return disconnects
+}
+
+// machineFlapCount returns the number of times the machine dropped its
+// connection during the last hour.
+func (h *Headscale) machineFlapCount(machine *Machine) uint32 {
+	return uint32(h.flaps.count(machine.ID, time.Now().UTC()))
+}
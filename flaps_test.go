@@ -0,0 +1,30 @@
+package headscale
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlapTracker(t *testing.T) {
+	var tracker flapTracker
+	now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	if count := tracker.count(1, now); count != 0 {
+		t.Fatalf("count() = %d, want 0 without disconnection", count)
+	}
+
+	tracker.recordDisconnect(1, now.Add(-2*time.Hour))
+	tracker.recordDisconnect(1, now.Add(-30*time.Minute))
+	tracker.recordDisconnect(1, now.Add(-time.Minute))
+	tracker.recordDisconnect(2, now.Add(-time.Minute))
+
+	if count := tracker.count(1, now); count != 2 {
+		t.Errorf("count() = %d, want 2 disconnections during the last hour", count)
+	}
+	if count := tracker.count(2, now); count != 1 {
+		t.Errorf("count() = %d, want 1", count)
+	}
+	if count := tracker.count(1, now.Add(time.Hour)); count != 0 {
+		t.Errorf("count() = %d, want 0 once the disconnections are old", count)
+	}
+}
@@ -0,0 +1,745 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.27.1
+// 	protoc        (unknown)
+// source: headscale/v1/autotag.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// AutoTag forces tags on the nodes with a hostname matching pattern.
+type AutoTag struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pattern string   `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Tags    []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+	// config or cli, only the rules set with the CLI can be changed with it.
+	Source string `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+}
+
+func (x *AutoTag) Reset() {
+	*x = AutoTag{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_headscale_v1_autotag_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AutoTag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutoTag) ProtoMessage() {}
+
+func (x *AutoTag) ProtoReflect() protoreflect.Message {
+	mi := &file_headscale_v1_autotag_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutoTag.ProtoReflect.Descriptor instead.
+func (*AutoTag) Descriptor() ([]byte, []int) {
+	return file_headscale_v1_autotag_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AutoTag) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *AutoTag) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *AutoTag) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type AutoTagChange struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Machine   *Machine `protobuf:"bytes,1,opt,name=machine,proto3" json:"machine,omitempty"`
+	AddedTags []string `protobuf:"bytes,2,rep,name=added_tags,json=addedTags,proto3" json:"added_tags,omitempty"`
+}
+
+func (x *AutoTagChange) Reset() {
+	*x = AutoTagChange{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_headscale_v1_autotag_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AutoTagChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AutoTagChange) ProtoMessage() {}
+
+func (x *AutoTagChange) ProtoReflect() protoreflect.Message {
+	mi := &file_headscale_v1_autotag_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AutoTagChange.ProtoReflect.Descriptor instead.
+func (*AutoTagChange) Descriptor() ([]byte, []int) {
+	return file_headscale_v1_autotag_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AutoTagChange) GetMachine() *Machine {
+	if x != nil {
+		return x.Machine
+	}
+	return nil
+}
+
+func (x *AutoTagChange) GetAddedTags() []string {
+	if x != nil {
+		return x.AddedTags
+	}
+	return nil
+}
+
+type SetAutoTagRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pattern string   `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	Tags    []string `protobuf:"bytes,2,rep,name=tags,proto3" json:"tags,omitempty"`
+}
+
+func (x *SetAutoTagRequest) Reset() {
+	*x = SetAutoTagRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_headscale_v1_autotag_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetAutoTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAutoTagRequest) ProtoMessage() {}
+
+func (x *SetAutoTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_headscale_v1_autotag_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAutoTagRequest.ProtoReflect.Descriptor instead.
+func (*SetAutoTagRequest) Descriptor() ([]byte, []int) {
+	return file_headscale_v1_autotag_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetAutoTagRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+func (x *SetAutoTagRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type SetAutoTagResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AutoTag *AutoTag `protobuf:"bytes,1,opt,name=auto_tag,json=autoTag,proto3" json:"auto_tag,omitempty"`
+}
+
+func (x *SetAutoTagResponse) Reset() {
+	*x = SetAutoTagResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_headscale_v1_autotag_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetAutoTagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAutoTagResponse) ProtoMessage() {}
+
+func (x *SetAutoTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_headscale_v1_autotag_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAutoTagResponse.ProtoReflect.Descriptor instead.
+func (*SetAutoTagResponse) Descriptor() ([]byte, []int) {
+	return file_headscale_v1_autotag_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SetAutoTagResponse) GetAutoTag() *AutoTag {
+	if x != nil {
+		return x.AutoTag
+	}
+	return nil
+}
+
+type DeleteAutoTagRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pattern string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+}
+
+func (x *DeleteAutoTagRequest) Reset() {
+	*x = DeleteAutoTagRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_headscale_v1_autotag_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteAutoTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAutoTagRequest) ProtoMessage() {}
+
+func (x *DeleteAutoTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_headscale_v1_autotag_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAutoTagRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAutoTagRequest) Descriptor() ([]byte, []int) {
+	return file_headscale_v1_autotag_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeleteAutoTagRequest) GetPattern() string {
+	if x != nil {
+		return x.Pattern
+	}
+	return ""
+}
+
+type DeleteAutoTagResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteAutoTagResponse) Reset() {
+	*x = DeleteAutoTagResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_headscale_v1_autotag_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteAutoTagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAutoTagResponse) ProtoMessage() {}
+
+func (x *DeleteAutoTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_headscale_v1_autotag_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAutoTagResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAutoTagResponse) Descriptor() ([]byte, []int) {
+	return file_headscale_v1_autotag_proto_rawDescGZIP(), []int{5}
+}
+
+type ListAutoTagsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListAutoTagsRequest) Reset() {
+	*x = ListAutoTagsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_headscale_v1_autotag_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAutoTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAutoTagsRequest) ProtoMessage() {}
+
+func (x *ListAutoTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_headscale_v1_autotag_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAutoTagsRequest.ProtoReflect.Descriptor instead.
+func (*ListAutoTagsRequest) Descriptor() ([]byte, []int) {
+	return file_headscale_v1_autotag_proto_rawDescGZIP(), []int{6}
+}
+
+type ListAutoTagsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AutoTags []*AutoTag `protobuf:"bytes,1,rep,name=auto_tags,json=autoTags,proto3" json:"auto_tags,omitempty"`
+}
+
+func (x *ListAutoTagsResponse) Reset() {
+	*x = ListAutoTagsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_headscale_v1_autotag_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListAutoTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAutoTagsResponse) ProtoMessage() {}
+
+func (x *ListAutoTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_headscale_v1_autotag_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAutoTagsResponse.ProtoReflect.Descriptor instead.
+func (*ListAutoTagsResponse) Descriptor() ([]byte, []int) {
+	return file_headscale_v1_autotag_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListAutoTagsResponse) GetAutoTags() []*AutoTag {
+	if x != nil {
+		return x.AutoTags
+	}
+	return nil
+}
+
+type ApplyAutoTagsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	DryRun bool `protobuf:"varint,1,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+}
+
+func (x *ApplyAutoTagsRequest) Reset() {
+	*x = ApplyAutoTagsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_headscale_v1_autotag_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApplyAutoTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyAutoTagsRequest) ProtoMessage() {}
+
+func (x *ApplyAutoTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_headscale_v1_autotag_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyAutoTagsRequest.ProtoReflect.Descriptor instead.
+func (*ApplyAutoTagsRequest) Descriptor() ([]byte, []int) {
+	return file_headscale_v1_autotag_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ApplyAutoTagsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ApplyAutoTagsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Changes []*AutoTagChange `protobuf:"bytes,1,rep,name=changes,proto3" json:"changes,omitempty"`
+}
+
+func (x *ApplyAutoTagsResponse) Reset() {
+	*x = ApplyAutoTagsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_headscale_v1_autotag_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApplyAutoTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyAutoTagsResponse) ProtoMessage() {}
+
+func (x *ApplyAutoTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_headscale_v1_autotag_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyAutoTagsResponse.ProtoReflect.Descriptor instead.
+func (*ApplyAutoTagsResponse) Descriptor() ([]byte, []int) {
+	return file_headscale_v1_autotag_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ApplyAutoTagsResponse) GetChanges() []*AutoTagChange {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+var File_headscale_v1_autotag_proto protoreflect.FileDescriptor
+
+var file_headscale_v1_autotag_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x68, 0x65, 0x61, 0x64, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x61,
+	0x75, 0x74, 0x6f, 0x74, 0x61, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x68, 0x65,
+	0x61, 0x64, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x1a, 0x1a, 0x68, 0x65, 0x61, 0x64,
+	0x73, 0x63, 0x61, 0x6c, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x4f, 0x0a, 0x07, 0x41, 0x75, 0x74, 0x6f, 0x54, 0x61,
+	0x67, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74,
+	0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x5f, 0x0a, 0x0d, 0x41, 0x75, 0x74, 0x6f, 0x54,
+	0x61, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x6d, 0x61, 0x63, 0x68,
+	0x69, 0x6e, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x68, 0x65, 0x61, 0x64,
+	0x73, 0x63, 0x61, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65,
+	0x52, 0x07, 0x6d, 0x61, 0x63, 0x68, 0x69, 0x6e, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x64, 0x64,
+	0x65, 0x64, 0x5f, 0x74, 0x61, 0x67, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61,
+	0x64, 0x64, 0x65, 0x64, 0x54, 0x61, 0x67, 0x73, 0x22, 0x41, 0x0a, 0x11, 0x53, 0x65, 0x74, 0x41,
+	0x75, 0x74, 0x6f, 0x54, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x22, 0x46, 0x0a, 0x12, 0x53,
+	0x65, 0x74, 0x41, 0x75, 0x74, 0x6f, 0x54, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x30, 0x0a, 0x08, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x74, 0x61, 0x67, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x68, 0x65, 0x61, 0x64, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x41, 0x75, 0x74, 0x6f, 0x54, 0x61, 0x67, 0x52, 0x07, 0x61, 0x75, 0x74, 0x6f,
+	0x54, 0x61, 0x67, 0x22, 0x30, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x75, 0x74,
+	0x6f, 0x54, 0x61, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70,
+	0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61,
+	0x74, 0x74, 0x65, 0x72, 0x6e, 0x22, 0x17, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41,
+	0x75, 0x74, 0x6f, 0x54, 0x61, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x15,
+	0x0a, 0x13, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x74, 0x6f, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4a, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x41, 0x75, 0x74,
+	0x6f, 0x54, 0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x32, 0x0a,
+	0x09, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x74, 0x61, 0x67, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x68, 0x65, 0x61, 0x64, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x41, 0x75, 0x74, 0x6f, 0x54, 0x61, 0x67, 0x52, 0x08, 0x61, 0x75, 0x74, 0x6f, 0x54, 0x61, 0x67,
+	0x73, 0x22, 0x2f, 0x0a, 0x14, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x41, 0x75, 0x74, 0x6f, 0x54, 0x61,
+	0x67, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x64, 0x72, 0x79,
+	0x5f, 0x72, 0x75, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x64, 0x72, 0x79, 0x52,
+	0x75, 0x6e, 0x22, 0x4e, 0x0a, 0x15, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x41, 0x75, 0x74, 0x6f, 0x54,
+	0x61, 0x67, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x07, 0x63,
+	0x68, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x68,
+	0x65, 0x61, 0x64, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x74, 0x6f,
+	0x54, 0x61, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x73, 0x42, 0x29, 0x5a, 0x27, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x6a, 0x75, 0x61, 0x6e, 0x66, 0x6f, 0x6e, 0x74, 0x2f, 0x68, 0x65, 0x61, 0x64, 0x73, 0x63,
+	0x61, 0x6c, 0x65, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67, 0x6f, 0x2f, 0x76, 0x31, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_headscale_v1_autotag_proto_rawDescOnce sync.Once
+	file_headscale_v1_autotag_proto_rawDescData = file_headscale_v1_autotag_proto_rawDesc
+)
+
+func file_headscale_v1_autotag_proto_rawDescGZIP() []byte {
+	file_headscale_v1_autotag_proto_rawDescOnce.Do(func() {
+		file_headscale_v1_autotag_proto_rawDescData = protoimpl.X.CompressGZIP(file_headscale_v1_autotag_proto_rawDescData)
+	})
+	return file_headscale_v1_autotag_proto_rawDescData
+}
+
+var file_headscale_v1_autotag_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_headscale_v1_autotag_proto_goTypes = []interface{}{
+	(*AutoTag)(nil),               // 0: headscale.v1.AutoTag
+	(*AutoTagChange)(nil),         // 1: headscale.v1.AutoTagChange
+	(*SetAutoTagRequest)(nil),     // 2: headscale.v1.SetAutoTagRequest
+	(*SetAutoTagResponse)(nil),    // 3: headscale.v1.SetAutoTagResponse
+	(*DeleteAutoTagRequest)(nil),  // 4: headscale.v1.DeleteAutoTagRequest
+	(*DeleteAutoTagResponse)(nil), // 5: headscale.v1.DeleteAutoTagResponse
+	(*ListAutoTagsRequest)(nil),   // 6: headscale.v1.ListAutoTagsRequest
+	(*ListAutoTagsResponse)(nil),  // 7: headscale.v1.ListAutoTagsResponse
+	(*ApplyAutoTagsRequest)(nil),  // 8: headscale.v1.ApplyAutoTagsRequest
+	(*ApplyAutoTagsResponse)(nil), // 9: headscale.v1.ApplyAutoTagsResponse
+	(*Machine)(nil),               // 10: headscale.v1.Machine
+}
+var file_headscale_v1_autotag_proto_depIdxs = []int32{
+	10, // 0: headscale.v1.AutoTagChange.machine:type_name -> headscale.v1.Machine
+	0,  // 1: headscale.v1.SetAutoTagResponse.auto_tag:type_name -> headscale.v1.AutoTag
+	0,  // 2: headscale.v1.ListAutoTagsResponse.auto_tags:type_name -> headscale.v1.AutoTag
+	1,  // 3: headscale.v1.ApplyAutoTagsResponse.changes:type_name -> headscale.v1.AutoTagChange
+	4,  // [4:4] is the sub-list for method output_type
+	4,  // [4:4] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_headscale_v1_autotag_proto_init() }
+func file_headscale_v1_autotag_proto_init() {
+	if File_headscale_v1_autotag_proto != nil {
+		return
+	}
+	file_headscale_v1_machine_proto_init()
+	if !protoimpl.UnsafeEnabled {
+		file_headscale_v1_autotag_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AutoTag); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_headscale_v1_autotag_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AutoTagChange); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_headscale_v1_autotag_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetAutoTagRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_headscale_v1_autotag_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetAutoTagResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_headscale_v1_autotag_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteAutoTagRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_headscale_v1_autotag_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteAutoTagResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_headscale_v1_autotag_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAutoTagsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_headscale_v1_autotag_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListAutoTagsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_headscale_v1_autotag_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApplyAutoTagsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_headscale_v1_autotag_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ApplyAutoTagsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_headscale_v1_autotag_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_headscale_v1_autotag_proto_goTypes,
+		DependencyIndexes: file_headscale_v1_autotag_proto_depIdxs,
+		MessageInfos:      file_headscale_v1_autotag_proto_msgTypes,
+	}.Build()
+	File_headscale_v1_autotag_proto = out.File
+	file_headscale_v1_autotag_proto_rawDesc = nil
+	file_headscale_v1_autotag_proto_goTypes = nil
+	file_headscale_v1_autotag_proto_depIdxs = nil
+}
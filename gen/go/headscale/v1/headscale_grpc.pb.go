@@ -22,8 +22,13 @@ type HeadscaleServiceClient interface {
 	GetNamespace(ctx context.Context, in *GetNamespaceRequest, opts ...grpc.CallOption) (*GetNamespaceResponse, error)
 	CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...grpc.CallOption) (*CreateNamespaceResponse, error)
 	RenameNamespace(ctx context.Context, in *RenameNamespaceRequest, opts ...grpc.CallOption) (*RenameNamespaceResponse, error)
+	SetNamespaceDNSDomain(ctx context.Context, in *SetNamespaceDNSDomainRequest, opts ...grpc.CallOption) (*SetNamespaceDNSDomainResponse, error)
+	SetNamespaceIdleExpiry(ctx context.Context, in *SetNamespaceIdleExpiryRequest, opts ...grpc.CallOption) (*SetNamespaceIdleExpiryResponse, error)
+	SetNamespaceMTU(ctx context.Context, in *SetNamespaceMTURequest, opts ...grpc.CallOption) (*SetNamespaceMTUResponse, error)
+	SetNamespaceLocked(ctx context.Context, in *SetNamespaceLockedRequest, opts ...grpc.CallOption) (*SetNamespaceLockedResponse, error)
 	DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, opts ...grpc.CallOption) (*DeleteNamespaceResponse, error)
 	ListNamespaces(ctx context.Context, in *ListNamespacesRequest, opts ...grpc.CallOption) (*ListNamespacesResponse, error)
+	GetNamespaceImpact(ctx context.Context, in *GetNamespaceImpactRequest, opts ...grpc.CallOption) (*GetNamespaceImpactResponse, error)
 	// --- PreAuthKeys start ---
 	CreatePreAuthKey(ctx context.Context, in *CreatePreAuthKeyRequest, opts ...grpc.CallOption) (*CreatePreAuthKeyResponse, error)
 	ExpirePreAuthKey(ctx context.Context, in *ExpirePreAuthKeyRequest, opts ...grpc.CallOption) (*ExpirePreAuthKeyResponse, error)
@@ -35,16 +40,53 @@ type HeadscaleServiceClient interface {
 	RegisterMachine(ctx context.Context, in *RegisterMachineRequest, opts ...grpc.CallOption) (*RegisterMachineResponse, error)
 	DeleteMachine(ctx context.Context, in *DeleteMachineRequest, opts ...grpc.CallOption) (*DeleteMachineResponse, error)
 	ExpireMachine(ctx context.Context, in *ExpireMachineRequest, opts ...grpc.CallOption) (*ExpireMachineResponse, error)
+	SetMachineExpiry(ctx context.Context, in *SetMachineExpiryRequest, opts ...grpc.CallOption) (*SetMachineExpiryResponse, error)
+	DumpMachineNetmap(ctx context.Context, in *DumpMachineNetmapRequest, opts ...grpc.CallOption) (*DumpMachineNetmapResponse, error)
+	RefreshMachineKey(ctx context.Context, in *RefreshMachineKeyRequest, opts ...grpc.CallOption) (*RefreshMachineKeyResponse, error)
+	BackfillMachineIPs(ctx context.Context, in *BackfillMachineIPsRequest, opts ...grpc.CallOption) (*BackfillMachineIPsResponse, error)
+	ImportMachine(ctx context.Context, in *ImportMachineRequest, opts ...grpc.CallOption) (*ImportMachineResponse, error)
+	QuarantineMachine(ctx context.Context, in *QuarantineMachineRequest, opts ...grpc.CallOption) (*QuarantineMachineResponse, error)
+	UnquarantineMachine(ctx context.Context, in *UnquarantineMachineRequest, opts ...grpc.CallOption) (*UnquarantineMachineResponse, error)
+	ListPendingRegistrations(ctx context.Context, in *ListPendingRegistrationsRequest, opts ...grpc.CallOption) (*ListPendingRegistrationsResponse, error)
+	ApproveRegistration(ctx context.Context, in *ApproveRegistrationRequest, opts ...grpc.CallOption) (*ApproveRegistrationResponse, error)
+	ApplyMachine(ctx context.Context, in *ApplyMachineRequest, opts ...grpc.CallOption) (*ApplyMachineResponse, error)
+	UnmanageMachine(ctx context.Context, in *UnmanageMachineRequest, opts ...grpc.CallOption) (*UnmanageMachineResponse, error)
+	RestoreMachine(ctx context.Context, in *RestoreMachineRequest, opts ...grpc.CallOption) (*RestoreMachineResponse, error)
+	SetDERPRegion(ctx context.Context, in *SetDERPRegionRequest, opts ...grpc.CallOption) (*SetDERPRegionResponse, error)
+	SetExitNodeOnly(ctx context.Context, in *SetExitNodeOnlyRequest, opts ...grpc.CallOption) (*SetExitNodeOnlyResponse, error)
+	TouchMachine(ctx context.Context, in *TouchMachineRequest, opts ...grpc.CallOption) (*TouchMachineResponse, error)
 	RenameMachine(ctx context.Context, in *RenameMachineRequest, opts ...grpc.CallOption) (*RenameMachineResponse, error)
 	ListMachines(ctx context.Context, in *ListMachinesRequest, opts ...grpc.CallOption) (*ListMachinesResponse, error)
 	MoveMachine(ctx context.Context, in *MoveMachineRequest, opts ...grpc.CallOption) (*MoveMachineResponse, error)
+	ListMachineConnections(ctx context.Context, in *ListMachineConnectionsRequest, opts ...grpc.CallOption) (*ListMachineConnectionsResponse, error)
 	// --- Route start ---
 	GetMachineRoute(ctx context.Context, in *GetMachineRouteRequest, opts ...grpc.CallOption) (*GetMachineRouteResponse, error)
 	EnableMachineRoutes(ctx context.Context, in *EnableMachineRoutesRequest, opts ...grpc.CallOption) (*EnableMachineRoutesResponse, error)
+	ListExitNodes(ctx context.Context, in *ListExitNodesRequest, opts ...grpc.CallOption) (*ListExitNodesResponse, error)
 	// --- ApiKeys start ---
 	CreateApiKey(ctx context.Context, in *CreateApiKeyRequest, opts ...grpc.CallOption) (*CreateApiKeyResponse, error)
 	ExpireApiKey(ctx context.Context, in *ExpireApiKeyRequest, opts ...grpc.CallOption) (*ExpireApiKeyResponse, error)
 	ListApiKeys(ctx context.Context, in *ListApiKeysRequest, opts ...grpc.CallOption) (*ListApiKeysResponse, error)
+	// --- ACL start ---
+	ListACLReferences(ctx context.Context, in *ListACLReferencesRequest, opts ...grpc.CallOption) (*ListACLReferencesResponse, error)
+	CheckACL(ctx context.Context, in *CheckACLRequest, opts ...grpc.CallOption) (*CheckACLResponse, error)
+	ListACLGroups(ctx context.Context, in *ListACLGroupsRequest, opts ...grpc.CallOption) (*ListACLGroupsResponse, error)
+	GetACLGroup(ctx context.Context, in *GetACLGroupRequest, opts ...grpc.CallOption) (*GetACLGroupResponse, error)
+	// --- DNS start ---
+	ListDNSRecords(ctx context.Context, in *ListDNSRecordsRequest, opts ...grpc.CallOption) (*ListDNSRecordsResponse, error)
+	// --- Stats start ---
+	GetNodeStats(ctx context.Context, in *GetNodeStatsRequest, opts ...grpc.CallOption) (*GetNodeStatsResponse, error)
+	// --- Template start ---
+	CreateNodeTemplate(ctx context.Context, in *CreateNodeTemplateRequest, opts ...grpc.CallOption) (*CreateNodeTemplateResponse, error)
+	ListNodeTemplates(ctx context.Context, in *ListNodeTemplatesRequest, opts ...grpc.CallOption) (*ListNodeTemplatesResponse, error)
+	DeleteNodeTemplate(ctx context.Context, in *DeleteNodeTemplateRequest, opts ...grpc.CallOption) (*DeleteNodeTemplateResponse, error)
+	// --- AutoTag start ---
+	SetAutoTag(ctx context.Context, in *SetAutoTagRequest, opts ...grpc.CallOption) (*SetAutoTagResponse, error)
+	DeleteAutoTag(ctx context.Context, in *DeleteAutoTagRequest, opts ...grpc.CallOption) (*DeleteAutoTagResponse, error)
+	ListAutoTags(ctx context.Context, in *ListAutoTagsRequest, opts ...grpc.CallOption) (*ListAutoTagsResponse, error)
+	ApplyAutoTags(ctx context.Context, in *ApplyAutoTagsRequest, opts ...grpc.CallOption) (*ApplyAutoTagsResponse, error)
+	// --- Version start ---
+	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error)
 }
 
 type headscaleServiceClient struct {
@@ -82,6 +124,42 @@ func (c *headscaleServiceClient) RenameNamespace(ctx context.Context, in *Rename
 	return out, nil
 }
 
+func (c *headscaleServiceClient) SetNamespaceDNSDomain(ctx context.Context, in *SetNamespaceDNSDomainRequest, opts ...grpc.CallOption) (*SetNamespaceDNSDomainResponse, error) {
+	out := new(SetNamespaceDNSDomainResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/SetNamespaceDNSDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) SetNamespaceIdleExpiry(ctx context.Context, in *SetNamespaceIdleExpiryRequest, opts ...grpc.CallOption) (*SetNamespaceIdleExpiryResponse, error) {
+	out := new(SetNamespaceIdleExpiryResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/SetNamespaceIdleExpiry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) SetNamespaceMTU(ctx context.Context, in *SetNamespaceMTURequest, opts ...grpc.CallOption) (*SetNamespaceMTUResponse, error) {
+	out := new(SetNamespaceMTUResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/SetNamespaceMTU", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) SetNamespaceLocked(ctx context.Context, in *SetNamespaceLockedRequest, opts ...grpc.CallOption) (*SetNamespaceLockedResponse, error) {
+	out := new(SetNamespaceLockedResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/SetNamespaceLocked", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *headscaleServiceClient) DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, opts ...grpc.CallOption) (*DeleteNamespaceResponse, error) {
 	out := new(DeleteNamespaceResponse)
 	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/DeleteNamespace", in, out, opts...)
@@ -100,6 +178,15 @@ func (c *headscaleServiceClient) ListNamespaces(ctx context.Context, in *ListNam
 	return out, nil
 }
 
+func (c *headscaleServiceClient) GetNamespaceImpact(ctx context.Context, in *GetNamespaceImpactRequest, opts ...grpc.CallOption) (*GetNamespaceImpactResponse, error) {
+	out := new(GetNamespaceImpactResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/GetNamespaceImpact", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *headscaleServiceClient) CreatePreAuthKey(ctx context.Context, in *CreatePreAuthKeyRequest, opts ...grpc.CallOption) (*CreatePreAuthKeyResponse, error) {
 	out := new(CreatePreAuthKeyResponse)
 	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/CreatePreAuthKey", in, out, opts...)
@@ -181,6 +268,141 @@ func (c *headscaleServiceClient) ExpireMachine(ctx context.Context, in *ExpireMa
 	return out, nil
 }
 
+func (c *headscaleServiceClient) SetMachineExpiry(ctx context.Context, in *SetMachineExpiryRequest, opts ...grpc.CallOption) (*SetMachineExpiryResponse, error) {
+	out := new(SetMachineExpiryResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/SetMachineExpiry", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) DumpMachineNetmap(ctx context.Context, in *DumpMachineNetmapRequest, opts ...grpc.CallOption) (*DumpMachineNetmapResponse, error) {
+	out := new(DumpMachineNetmapResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/DumpMachineNetmap", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) RefreshMachineKey(ctx context.Context, in *RefreshMachineKeyRequest, opts ...grpc.CallOption) (*RefreshMachineKeyResponse, error) {
+	out := new(RefreshMachineKeyResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/RefreshMachineKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) BackfillMachineIPs(ctx context.Context, in *BackfillMachineIPsRequest, opts ...grpc.CallOption) (*BackfillMachineIPsResponse, error) {
+	out := new(BackfillMachineIPsResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/BackfillMachineIPs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ImportMachine(ctx context.Context, in *ImportMachineRequest, opts ...grpc.CallOption) (*ImportMachineResponse, error) {
+	out := new(ImportMachineResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ImportMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) QuarantineMachine(ctx context.Context, in *QuarantineMachineRequest, opts ...grpc.CallOption) (*QuarantineMachineResponse, error) {
+	out := new(QuarantineMachineResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/QuarantineMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) UnquarantineMachine(ctx context.Context, in *UnquarantineMachineRequest, opts ...grpc.CallOption) (*UnquarantineMachineResponse, error) {
+	out := new(UnquarantineMachineResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/UnquarantineMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ListPendingRegistrations(ctx context.Context, in *ListPendingRegistrationsRequest, opts ...grpc.CallOption) (*ListPendingRegistrationsResponse, error) {
+	out := new(ListPendingRegistrationsResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ListPendingRegistrations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ApproveRegistration(ctx context.Context, in *ApproveRegistrationRequest, opts ...grpc.CallOption) (*ApproveRegistrationResponse, error) {
+	out := new(ApproveRegistrationResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ApproveRegistration", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ApplyMachine(ctx context.Context, in *ApplyMachineRequest, opts ...grpc.CallOption) (*ApplyMachineResponse, error) {
+	out := new(ApplyMachineResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ApplyMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) UnmanageMachine(ctx context.Context, in *UnmanageMachineRequest, opts ...grpc.CallOption) (*UnmanageMachineResponse, error) {
+	out := new(UnmanageMachineResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/UnmanageMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) RestoreMachine(ctx context.Context, in *RestoreMachineRequest, opts ...grpc.CallOption) (*RestoreMachineResponse, error) {
+	out := new(RestoreMachineResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/RestoreMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) SetDERPRegion(ctx context.Context, in *SetDERPRegionRequest, opts ...grpc.CallOption) (*SetDERPRegionResponse, error) {
+	out := new(SetDERPRegionResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/SetDERPRegion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) SetExitNodeOnly(ctx context.Context, in *SetExitNodeOnlyRequest, opts ...grpc.CallOption) (*SetExitNodeOnlyResponse, error) {
+	out := new(SetExitNodeOnlyResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/SetExitNodeOnly", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) TouchMachine(ctx context.Context, in *TouchMachineRequest, opts ...grpc.CallOption) (*TouchMachineResponse, error) {
+	out := new(TouchMachineResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/TouchMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *headscaleServiceClient) RenameMachine(ctx context.Context, in *RenameMachineRequest, opts ...grpc.CallOption) (*RenameMachineResponse, error) {
 	out := new(RenameMachineResponse)
 	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/RenameMachine", in, out, opts...)
@@ -208,6 +430,15 @@ func (c *headscaleServiceClient) MoveMachine(ctx context.Context, in *MoveMachin
 	return out, nil
 }
 
+func (c *headscaleServiceClient) ListMachineConnections(ctx context.Context, in *ListMachineConnectionsRequest, opts ...grpc.CallOption) (*ListMachineConnectionsResponse, error) {
+	out := new(ListMachineConnectionsResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ListMachineConnections", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *headscaleServiceClient) GetMachineRoute(ctx context.Context, in *GetMachineRouteRequest, opts ...grpc.CallOption) (*GetMachineRouteResponse, error) {
 	out := new(GetMachineRouteResponse)
 	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/GetMachineRoute", in, out, opts...)
@@ -226,6 +457,15 @@ func (c *headscaleServiceClient) EnableMachineRoutes(ctx context.Context, in *En
 	return out, nil
 }
 
+func (c *headscaleServiceClient) ListExitNodes(ctx context.Context, in *ListExitNodesRequest, opts ...grpc.CallOption) (*ListExitNodesResponse, error) {
+	out := new(ListExitNodesResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ListExitNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *headscaleServiceClient) CreateApiKey(ctx context.Context, in *CreateApiKeyRequest, opts ...grpc.CallOption) (*CreateApiKeyResponse, error) {
 	out := new(CreateApiKeyResponse)
 	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/CreateApiKey", in, out, opts...)
@@ -253,6 +493,132 @@ func (c *headscaleServiceClient) ListApiKeys(ctx context.Context, in *ListApiKey
 	return out, nil
 }
 
+func (c *headscaleServiceClient) ListACLReferences(ctx context.Context, in *ListACLReferencesRequest, opts ...grpc.CallOption) (*ListACLReferencesResponse, error) {
+	out := new(ListACLReferencesResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ListACLReferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) CheckACL(ctx context.Context, in *CheckACLRequest, opts ...grpc.CallOption) (*CheckACLResponse, error) {
+	out := new(CheckACLResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/CheckACL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ListACLGroups(ctx context.Context, in *ListACLGroupsRequest, opts ...grpc.CallOption) (*ListACLGroupsResponse, error) {
+	out := new(ListACLGroupsResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ListACLGroups", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) GetACLGroup(ctx context.Context, in *GetACLGroupRequest, opts ...grpc.CallOption) (*GetACLGroupResponse, error) {
+	out := new(GetACLGroupResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/GetACLGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ListDNSRecords(ctx context.Context, in *ListDNSRecordsRequest, opts ...grpc.CallOption) (*ListDNSRecordsResponse, error) {
+	out := new(ListDNSRecordsResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ListDNSRecords", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) GetNodeStats(ctx context.Context, in *GetNodeStatsRequest, opts ...grpc.CallOption) (*GetNodeStatsResponse, error) {
+	out := new(GetNodeStatsResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/GetNodeStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) CreateNodeTemplate(ctx context.Context, in *CreateNodeTemplateRequest, opts ...grpc.CallOption) (*CreateNodeTemplateResponse, error) {
+	out := new(CreateNodeTemplateResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/CreateNodeTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ListNodeTemplates(ctx context.Context, in *ListNodeTemplatesRequest, opts ...grpc.CallOption) (*ListNodeTemplatesResponse, error) {
+	out := new(ListNodeTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ListNodeTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) DeleteNodeTemplate(ctx context.Context, in *DeleteNodeTemplateRequest, opts ...grpc.CallOption) (*DeleteNodeTemplateResponse, error) {
+	out := new(DeleteNodeTemplateResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/DeleteNodeTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) SetAutoTag(ctx context.Context, in *SetAutoTagRequest, opts ...grpc.CallOption) (*SetAutoTagResponse, error) {
+	out := new(SetAutoTagResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/SetAutoTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) DeleteAutoTag(ctx context.Context, in *DeleteAutoTagRequest, opts ...grpc.CallOption) (*DeleteAutoTagResponse, error) {
+	out := new(DeleteAutoTagResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/DeleteAutoTag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ListAutoTags(ctx context.Context, in *ListAutoTagsRequest, opts ...grpc.CallOption) (*ListAutoTagsResponse, error) {
+	out := new(ListAutoTagsResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ListAutoTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) ApplyAutoTags(ctx context.Context, in *ApplyAutoTagsRequest, opts ...grpc.CallOption) (*ApplyAutoTagsResponse, error) {
+	out := new(ApplyAutoTagsResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/ApplyAutoTags", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *headscaleServiceClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error) {
+	out := new(GetVersionResponse)
+	err := c.cc.Invoke(ctx, "/headscale.v1.HeadscaleService/GetVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HeadscaleServiceServer is the server API for HeadscaleService service.
 // All implementations must embed UnimplementedHeadscaleServiceServer
 // for forward compatibility
@@ -261,8 +627,13 @@ type HeadscaleServiceServer interface {
 	GetNamespace(context.Context, *GetNamespaceRequest) (*GetNamespaceResponse, error)
 	CreateNamespace(context.Context, *CreateNamespaceRequest) (*CreateNamespaceResponse, error)
 	RenameNamespace(context.Context, *RenameNamespaceRequest) (*RenameNamespaceResponse, error)
+	SetNamespaceDNSDomain(context.Context, *SetNamespaceDNSDomainRequest) (*SetNamespaceDNSDomainResponse, error)
+	SetNamespaceIdleExpiry(context.Context, *SetNamespaceIdleExpiryRequest) (*SetNamespaceIdleExpiryResponse, error)
+	SetNamespaceMTU(context.Context, *SetNamespaceMTURequest) (*SetNamespaceMTUResponse, error)
+	SetNamespaceLocked(context.Context, *SetNamespaceLockedRequest) (*SetNamespaceLockedResponse, error)
 	DeleteNamespace(context.Context, *DeleteNamespaceRequest) (*DeleteNamespaceResponse, error)
 	ListNamespaces(context.Context, *ListNamespacesRequest) (*ListNamespacesResponse, error)
+	GetNamespaceImpact(context.Context, *GetNamespaceImpactRequest) (*GetNamespaceImpactResponse, error)
 	// --- PreAuthKeys start ---
 	CreatePreAuthKey(context.Context, *CreatePreAuthKeyRequest) (*CreatePreAuthKeyResponse, error)
 	ExpirePreAuthKey(context.Context, *ExpirePreAuthKeyRequest) (*ExpirePreAuthKeyResponse, error)
@@ -274,16 +645,53 @@ type HeadscaleServiceServer interface {
 	RegisterMachine(context.Context, *RegisterMachineRequest) (*RegisterMachineResponse, error)
 	DeleteMachine(context.Context, *DeleteMachineRequest) (*DeleteMachineResponse, error)
 	ExpireMachine(context.Context, *ExpireMachineRequest) (*ExpireMachineResponse, error)
+	SetMachineExpiry(context.Context, *SetMachineExpiryRequest) (*SetMachineExpiryResponse, error)
+	DumpMachineNetmap(context.Context, *DumpMachineNetmapRequest) (*DumpMachineNetmapResponse, error)
+	RefreshMachineKey(context.Context, *RefreshMachineKeyRequest) (*RefreshMachineKeyResponse, error)
+	BackfillMachineIPs(context.Context, *BackfillMachineIPsRequest) (*BackfillMachineIPsResponse, error)
+	ImportMachine(context.Context, *ImportMachineRequest) (*ImportMachineResponse, error)
+	QuarantineMachine(context.Context, *QuarantineMachineRequest) (*QuarantineMachineResponse, error)
+	UnquarantineMachine(context.Context, *UnquarantineMachineRequest) (*UnquarantineMachineResponse, error)
+	ListPendingRegistrations(context.Context, *ListPendingRegistrationsRequest) (*ListPendingRegistrationsResponse, error)
+	ApproveRegistration(context.Context, *ApproveRegistrationRequest) (*ApproveRegistrationResponse, error)
+	ApplyMachine(context.Context, *ApplyMachineRequest) (*ApplyMachineResponse, error)
+	UnmanageMachine(context.Context, *UnmanageMachineRequest) (*UnmanageMachineResponse, error)
+	RestoreMachine(context.Context, *RestoreMachineRequest) (*RestoreMachineResponse, error)
+	SetDERPRegion(context.Context, *SetDERPRegionRequest) (*SetDERPRegionResponse, error)
+	SetExitNodeOnly(context.Context, *SetExitNodeOnlyRequest) (*SetExitNodeOnlyResponse, error)
+	TouchMachine(context.Context, *TouchMachineRequest) (*TouchMachineResponse, error)
 	RenameMachine(context.Context, *RenameMachineRequest) (*RenameMachineResponse, error)
 	ListMachines(context.Context, *ListMachinesRequest) (*ListMachinesResponse, error)
 	MoveMachine(context.Context, *MoveMachineRequest) (*MoveMachineResponse, error)
+	ListMachineConnections(context.Context, *ListMachineConnectionsRequest) (*ListMachineConnectionsResponse, error)
 	// --- Route start ---
 	GetMachineRoute(context.Context, *GetMachineRouteRequest) (*GetMachineRouteResponse, error)
 	EnableMachineRoutes(context.Context, *EnableMachineRoutesRequest) (*EnableMachineRoutesResponse, error)
+	ListExitNodes(context.Context, *ListExitNodesRequest) (*ListExitNodesResponse, error)
 	// --- ApiKeys start ---
 	CreateApiKey(context.Context, *CreateApiKeyRequest) (*CreateApiKeyResponse, error)
 	ExpireApiKey(context.Context, *ExpireApiKeyRequest) (*ExpireApiKeyResponse, error)
 	ListApiKeys(context.Context, *ListApiKeysRequest) (*ListApiKeysResponse, error)
+	// --- ACL start ---
+	ListACLReferences(context.Context, *ListACLReferencesRequest) (*ListACLReferencesResponse, error)
+	CheckACL(context.Context, *CheckACLRequest) (*CheckACLResponse, error)
+	ListACLGroups(context.Context, *ListACLGroupsRequest) (*ListACLGroupsResponse, error)
+	GetACLGroup(context.Context, *GetACLGroupRequest) (*GetACLGroupResponse, error)
+	// --- DNS start ---
+	ListDNSRecords(context.Context, *ListDNSRecordsRequest) (*ListDNSRecordsResponse, error)
+	// --- Stats start ---
+	GetNodeStats(context.Context, *GetNodeStatsRequest) (*GetNodeStatsResponse, error)
+	// --- Template start ---
+	CreateNodeTemplate(context.Context, *CreateNodeTemplateRequest) (*CreateNodeTemplateResponse, error)
+	ListNodeTemplates(context.Context, *ListNodeTemplatesRequest) (*ListNodeTemplatesResponse, error)
+	DeleteNodeTemplate(context.Context, *DeleteNodeTemplateRequest) (*DeleteNodeTemplateResponse, error)
+	// --- AutoTag start ---
+	SetAutoTag(context.Context, *SetAutoTagRequest) (*SetAutoTagResponse, error)
+	DeleteAutoTag(context.Context, *DeleteAutoTagRequest) (*DeleteAutoTagResponse, error)
+	ListAutoTags(context.Context, *ListAutoTagsRequest) (*ListAutoTagsResponse, error)
+	ApplyAutoTags(context.Context, *ApplyAutoTagsRequest) (*ApplyAutoTagsResponse, error)
+	// --- Version start ---
+	GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error)
 	mustEmbedUnimplementedHeadscaleServiceServer()
 }
 
@@ -300,12 +708,27 @@ func (UnimplementedHeadscaleServiceServer) CreateNamespace(context.Context, *Cre
 func (UnimplementedHeadscaleServiceServer) RenameNamespace(context.Context, *RenameNamespaceRequest) (*RenameNamespaceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RenameNamespace not implemented")
 }
+func (UnimplementedHeadscaleServiceServer) SetNamespaceDNSDomain(context.Context, *SetNamespaceDNSDomainRequest) (*SetNamespaceDNSDomainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNamespaceDNSDomain not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) SetNamespaceIdleExpiry(context.Context, *SetNamespaceIdleExpiryRequest) (*SetNamespaceIdleExpiryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNamespaceIdleExpiry not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) SetNamespaceMTU(context.Context, *SetNamespaceMTURequest) (*SetNamespaceMTUResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNamespaceMTU not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) SetNamespaceLocked(context.Context, *SetNamespaceLockedRequest) (*SetNamespaceLockedResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNamespaceLocked not implemented")
+}
 func (UnimplementedHeadscaleServiceServer) DeleteNamespace(context.Context, *DeleteNamespaceRequest) (*DeleteNamespaceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteNamespace not implemented")
 }
 func (UnimplementedHeadscaleServiceServer) ListNamespaces(context.Context, *ListNamespacesRequest) (*ListNamespacesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListNamespaces not implemented")
 }
+func (UnimplementedHeadscaleServiceServer) GetNamespaceImpact(context.Context, *GetNamespaceImpactRequest) (*GetNamespaceImpactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNamespaceImpact not implemented")
+}
 func (UnimplementedHeadscaleServiceServer) CreatePreAuthKey(context.Context, *CreatePreAuthKeyRequest) (*CreatePreAuthKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreatePreAuthKey not implemented")
 }
@@ -333,6 +756,51 @@ func (UnimplementedHeadscaleServiceServer) DeleteMachine(context.Context, *Delet
 func (UnimplementedHeadscaleServiceServer) ExpireMachine(context.Context, *ExpireMachineRequest) (*ExpireMachineResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ExpireMachine not implemented")
 }
+func (UnimplementedHeadscaleServiceServer) SetMachineExpiry(context.Context, *SetMachineExpiryRequest) (*SetMachineExpiryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMachineExpiry not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) DumpMachineNetmap(context.Context, *DumpMachineNetmapRequest) (*DumpMachineNetmapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DumpMachineNetmap not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) RefreshMachineKey(context.Context, *RefreshMachineKeyRequest) (*RefreshMachineKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshMachineKey not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) BackfillMachineIPs(context.Context, *BackfillMachineIPsRequest) (*BackfillMachineIPsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BackfillMachineIPs not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ImportMachine(context.Context, *ImportMachineRequest) (*ImportMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportMachine not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) QuarantineMachine(context.Context, *QuarantineMachineRequest) (*QuarantineMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuarantineMachine not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) UnquarantineMachine(context.Context, *UnquarantineMachineRequest) (*UnquarantineMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnquarantineMachine not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ListPendingRegistrations(context.Context, *ListPendingRegistrationsRequest) (*ListPendingRegistrationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingRegistrations not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ApproveRegistration(context.Context, *ApproveRegistrationRequest) (*ApproveRegistrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveRegistration not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ApplyMachine(context.Context, *ApplyMachineRequest) (*ApplyMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyMachine not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) UnmanageMachine(context.Context, *UnmanageMachineRequest) (*UnmanageMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnmanageMachine not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) RestoreMachine(context.Context, *RestoreMachineRequest) (*RestoreMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreMachine not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) SetDERPRegion(context.Context, *SetDERPRegionRequest) (*SetDERPRegionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDERPRegion not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) SetExitNodeOnly(context.Context, *SetExitNodeOnlyRequest) (*SetExitNodeOnlyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetExitNodeOnly not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) TouchMachine(context.Context, *TouchMachineRequest) (*TouchMachineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TouchMachine not implemented")
+}
 func (UnimplementedHeadscaleServiceServer) RenameMachine(context.Context, *RenameMachineRequest) (*RenameMachineResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RenameMachine not implemented")
 }
@@ -342,12 +810,18 @@ func (UnimplementedHeadscaleServiceServer) ListMachines(context.Context, *ListMa
 func (UnimplementedHeadscaleServiceServer) MoveMachine(context.Context, *MoveMachineRequest) (*MoveMachineResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method MoveMachine not implemented")
 }
+func (UnimplementedHeadscaleServiceServer) ListMachineConnections(context.Context, *ListMachineConnectionsRequest) (*ListMachineConnectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMachineConnections not implemented")
+}
 func (UnimplementedHeadscaleServiceServer) GetMachineRoute(context.Context, *GetMachineRouteRequest) (*GetMachineRouteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMachineRoute not implemented")
 }
 func (UnimplementedHeadscaleServiceServer) EnableMachineRoutes(context.Context, *EnableMachineRoutesRequest) (*EnableMachineRoutesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EnableMachineRoutes not implemented")
 }
+func (UnimplementedHeadscaleServiceServer) ListExitNodes(context.Context, *ListExitNodesRequest) (*ListExitNodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListExitNodes not implemented")
+}
 func (UnimplementedHeadscaleServiceServer) CreateApiKey(context.Context, *CreateApiKeyRequest) (*CreateApiKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateApiKey not implemented")
 }
@@ -357,411 +831,1101 @@ func (UnimplementedHeadscaleServiceServer) ExpireApiKey(context.Context, *Expire
 func (UnimplementedHeadscaleServiceServer) ListApiKeys(context.Context, *ListApiKeysRequest) (*ListApiKeysResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListApiKeys not implemented")
 }
-func (UnimplementedHeadscaleServiceServer) mustEmbedUnimplementedHeadscaleServiceServer() {}
+func (UnimplementedHeadscaleServiceServer) ListACLReferences(context.Context, *ListACLReferencesRequest) (*ListACLReferencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListACLReferences not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) CheckACL(context.Context, *CheckACLRequest) (*CheckACLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckACL not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ListACLGroups(context.Context, *ListACLGroupsRequest) (*ListACLGroupsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListACLGroups not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) GetACLGroup(context.Context, *GetACLGroupRequest) (*GetACLGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetACLGroup not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ListDNSRecords(context.Context, *ListDNSRecordsRequest) (*ListDNSRecordsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDNSRecords not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) GetNodeStats(context.Context, *GetNodeStatsRequest) (*GetNodeStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeStats not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) CreateNodeTemplate(context.Context, *CreateNodeTemplateRequest) (*CreateNodeTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateNodeTemplate not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ListNodeTemplates(context.Context, *ListNodeTemplatesRequest) (*ListNodeTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNodeTemplates not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) DeleteNodeTemplate(context.Context, *DeleteNodeTemplateRequest) (*DeleteNodeTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNodeTemplate not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) SetAutoTag(context.Context, *SetAutoTagRequest) (*SetAutoTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAutoTag not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) DeleteAutoTag(context.Context, *DeleteAutoTagRequest) (*DeleteAutoTagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAutoTag not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ListAutoTags(context.Context, *ListAutoTagsRequest) (*ListAutoTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAutoTags not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) ApplyAutoTags(context.Context, *ApplyAutoTagsRequest) (*ApplyAutoTagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyAutoTags not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (UnimplementedHeadscaleServiceServer) mustEmbedUnimplementedHeadscaleServiceServer() {}
+
+// UnsafeHeadscaleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HeadscaleServiceServer will
+// result in compilation errors.
+type UnsafeHeadscaleServiceServer interface {
+	mustEmbedUnimplementedHeadscaleServiceServer()
+}
+
+func RegisterHeadscaleServiceServer(s grpc.ServiceRegistrar, srv HeadscaleServiceServer) {
+	s.RegisterService(&HeadscaleService_ServiceDesc, srv)
+}
+
+func _HeadscaleService_GetNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).GetNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/GetNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).GetNamespace(ctx, req.(*GetNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_CreateNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).CreateNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/CreateNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).CreateNamespace(ctx, req.(*CreateNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_RenameNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).RenameNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/RenameNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).RenameNamespace(ctx, req.(*RenameNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_SetNamespaceDNSDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNamespaceDNSDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetNamespaceDNSDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/SetNamespaceDNSDomain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetNamespaceDNSDomain(ctx, req.(*SetNamespaceDNSDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_SetNamespaceIdleExpiry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNamespaceIdleExpiryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetNamespaceIdleExpiry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/SetNamespaceIdleExpiry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetNamespaceIdleExpiry(ctx, req.(*SetNamespaceIdleExpiryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_SetNamespaceMTU_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNamespaceMTURequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetNamespaceMTU(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/SetNamespaceMTU",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetNamespaceMTU(ctx, req.(*SetNamespaceMTURequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_SetNamespaceLocked_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNamespaceLockedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetNamespaceLocked(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/SetNamespaceLocked",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetNamespaceLocked(ctx, req.(*SetNamespaceLockedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_DeleteNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNamespaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).DeleteNamespace(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/DeleteNamespace",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).DeleteNamespace(ctx, req.(*DeleteNamespaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ListNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNamespacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ListNamespaces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/ListNamespaces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ListNamespaces(ctx, req.(*ListNamespacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_GetNamespaceImpact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNamespaceImpactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).GetNamespaceImpact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/GetNamespaceImpact",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).GetNamespaceImpact(ctx, req.(*GetNamespaceImpactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_CreatePreAuthKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePreAuthKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).CreatePreAuthKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/CreatePreAuthKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).CreatePreAuthKey(ctx, req.(*CreatePreAuthKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ExpirePreAuthKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpirePreAuthKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ExpirePreAuthKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/ExpirePreAuthKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ExpirePreAuthKey(ctx, req.(*ExpirePreAuthKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ListPreAuthKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPreAuthKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ListPreAuthKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/ListPreAuthKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ListPreAuthKeys(ctx, req.(*ListPreAuthKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_DebugCreateMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugCreateMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).DebugCreateMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/DebugCreateMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).DebugCreateMachine(ctx, req.(*DebugCreateMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_GetMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).GetMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/GetMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).GetMachine(ctx, req.(*GetMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_SetTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/SetTags",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetTags(ctx, req.(*SetTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_RegisterMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).RegisterMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/RegisterMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).RegisterMachine(ctx, req.(*RegisterMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_DeleteMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).DeleteMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/DeleteMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).DeleteMachine(ctx, req.(*DeleteMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ExpireMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpireMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ExpireMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/ExpireMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ExpireMachine(ctx, req.(*ExpireMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_SetMachineExpiry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMachineExpiryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetMachineExpiry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/SetMachineExpiry",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetMachineExpiry(ctx, req.(*SetMachineExpiryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_DumpMachineNetmap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DumpMachineNetmapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).DumpMachineNetmap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/DumpMachineNetmap",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).DumpMachineNetmap(ctx, req.(*DumpMachineNetmapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_RefreshMachineKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshMachineKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).RefreshMachineKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/RefreshMachineKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).RefreshMachineKey(ctx, req.(*RefreshMachineKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_BackfillMachineIPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackfillMachineIPsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).BackfillMachineIPs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/BackfillMachineIPs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).BackfillMachineIPs(ctx, req.(*BackfillMachineIPsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ImportMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ImportMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/ImportMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ImportMachine(ctx, req.(*ImportMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_QuarantineMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuarantineMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).QuarantineMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/QuarantineMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).QuarantineMachine(ctx, req.(*QuarantineMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_UnquarantineMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnquarantineMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).UnquarantineMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/UnquarantineMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).UnquarantineMachine(ctx, req.(*UnquarantineMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ListPendingRegistrations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingRegistrationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ListPendingRegistrations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/ListPendingRegistrations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ListPendingRegistrations(ctx, req.(*ListPendingRegistrationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ApproveRegistration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveRegistrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ApproveRegistration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/ApproveRegistration",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ApproveRegistration(ctx, req.(*ApproveRegistrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_ApplyMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ApplyMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/ApplyMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ApplyMachine(ctx, req.(*ApplyMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_UnmanageMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnmanageMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).UnmanageMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/UnmanageMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).UnmanageMachine(ctx, req.(*UnmanageMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_RestoreMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).RestoreMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/RestoreMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).RestoreMachine(ctx, req.(*RestoreMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_SetDERPRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDERPRegionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetDERPRegion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/SetDERPRegion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetDERPRegion(ctx, req.(*SetDERPRegionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_SetExitNodeOnly_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetExitNodeOnlyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).SetExitNodeOnly(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/SetExitNodeOnly",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).SetExitNodeOnly(ctx, req.(*SetExitNodeOnlyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeadscaleService_TouchMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TouchMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).TouchMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/TouchMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).TouchMachine(ctx, req.(*TouchMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-// UnsafeHeadscaleServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to HeadscaleServiceServer will
-// result in compilation errors.
-type UnsafeHeadscaleServiceServer interface {
-	mustEmbedUnimplementedHeadscaleServiceServer()
+func _HeadscaleService_RenameMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).RenameMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/RenameMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).RenameMachine(ctx, req.(*RenameMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterHeadscaleServiceServer(s grpc.ServiceRegistrar, srv HeadscaleServiceServer) {
-	s.RegisterService(&HeadscaleService_ServiceDesc, srv)
+func _HeadscaleService_ListMachines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMachinesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeadscaleServiceServer).ListMachines(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/headscale.v1.HeadscaleService/ListMachines",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeadscaleServiceServer).ListMachines(ctx, req.(*ListMachinesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_GetNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetNamespaceRequest)
+func _HeadscaleService_MoveMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveMachineRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).GetNamespace(ctx, in)
+		return srv.(HeadscaleServiceServer).MoveMachine(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/GetNamespace",
+		FullMethod: "/headscale.v1.HeadscaleService/MoveMachine",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).GetNamespace(ctx, req.(*GetNamespaceRequest))
+		return srv.(HeadscaleServiceServer).MoveMachine(ctx, req.(*MoveMachineRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_CreateNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateNamespaceRequest)
+func _HeadscaleService_ListMachineConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMachineConnectionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).CreateNamespace(ctx, in)
+		return srv.(HeadscaleServiceServer).ListMachineConnections(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/CreateNamespace",
+		FullMethod: "/headscale.v1.HeadscaleService/ListMachineConnections",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).CreateNamespace(ctx, req.(*CreateNamespaceRequest))
+		return srv.(HeadscaleServiceServer).ListMachineConnections(ctx, req.(*ListMachineConnectionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_RenameNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RenameNamespaceRequest)
+func _HeadscaleService_GetMachineRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMachineRouteRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).RenameNamespace(ctx, in)
+		return srv.(HeadscaleServiceServer).GetMachineRoute(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/RenameNamespace",
+		FullMethod: "/headscale.v1.HeadscaleService/GetMachineRoute",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).RenameNamespace(ctx, req.(*RenameNamespaceRequest))
+		return srv.(HeadscaleServiceServer).GetMachineRoute(ctx, req.(*GetMachineRouteRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_DeleteNamespace_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteNamespaceRequest)
+func _HeadscaleService_EnableMachineRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableMachineRoutesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).DeleteNamespace(ctx, in)
+		return srv.(HeadscaleServiceServer).EnableMachineRoutes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/DeleteNamespace",
+		FullMethod: "/headscale.v1.HeadscaleService/EnableMachineRoutes",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).DeleteNamespace(ctx, req.(*DeleteNamespaceRequest))
+		return srv.(HeadscaleServiceServer).EnableMachineRoutes(ctx, req.(*EnableMachineRoutesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_ListNamespaces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListNamespacesRequest)
+func _HeadscaleService_ListExitNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListExitNodesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).ListNamespaces(ctx, in)
+		return srv.(HeadscaleServiceServer).ListExitNodes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/ListNamespaces",
+		FullMethod: "/headscale.v1.HeadscaleService/ListExitNodes",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).ListNamespaces(ctx, req.(*ListNamespacesRequest))
+		return srv.(HeadscaleServiceServer).ListExitNodes(ctx, req.(*ListExitNodesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_CreatePreAuthKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreatePreAuthKeyRequest)
+func _HeadscaleService_CreateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateApiKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).CreatePreAuthKey(ctx, in)
+		return srv.(HeadscaleServiceServer).CreateApiKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/CreatePreAuthKey",
+		FullMethod: "/headscale.v1.HeadscaleService/CreateApiKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).CreatePreAuthKey(ctx, req.(*CreatePreAuthKeyRequest))
+		return srv.(HeadscaleServiceServer).CreateApiKey(ctx, req.(*CreateApiKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_ExpirePreAuthKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ExpirePreAuthKeyRequest)
+func _HeadscaleService_ExpireApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpireApiKeyRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).ExpirePreAuthKey(ctx, in)
+		return srv.(HeadscaleServiceServer).ExpireApiKey(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/ExpirePreAuthKey",
+		FullMethod: "/headscale.v1.HeadscaleService/ExpireApiKey",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).ExpirePreAuthKey(ctx, req.(*ExpirePreAuthKeyRequest))
+		return srv.(HeadscaleServiceServer).ExpireApiKey(ctx, req.(*ExpireApiKeyRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_ListPreAuthKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPreAuthKeysRequest)
+func _HeadscaleService_ListApiKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListApiKeysRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).ListPreAuthKeys(ctx, in)
+		return srv.(HeadscaleServiceServer).ListApiKeys(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/ListPreAuthKeys",
+		FullMethod: "/headscale.v1.HeadscaleService/ListApiKeys",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).ListPreAuthKeys(ctx, req.(*ListPreAuthKeysRequest))
+		return srv.(HeadscaleServiceServer).ListApiKeys(ctx, req.(*ListApiKeysRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_DebugCreateMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DebugCreateMachineRequest)
+func _HeadscaleService_ListACLReferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListACLReferencesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).DebugCreateMachine(ctx, in)
+		return srv.(HeadscaleServiceServer).ListACLReferences(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/DebugCreateMachine",
+		FullMethod: "/headscale.v1.HeadscaleService/ListACLReferences",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).DebugCreateMachine(ctx, req.(*DebugCreateMachineRequest))
+		return srv.(HeadscaleServiceServer).ListACLReferences(ctx, req.(*ListACLReferencesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_GetMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetMachineRequest)
+func _HeadscaleService_CheckACL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckACLRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).GetMachine(ctx, in)
+		return srv.(HeadscaleServiceServer).CheckACL(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/GetMachine",
+		FullMethod: "/headscale.v1.HeadscaleService/CheckACL",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).GetMachine(ctx, req.(*GetMachineRequest))
+		return srv.(HeadscaleServiceServer).CheckACL(ctx, req.(*CheckACLRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_SetTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SetTagsRequest)
+func _HeadscaleService_ListACLGroups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListACLGroupsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).SetTags(ctx, in)
+		return srv.(HeadscaleServiceServer).ListACLGroups(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/SetTags",
+		FullMethod: "/headscale.v1.HeadscaleService/ListACLGroups",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).SetTags(ctx, req.(*SetTagsRequest))
+		return srv.(HeadscaleServiceServer).ListACLGroups(ctx, req.(*ListACLGroupsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_RegisterMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RegisterMachineRequest)
+func _HeadscaleService_GetACLGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetACLGroupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).RegisterMachine(ctx, in)
+		return srv.(HeadscaleServiceServer).GetACLGroup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/RegisterMachine",
+		FullMethod: "/headscale.v1.HeadscaleService/GetACLGroup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).RegisterMachine(ctx, req.(*RegisterMachineRequest))
+		return srv.(HeadscaleServiceServer).GetACLGroup(ctx, req.(*GetACLGroupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_DeleteMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteMachineRequest)
+func _HeadscaleService_ListDNSRecords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDNSRecordsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).DeleteMachine(ctx, in)
+		return srv.(HeadscaleServiceServer).ListDNSRecords(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/DeleteMachine",
+		FullMethod: "/headscale.v1.HeadscaleService/ListDNSRecords",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).DeleteMachine(ctx, req.(*DeleteMachineRequest))
+		return srv.(HeadscaleServiceServer).ListDNSRecords(ctx, req.(*ListDNSRecordsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_ExpireMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ExpireMachineRequest)
+func _HeadscaleService_GetNodeStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNodeStatsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).ExpireMachine(ctx, in)
+		return srv.(HeadscaleServiceServer).GetNodeStats(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/ExpireMachine",
+		FullMethod: "/headscale.v1.HeadscaleService/GetNodeStats",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).ExpireMachine(ctx, req.(*ExpireMachineRequest))
+		return srv.(HeadscaleServiceServer).GetNodeStats(ctx, req.(*GetNodeStatsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_RenameMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RenameMachineRequest)
+func _HeadscaleService_CreateNodeTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNodeTemplateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).RenameMachine(ctx, in)
+		return srv.(HeadscaleServiceServer).CreateNodeTemplate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/RenameMachine",
+		FullMethod: "/headscale.v1.HeadscaleService/CreateNodeTemplate",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).RenameMachine(ctx, req.(*RenameMachineRequest))
+		return srv.(HeadscaleServiceServer).CreateNodeTemplate(ctx, req.(*CreateNodeTemplateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_ListMachines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListMachinesRequest)
+func _HeadscaleService_ListNodeTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodeTemplatesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).ListMachines(ctx, in)
+		return srv.(HeadscaleServiceServer).ListNodeTemplates(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/ListMachines",
+		FullMethod: "/headscale.v1.HeadscaleService/ListNodeTemplates",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).ListMachines(ctx, req.(*ListMachinesRequest))
+		return srv.(HeadscaleServiceServer).ListNodeTemplates(ctx, req.(*ListNodeTemplatesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_MoveMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MoveMachineRequest)
+func _HeadscaleService_DeleteNodeTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNodeTemplateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).MoveMachine(ctx, in)
+		return srv.(HeadscaleServiceServer).DeleteNodeTemplate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/MoveMachine",
+		FullMethod: "/headscale.v1.HeadscaleService/DeleteNodeTemplate",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).MoveMachine(ctx, req.(*MoveMachineRequest))
+		return srv.(HeadscaleServiceServer).DeleteNodeTemplate(ctx, req.(*DeleteNodeTemplateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_GetMachineRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetMachineRouteRequest)
+func _HeadscaleService_SetAutoTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAutoTagRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).GetMachineRoute(ctx, in)
+		return srv.(HeadscaleServiceServer).SetAutoTag(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/GetMachineRoute",
+		FullMethod: "/headscale.v1.HeadscaleService/SetAutoTag",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).GetMachineRoute(ctx, req.(*GetMachineRouteRequest))
+		return srv.(HeadscaleServiceServer).SetAutoTag(ctx, req.(*SetAutoTagRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_EnableMachineRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EnableMachineRoutesRequest)
+func _HeadscaleService_DeleteAutoTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAutoTagRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).EnableMachineRoutes(ctx, in)
+		return srv.(HeadscaleServiceServer).DeleteAutoTag(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/EnableMachineRoutes",
+		FullMethod: "/headscale.v1.HeadscaleService/DeleteAutoTag",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).EnableMachineRoutes(ctx, req.(*EnableMachineRoutesRequest))
+		return srv.(HeadscaleServiceServer).DeleteAutoTag(ctx, req.(*DeleteAutoTagRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_CreateApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateApiKeyRequest)
+func _HeadscaleService_ListAutoTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAutoTagsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).CreateApiKey(ctx, in)
+		return srv.(HeadscaleServiceServer).ListAutoTags(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/CreateApiKey",
+		FullMethod: "/headscale.v1.HeadscaleService/ListAutoTags",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).CreateApiKey(ctx, req.(*CreateApiKeyRequest))
+		return srv.(HeadscaleServiceServer).ListAutoTags(ctx, req.(*ListAutoTagsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_ExpireApiKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ExpireApiKeyRequest)
+func _HeadscaleService_ApplyAutoTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyAutoTagsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).ExpireApiKey(ctx, in)
+		return srv.(HeadscaleServiceServer).ApplyAutoTags(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/ExpireApiKey",
+		FullMethod: "/headscale.v1.HeadscaleService/ApplyAutoTags",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).ExpireApiKey(ctx, req.(*ExpireApiKeyRequest))
+		return srv.(HeadscaleServiceServer).ApplyAutoTags(ctx, req.(*ApplyAutoTagsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _HeadscaleService_ListApiKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListApiKeysRequest)
+func _HeadscaleService_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(HeadscaleServiceServer).ListApiKeys(ctx, in)
+		return srv.(HeadscaleServiceServer).GetVersion(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/headscale.v1.HeadscaleService/ListApiKeys",
+		FullMethod: "/headscale.v1.HeadscaleService/GetVersion",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(HeadscaleServiceServer).ListApiKeys(ctx, req.(*ListApiKeysRequest))
+		return srv.(HeadscaleServiceServer).GetVersion(ctx, req.(*GetVersionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -785,6 +1949,22 @@ var HeadscaleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RenameNamespace",
 			Handler:    _HeadscaleService_RenameNamespace_Handler,
 		},
+		{
+			MethodName: "SetNamespaceDNSDomain",
+			Handler:    _HeadscaleService_SetNamespaceDNSDomain_Handler,
+		},
+		{
+			MethodName: "SetNamespaceIdleExpiry",
+			Handler:    _HeadscaleService_SetNamespaceIdleExpiry_Handler,
+		},
+		{
+			MethodName: "SetNamespaceMTU",
+			Handler:    _HeadscaleService_SetNamespaceMTU_Handler,
+		},
+		{
+			MethodName: "SetNamespaceLocked",
+			Handler:    _HeadscaleService_SetNamespaceLocked_Handler,
+		},
 		{
 			MethodName: "DeleteNamespace",
 			Handler:    _HeadscaleService_DeleteNamespace_Handler,
@@ -793,6 +1973,10 @@ var HeadscaleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListNamespaces",
 			Handler:    _HeadscaleService_ListNamespaces_Handler,
 		},
+		{
+			MethodName: "GetNamespaceImpact",
+			Handler:    _HeadscaleService_GetNamespaceImpact_Handler,
+		},
 		{
 			MethodName: "CreatePreAuthKey",
 			Handler:    _HeadscaleService_CreatePreAuthKey_Handler,
@@ -829,6 +2013,66 @@ var HeadscaleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExpireMachine",
 			Handler:    _HeadscaleService_ExpireMachine_Handler,
 		},
+		{
+			MethodName: "SetMachineExpiry",
+			Handler:    _HeadscaleService_SetMachineExpiry_Handler,
+		},
+		{
+			MethodName: "DumpMachineNetmap",
+			Handler:    _HeadscaleService_DumpMachineNetmap_Handler,
+		},
+		{
+			MethodName: "RefreshMachineKey",
+			Handler:    _HeadscaleService_RefreshMachineKey_Handler,
+		},
+		{
+			MethodName: "BackfillMachineIPs",
+			Handler:    _HeadscaleService_BackfillMachineIPs_Handler,
+		},
+		{
+			MethodName: "ImportMachine",
+			Handler:    _HeadscaleService_ImportMachine_Handler,
+		},
+		{
+			MethodName: "QuarantineMachine",
+			Handler:    _HeadscaleService_QuarantineMachine_Handler,
+		},
+		{
+			MethodName: "UnquarantineMachine",
+			Handler:    _HeadscaleService_UnquarantineMachine_Handler,
+		},
+		{
+			MethodName: "ListPendingRegistrations",
+			Handler:    _HeadscaleService_ListPendingRegistrations_Handler,
+		},
+		{
+			MethodName: "ApproveRegistration",
+			Handler:    _HeadscaleService_ApproveRegistration_Handler,
+		},
+		{
+			MethodName: "ApplyMachine",
+			Handler:    _HeadscaleService_ApplyMachine_Handler,
+		},
+		{
+			MethodName: "UnmanageMachine",
+			Handler:    _HeadscaleService_UnmanageMachine_Handler,
+		},
+		{
+			MethodName: "RestoreMachine",
+			Handler:    _HeadscaleService_RestoreMachine_Handler,
+		},
+		{
+			MethodName: "SetDERPRegion",
+			Handler:    _HeadscaleService_SetDERPRegion_Handler,
+		},
+		{
+			MethodName: "SetExitNodeOnly",
+			Handler:    _HeadscaleService_SetExitNodeOnly_Handler,
+		},
+		{
+			MethodName: "TouchMachine",
+			Handler:    _HeadscaleService_TouchMachine_Handler,
+		},
 		{
 			MethodName: "RenameMachine",
 			Handler:    _HeadscaleService_RenameMachine_Handler,
@@ -841,6 +2085,10 @@ var HeadscaleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "MoveMachine",
 			Handler:    _HeadscaleService_MoveMachine_Handler,
 		},
+		{
+			MethodName: "ListMachineConnections",
+			Handler:    _HeadscaleService_ListMachineConnections_Handler,
+		},
 		{
 			MethodName: "GetMachineRoute",
 			Handler:    _HeadscaleService_GetMachineRoute_Handler,
@@ -849,6 +2097,10 @@ var HeadscaleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "EnableMachineRoutes",
 			Handler:    _HeadscaleService_EnableMachineRoutes_Handler,
 		},
+		{
+			MethodName: "ListExitNodes",
+			Handler:    _HeadscaleService_ListExitNodes_Handler,
+		},
 		{
 			MethodName: "CreateApiKey",
 			Handler:    _HeadscaleService_CreateApiKey_Handler,
@@ -861,6 +2113,62 @@ var HeadscaleService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListApiKeys",
 			Handler:    _HeadscaleService_ListApiKeys_Handler,
 		},
+		{
+			MethodName: "ListACLReferences",
+			Handler:    _HeadscaleService_ListACLReferences_Handler,
+		},
+		{
+			MethodName: "CheckACL",
+			Handler:    _HeadscaleService_CheckACL_Handler,
+		},
+		{
+			MethodName: "ListACLGroups",
+			Handler:    _HeadscaleService_ListACLGroups_Handler,
+		},
+		{
+			MethodName: "GetACLGroup",
+			Handler:    _HeadscaleService_GetACLGroup_Handler,
+		},
+		{
+			MethodName: "ListDNSRecords",
+			Handler:    _HeadscaleService_ListDNSRecords_Handler,
+		},
+		{
+			MethodName: "GetNodeStats",
+			Handler:    _HeadscaleService_GetNodeStats_Handler,
+		},
+		{
+			MethodName: "CreateNodeTemplate",
+			Handler:    _HeadscaleService_CreateNodeTemplate_Handler,
+		},
+		{
+			MethodName: "ListNodeTemplates",
+			Handler:    _HeadscaleService_ListNodeTemplates_Handler,
+		},
+		{
+			MethodName: "DeleteNodeTemplate",
+			Handler:    _HeadscaleService_DeleteNodeTemplate_Handler,
+		},
+		{
+			MethodName: "SetAutoTag",
+			Handler:    _HeadscaleService_SetAutoTag_Handler,
+		},
+		{
+			MethodName: "DeleteAutoTag",
+			Handler:    _HeadscaleService_DeleteAutoTag_Handler,
+		},
+		{
+			MethodName: "ListAutoTags",
+			Handler:    _HeadscaleService_ListAutoTags_Handler,
+		},
+		{
+			MethodName: "ApplyAutoTags",
+			Handler:    _HeadscaleService_ApplyAutoTags_Handler,
+		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _HeadscaleService_GetVersion_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "headscale/v1/headscale.proto",
@@ -3,6 +3,9 @@ package headscale
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"sort"
 	"strings"
 	"time"
 
@@ -10,6 +13,8 @@ import (
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+	"inet.af/netaddr"
 	"tailscale.com/tailcfg"
 )
 
@@ -65,6 +70,66 @@ func (api headscaleV1APIServer) RenameNamespace(
 	return &v1.RenameNamespaceResponse{Namespace: namespace.toProto()}, nil
 }
 
+func (api headscaleV1APIServer) SetNamespaceDNSDomain(
+	ctx context.Context,
+	request *v1.SetNamespaceDNSDomainRequest,
+) (*v1.SetNamespaceDNSDomainResponse, error) {
+	namespace, err := api.h.SetNamespaceDNSDomain(
+		request.GetName(),
+		request.GetDnsDomain(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.SetNamespaceDNSDomainResponse{Namespace: namespace.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) SetNamespaceIdleExpiry(
+	ctx context.Context,
+	request *v1.SetNamespaceIdleExpiryRequest,
+) (*v1.SetNamespaceIdleExpiryResponse, error) {
+	namespace, err := api.h.SetNamespaceIdleExpiry(
+		request.GetName(),
+		request.GetIdleExpiry().AsDuration(),
+	)
+	if errors.Is(err, errInvalidIdleExpiry) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.SetNamespaceIdleExpiryResponse{Namespace: namespace.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) SetNamespaceMTU(
+	ctx context.Context,
+	request *v1.SetNamespaceMTURequest,
+) (*v1.SetNamespaceMTUResponse, error) {
+	namespace, err := api.h.SetNamespaceMTU(request.GetName(), int(request.GetMtu()))
+	if errors.Is(err, errInvalidMTU) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.SetNamespaceMTUResponse{Namespace: namespace.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) SetNamespaceLocked(
+	ctx context.Context,
+	request *v1.SetNamespaceLockedRequest,
+) (*v1.SetNamespaceLockedResponse, error) {
+	namespace, err := api.h.SetNamespaceLocked(request.GetName(), request.GetLocked())
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.SetNamespaceLockedResponse{Namespace: namespace.toProto()}, nil
+}
+
 func (api headscaleV1APIServer) DeleteNamespace(
 	ctx context.Context,
 	request *v1.DeleteNamespaceRequest,
@@ -96,6 +161,18 @@ func (api headscaleV1APIServer) ListNamespaces(
 	return &v1.ListNamespacesResponse{Namespaces: response}, nil
 }
 
+func (api headscaleV1APIServer) GetNamespaceImpact(
+	ctx context.Context,
+	request *v1.GetNamespaceImpactRequest,
+) (*v1.GetNamespaceImpactResponse, error) {
+	impact, err := api.h.GetNamespaceImpact(request.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	return impact.toProto(), nil
+}
+
 func (api headscaleV1APIServer) CreatePreAuthKey(
 	ctx context.Context,
 	request *v1.CreatePreAuthKeyRequest,
@@ -161,16 +238,102 @@ func (api headscaleV1APIServer) RegisterMachine(
 		Str("machine_key", request.GetKey()).
 		Msg("Registering machine")
 
-	machine, err := api.h.RegisterMachineFromAuthCallback(
+	overrides := NodeTemplate{
+		Tags:   request.GetTags(),
+		Routes: request.GetRoutes(),
+		Labels: request.GetLabels(),
+	}
+	if request.GetExpiry() != nil {
+		overrides.Expiry = request.GetExpiry().AsDuration()
+	}
+
+	ipAddresses := MachineAddresses{}
+	for _, address := range request.GetIpAddresses() {
+		ip, err := netaddr.ParseIP(address)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		ipAddresses = append(ipAddresses, ip)
+	}
+
+	namespaceName := request.GetNamespace()
+	var pak *PreAuthKey
+	if request.GetPreAuthKey() != "" {
+		var err error
+		pak, err = api.h.RegistrationPreAuthKey(request.GetPreAuthKey(), namespaceName)
+		switch {
+		case errors.Is(err, errPreAuthKeyNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, errPreAuthKeyNamespaceConflict):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, errPreAuthKeyExpired),
+			errors.Is(err, errSingleUseAuthKeyHasBeenUsed):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case err != nil:
+			return nil, err
+		}
+		namespaceName = pak.Namespace.Name
+	}
+
+	register := api.h.RegisterMachineWithTemplate
+	if request.GetDryRun() {
+		register = api.h.PlanMachineRegistration
+	}
+
+	machine, err := register(
 		request.GetKey(),
-		request.GetNamespace(),
-		RegisterMethodCLI,
+		namespaceName,
+		request.GetTemplate(),
+		overrides,
+		ipAddresses,
 	)
-	if err != nil {
+	switch {
+	case errors.Is(err, errInvalidNodeTemplate),
+		errors.Is(err, errInvalidMachineKey),
+		errors.Is(err, errIPNotInPrefixes):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errIPAlreadyAllocated):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, errNodeTemplateNotFound),
+		errors.Is(err, errMachineNotFoundRegistrationCache),
+		errors.Is(err, errNamespaceNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errNamespaceLocked):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
 		return nil, err
 	}
 
-	return &v1.RegisterMachineResponse{Machine: machine.toProto()}, nil
+	if pak != nil {
+		if request.GetDryRun() {
+			machine.AuthKeyID = uint(pak.ID)
+			machine.AuthKey = pak
+		} else if err := api.h.UsePreAuthKeyForMachine(machine, pak); err != nil {
+			// The key was not consumed, the machine must not stay
+			// registered without it.
+			if deleteErr := api.h.HardDeleteMachine(machine); deleteErr != nil {
+				log.Error().
+					Caller().
+					Err(deleteErr).
+					Str("machine", machine.Hostname).
+					Msg("Failed to delete the machine registered with an unusable AuthKey")
+			}
+			if errors.Is(err, errSingleUseAuthKeyHasBeenUsed) {
+				return nil, status.Error(codes.FailedPrecondition, err.Error())
+			}
+
+			return nil, err
+		}
+	}
+
+	response := &v1.RegisterMachineResponse{Machine: machine.toProto()}
+	if request.GetDryRun() {
+		for _, route := range machine.GetEnabledRoutes() {
+			response.EnabledRoutes = append(response.EnabledRoutes, route.String())
+		}
+	}
+
+	return response, nil
 }
 
 func (api headscaleV1APIServer) GetMachine(
@@ -178,11 +341,35 @@ func (api headscaleV1APIServer) GetMachine(
 	request *v1.GetMachineRequest,
 ) (*v1.GetMachineResponse, error) {
 	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &v1.GetMachineResponse{Machine: machine.toProto()}, nil
+	return &v1.GetMachineResponse{Machine: api.enrichMachineProto(machine)}, nil
+}
+
+// enrichMachineProto is the machine in the API with the fields which are
+// not stored with it: its tags validated by the ACL policy, its flaps,
+// the state of its netmap, its clock skew and the code of its DERP region.
+func (api headscaleV1APIServer) enrichMachineProto(machine *Machine) *v1.Machine {
+	machineProto := machine.toProto()
+	machineProto.ValidTags, machineProto.InvalidTags = getTags(
+		api.h.aclPolicy,
+		*machine,
+		api.h.cfg.OIDC.StripEmaildomain,
+	)
+	machineProto.FlapCount = api.h.machineFlapCount(machine)
+	machineProto.PreferredDerpRegionCode = derpRegionCode(
+		api.h.DERPMap,
+		int(machineProto.GetPreferredDerpRegion()),
+	)
+	machineProto.NetmapUpToDate = api.h.machineNetmapUpToDate(machine)
+	machineProto.ClockSkewed = api.h.isClockSkewed(machine.ClockSkew)
+
+	return machineProto
 }
 
 func (api headscaleV1APIServer) SetTags(
@@ -190,6 +377,9 @@ func (api headscaleV1APIServer) SetTags(
 	request *v1.SetTagsRequest,
 ) (*v1.SetTagsResponse, error) {
 	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -217,7 +407,14 @@ func (api headscaleV1APIServer) SetTags(
 		Strs("tags", request.GetTags()).
 		Msg("Changing tags of machine")
 
-	return &v1.SetTagsResponse{Machine: machine.toProto()}, nil
+	machineProto := machine.toProto()
+	machineProto.ValidTags, machineProto.InvalidTags = getTags(
+		api.h.aclPolicy,
+		*machine,
+		api.h.cfg.OIDC.StripEmaildomain,
+	)
+
+	return &v1.SetTagsResponse{Machine: machineProto}, nil
 }
 
 func (api headscaleV1APIServer) DeleteMachine(
@@ -225,6 +422,9 @@ func (api headscaleV1APIServer) DeleteMachine(
 	request *v1.DeleteMachineRequest,
 ) (*v1.DeleteMachineResponse, error) {
 	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -244,6 +444,9 @@ func (api headscaleV1APIServer) ExpireMachine(
 	request *v1.ExpireMachineRequest,
 ) (*v1.ExpireMachineResponse, error) {
 	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -260,6 +463,425 @@ func (api headscaleV1APIServer) ExpireMachine(
 	return &v1.ExpireMachineResponse{Machine: machine.toProto()}, nil
 }
 
+func (api headscaleV1APIServer) SetMachineExpiry(
+	ctx context.Context,
+	request *v1.SetMachineExpiryRequest,
+) (*v1.SetMachineExpiryResponse, error) {
+	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var expiry *time.Time
+	if request.GetExpiry() != nil {
+		expiryTime := request.GetExpiry().AsTime()
+		expiry = &expiryTime
+	}
+
+	if err := api.h.SetMachineExpiry(machine, expiry); err != nil {
+		return nil, err
+	}
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Interface("expiry", expiry).
+		Msg("Setting the expiry of machine")
+
+	return &v1.SetMachineExpiryResponse{Machine: machine.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) DumpMachineNetmap(
+	ctx context.Context,
+	request *v1.DumpMachineNetmapRequest,
+) (*v1.DumpMachineNetmapResponse, error) {
+	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	mapResponse, err := api.h.generateMapResponse(machine)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := json.Marshal(mapResponse.Node)
+	if err != nil {
+		return nil, err
+	}
+	peers, err := json.Marshal(mapResponse.Peers)
+	if err != nil {
+		return nil, err
+	}
+	packetFilter, err := json.Marshal(mapResponse.PacketFilter)
+	if err != nil {
+		return nil, err
+	}
+	dnsConfig, err := json.Marshal(mapResponse.DNSConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Msg("Dumping the netmap of machine")
+
+	return &v1.DumpMachineNetmapResponse{
+		Node:         string(node),
+		Peers:        string(peers),
+		PacketFilter: string(packetFilter),
+		DnsConfig:    string(dnsConfig),
+	}, nil
+}
+
+func (api headscaleV1APIServer) RefreshMachineKey(
+	ctx context.Context,
+	request *v1.RefreshMachineKeyRequest,
+) (*v1.RefreshMachineKeyResponse, error) {
+	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := api.h.RequestMachineKeyRefresh(machine); err != nil {
+		return nil, err
+	}
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Msg("Requested the key refresh of machine")
+
+	return &v1.RefreshMachineKeyResponse{Machine: machine.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) BackfillMachineIPs(
+	ctx context.Context,
+	request *v1.BackfillMachineIPsRequest,
+) (*v1.BackfillMachineIPsResponse, error) {
+	assignments, err := api.h.BackfillMachineIPs(request.GetDryRun())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.MachineIPAssignment, len(assignments))
+	for index, assignment := range assignments {
+		response[index] = assignment.toProto()
+	}
+
+	return &v1.BackfillMachineIPsResponse{Assignments: response}, nil
+}
+
+func (api headscaleV1APIServer) QuarantineMachine(
+	ctx context.Context,
+	request *v1.QuarantineMachineRequest,
+) (*v1.QuarantineMachineResponse, error) {
+	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.h.QuarantineMachine(machine)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Msg("machine quarantined")
+
+	return &v1.QuarantineMachineResponse{Machine: machine.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) UnquarantineMachine(
+	ctx context.Context,
+	request *v1.UnquarantineMachineRequest,
+) (*v1.UnquarantineMachineResponse, error) {
+	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.h.UnquarantineMachine(machine)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Msg("machine unquarantined")
+
+	return &v1.UnquarantineMachineResponse{Machine: machine.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) ListPendingRegistrations(
+	ctx context.Context,
+	request *v1.ListPendingRegistrationsRequest,
+) (*v1.ListPendingRegistrationsResponse, error) {
+	registrations := api.h.ListPendingRegistrations()
+
+	response := make([]*v1.PendingRegistration, len(registrations))
+	for index, registration := range registrations {
+		response[index] = registration.toProto()
+	}
+
+	return &v1.ListPendingRegistrationsResponse{Registrations: response}, nil
+}
+
+func (api headscaleV1APIServer) ApproveRegistration(
+	ctx context.Context,
+	request *v1.ApproveRegistrationRequest,
+) (*v1.ApproveRegistrationResponse, error) {
+	machine, err := api.h.ApproveRegistration(request.GetMachineKey())
+	if errors.Is(err, errPendingRegistrationNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Msg("machine registration approved")
+
+	return &v1.ApproveRegistrationResponse{Machine: machine.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) UnmanageMachine(
+	ctx context.Context,
+	request *v1.UnmanageMachineRequest,
+) (*v1.UnmanageMachineResponse, error) {
+	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.h.UnmanageMachine(machine)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.UnmanageMachineResponse{Machine: machine.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) ApplyMachine(
+	ctx context.Context,
+	request *v1.ApplyMachineRequest,
+) (*v1.ApplyMachineResponse, error) {
+	machine, result, changes, err := api.h.ApplyMachineSpec(
+		machineSpecFromProto(request.GetSpec()),
+		request.GetDryRun(),
+	)
+	switch {
+	case errors.Is(err, errInvalidMachineSpec),
+		errors.Is(err, errMachineRouteIsNotAvailable):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errMachineNotFoundRegistrationCache),
+		errors.Is(err, errNamespaceNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	response := &v1.ApplyMachineResponse{
+		Result:  result,
+		Changes: changes,
+	}
+	if machine != nil {
+		response.Machine = machine.toProto()
+	}
+
+	return response, nil
+}
+
+func (api headscaleV1APIServer) RestoreMachine(
+	ctx context.Context,
+	request *v1.RestoreMachineRequest,
+) (*v1.RestoreMachineResponse, error) {
+	machine, ipAddressesChanged, err := api.h.RestoreMachine(request.GetMachineId())
+	switch {
+	case errors.Is(err, errMachineNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errMachineNotDeleted),
+		errors.Is(err, errMachineNotRestorable),
+		errors.Is(err, errMachineKeyInUse),
+		errors.Is(err, errNamespaceNotFound):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, errMachineNameInUse):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Bool("ip_addresses_changed", ipAddressesChanged).
+		Msg("machine restored")
+
+	return &v1.RestoreMachineResponse{
+		Machine:            machine.toProto(),
+		IpAddressesChanged: ipAddressesChanged,
+	}, nil
+}
+
+func (api headscaleV1APIServer) ImportMachine(
+	ctx context.Context,
+	request *v1.ImportMachineRequest,
+) (*v1.ImportMachineResponse, error) {
+	ipAddresses := MachineAddresses{}
+	for _, address := range request.GetIpAddresses() {
+		ip, err := netaddr.ParseIP(address)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		ipAddresses = append(ipAddresses, ip)
+	}
+
+	routes, err := stringToIPPrefix(request.GetEnabledRoutes())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	newMachine := Machine{
+		MachineKey:    request.GetMachineKey(),
+		NodeKey:       request.GetNodeKey(),
+		DiscoKey:      request.GetDiscoKey(),
+		Hostname:      request.GetName(),
+		GivenName:     request.GetGivenName(),
+		IPAddresses:   ipAddresses,
+		ForcedTags:    request.GetForcedTags(),
+		EnabledRoutes: routes,
+	}
+	if request.GetExpiry() != nil {
+		expiry := request.GetExpiry().AsTime()
+		newMachine.Expiry = &expiry
+	}
+
+	machine, ipAddressesChanged, err := api.h.ImportMachine(newMachine, request.GetNamespace())
+	switch {
+	case errors.Is(err, errInvalidMachineKey),
+		errors.Is(err, errInvalidDNSLabel):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errNamespaceNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errMachineKeyInUse),
+		errors.Is(err, errMachineNameInUse):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Bool("ip_addresses_changed", ipAddressesChanged).
+		Msg("machine imported")
+
+	return &v1.ImportMachineResponse{
+		Machine:            machine.toProto(),
+		IpAddressesChanged: ipAddressesChanged,
+	}, nil
+}
+
+func (api headscaleV1APIServer) SetDERPRegion(
+	ctx context.Context,
+	request *v1.SetDERPRegionRequest,
+) (*v1.SetDERPRegionResponse, error) {
+	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.h.SetMachineDERPRegion(machine, int(request.GetRegionId()))
+	if errors.Is(err, errDERPRegionNotFound) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Int("region", machine.ForcedDERPRegion).
+		Msg("machine DERP region forced")
+
+	return &v1.SetDERPRegionResponse{Machine: machine.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) SetExitNodeOnly(
+	ctx context.Context,
+	request *v1.SetExitNodeOnlyRequest,
+) (*v1.SetExitNodeOnlyResponse, error) {
+	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = api.h.SetMachineExitNodeOnly(machine, request.GetExitNodeOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Bool("exit_node_only", machine.ExitNodeOnly).
+		Msg("machine exit node only updated")
+
+	return &v1.SetExitNodeOnlyResponse{Machine: machine.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) TouchMachine(
+	ctx context.Context,
+	request *v1.TouchMachineRequest,
+) (*v1.TouchMachineResponse, error) {
+	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	machine.LastSeen = &now
+
+	err = api.h.TouchMachine(machine)
+	if err != nil {
+		return nil, err
+	}
+
+	// The online status of the machine is part of the netmap of its peers.
+	api.h.setLastStateChangeToNow(machine.Namespace.Name)
+
+	log.Trace().
+		Str("machine", machine.Hostname).
+		Time("last_seen", now).
+		Msg("machine touched")
+
+	return &v1.TouchMachineResponse{Machine: machine.toProto()}, nil
+}
+
 func (api headscaleV1APIServer) RenameMachine(
 	ctx context.Context,
 	request *v1.RenameMachineRequest,
@@ -273,7 +895,12 @@ func (api headscaleV1APIServer) RenameMachine(
 		machine,
 		request.GetNewName(),
 	)
-	if err != nil {
+	switch {
+	case errors.Is(err, errInvalidDNSLabel):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errMachineNameInUse):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
 		return nil, err
 	}
 
@@ -289,39 +916,48 @@ func (api headscaleV1APIServer) ListMachines(
 	ctx context.Context,
 	request *v1.ListMachinesRequest,
 ) (*v1.ListMachinesResponse, error) {
-	if request.GetNamespace() != "" {
-		machines, err := api.h.ListMachinesInNamespace(request.GetNamespace())
+	var selector map[string]string
+	if request.GetLabelSelector() != "" {
+		var err error
+		selector, err = ParseLabelSelector(request.GetLabelSelector())
 		if err != nil {
-			return nil, err
+			return nil, status.Error(codes.InvalidArgument, err.Error())
 		}
+	}
 
-		response := make([]*v1.Machine, len(machines))
-		for index, machine := range machines {
-			response[index] = machine.toProto()
+	list := api.h.ListMachines
+	switch {
+	case request.GetDeleted() && request.GetNamespace() != "":
+		list = func(scopes ...func(*gorm.DB) *gorm.DB) ([]Machine, error) {
+			return api.h.ListDeletedMachinesInNamespace(request.GetNamespace(), scopes...)
+		}
+	case request.GetDeleted():
+		list = api.h.ListDeletedMachines
+	case request.GetNamespace() != "":
+		list = func(scopes ...func(*gorm.DB) *gorm.DB) ([]Machine, error) {
+			return api.h.ListMachinesInNamespace(request.GetNamespace(), scopes...)
 		}
-
-		return &v1.ListMachinesResponse{Machines: response}, nil
 	}
 
-	machines, err := api.h.ListMachines()
+	machines, nextPage, err := listMachinesMatchingLabels(
+		list,
+		selector,
+		request.GetPageToken(),
+		int(request.GetPageSize()),
+	)
+	if errors.Is(err, errInvalidPageToken) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	response := make([]*v1.Machine, len(machines))
-	for index, machine := range machines {
-		m := machine.toProto()
-		validTags, invalidTags := getTags(
-			api.h.aclPolicy,
-			machine,
-			api.h.cfg.OIDC.StripEmaildomain,
-		)
-		m.InvalidTags = invalidTags
-		m.ValidTags = validTags
-		response[index] = m
+	for index := range machines {
+		response[index] = api.enrichMachineProto(&machines[index])
 	}
 
-	return &v1.ListMachinesResponse{Machines: response}, nil
+	return &v1.ListMachinesResponse{Machines: response, NextPageToken: nextPage}, nil
 }
 
 func (api headscaleV1APIServer) MoveMachine(
@@ -329,6 +965,9 @@ func (api headscaleV1APIServer) MoveMachine(
 	request *v1.MoveMachineRequest,
 ) (*v1.MoveMachineResponse, error) {
 	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -346,6 +985,9 @@ func (api headscaleV1APIServer) GetMachineRoute(
 	request *v1.GetMachineRouteRequest,
 ) (*v1.GetMachineRouteResponse, error) {
 	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -360,6 +1002,9 @@ func (api headscaleV1APIServer) EnableMachineRoutes(
 	request *v1.EnableMachineRoutesRequest,
 ) (*v1.EnableMachineRoutesResponse, error) {
 	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, status.Error(codes.NotFound, errMachineNotFound.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -374,6 +1019,29 @@ func (api headscaleV1APIServer) EnableMachineRoutes(
 	}, nil
 }
 
+func (api headscaleV1APIServer) ListExitNodes(
+	ctx context.Context,
+	request *v1.ListExitNodesRequest,
+) (*v1.ListExitNodesResponse, error) {
+	exitNodes, err := api.h.ListExitNodes(request.GetNamespace())
+	if errors.Is(err, errNamespaceNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.ExitNode, len(exitNodes))
+	for index, exitNode := range exitNodes {
+		response[index] = &v1.ExitNode{
+			Machine:  exitNode.Machine.toProto(),
+			Approved: exitNode.Approved,
+		}
+	}
+
+	return &v1.ListExitNodesResponse{ExitNodes: response}, nil
+}
+
 func (api headscaleV1APIServer) CreateApiKey(
 	ctx context.Context,
 	request *v1.CreateApiKeyRequest,
@@ -383,9 +1051,18 @@ func (api headscaleV1APIServer) CreateApiKey(
 		expiration = request.GetExpiration().AsTime()
 	}
 
-	apiKey, _, err := api.h.CreateAPIKey(
+	scope := request.GetScope()
+	if scope == "" {
+		scope = APIKeyScopeFull
+	}
+
+	apiKey, _, err := api.h.CreateAPIKeyWithScope(
 		&expiration,
+		scope,
 	)
+	if errors.Is(err, errInvalidAPIKeyScope) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -430,6 +1107,142 @@ func (api headscaleV1APIServer) ListApiKeys(
 	return &v1.ListApiKeysResponse{ApiKeys: response}, nil
 }
 
+func (api headscaleV1APIServer) ListACLReferences(
+	ctx context.Context,
+	request *v1.ListACLReferencesRequest,
+) (*v1.ListACLReferencesResponse, error) {
+	if (request.GetTag() == "") == (request.GetMachineId() == 0) {
+		return nil, status.Error(
+			codes.InvalidArgument,
+			"Exactly one of tag or machine ID must be given",
+		)
+	}
+
+	var references []ACLReference
+	var err error
+	if request.GetTag() != "" {
+		references, err = api.h.ListACLReferencesForTag(request.GetTag())
+	} else {
+		var machine *Machine
+		machine, err = api.h.GetMachineByID(request.GetMachineId())
+		if err != nil {
+			return nil, err
+		}
+
+		references, err = api.h.ListACLReferencesForMachine(machine)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.ACLReference, len(references))
+	for index, reference := range references {
+		response[index] = reference.toProto()
+	}
+
+	return &v1.ListACLReferencesResponse{References: response}, nil
+}
+
+func (api headscaleV1APIServer) CheckACL(
+	ctx context.Context,
+	request *v1.CheckACLRequest,
+) (*v1.CheckACLResponse, error) {
+	if request.GetPort() > portRangeEnd {
+		return nil, status.Error(codes.InvalidArgument, "Invalid port")
+	}
+
+	src, err := api.h.GetMachineByID(request.GetSourceMachineId())
+	if err != nil {
+		return nil, err
+	}
+
+	dst, err := api.h.GetMachineByID(request.GetDestinationMachineId())
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := api.h.CheckACL(src, dst, uint16(request.GetPort()))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &v1.CheckACLResponse{
+		Allowed: result.Allowed,
+		Reason:  result.Reason,
+	}
+	if request.GetTrace() {
+		for _, rule := range result.Rules {
+			response.Rules = append(response.Rules, rule.toProto())
+		}
+	}
+
+	return response, nil
+}
+
+func (api headscaleV1APIServer) ListACLGroups(
+	ctx context.Context,
+	request *v1.ListACLGroupsRequest,
+) (*v1.ListACLGroupsResponse, error) {
+	groups, err := api.h.ListACLGroups()
+	switch {
+	case errors.Is(err, errEmptyPolicy):
+		return nil, status.Error(codes.FailedPrecondition, "No ACL policy is loaded")
+	case err != nil:
+		return nil, err
+	}
+
+	response := make([]*v1.ACLGroup, len(groups))
+	for index, group := range groups {
+		response[index] = group.toProto()
+	}
+
+	return &v1.ListACLGroupsResponse{Groups: response}, nil
+}
+
+func (api headscaleV1APIServer) GetACLGroup(
+	ctx context.Context,
+	request *v1.GetACLGroupRequest,
+) (*v1.GetACLGroupResponse, error) {
+	group, err := api.h.GetACLGroup(request.GetName())
+	switch {
+	case errors.Is(err, errEmptyPolicy):
+		return nil, status.Error(codes.FailedPrecondition, "No ACL policy is loaded")
+	case errors.Is(err, errACLGroupNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return &v1.GetACLGroupResponse{Group: group.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) ListMachineConnections(
+	ctx context.Context,
+	request *v1.ListMachineConnectionsRequest,
+) (*v1.ListMachineConnectionsResponse, error) {
+	machine, err := api.h.GetMachineByID(request.GetMachineId())
+	if err != nil {
+		return nil, err
+	}
+
+	since := time.Time{}
+	if request.GetSince() != nil {
+		since = request.GetSince().AsTime()
+	}
+
+	events, err := api.h.ListConnectionEvents(machine.ID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.ConnectionEvent, len(events))
+	for index, event := range events {
+		response[index] = event.toProto()
+	}
+
+	return &v1.ListMachineConnectionsResponse{Events: response}, nil
+}
+
 // The following service calls are for testing and debugging
 func (api headscaleV1APIServer) DebugCreateMachine(
 	ctx context.Context,
@@ -484,4 +1297,205 @@ func (api headscaleV1APIServer) DebugCreateMachine(
 	return &v1.DebugCreateMachineResponse{Machine: newMachine.toProto()}, nil
 }
 
+func (api headscaleV1APIServer) ListDNSRecords(
+	ctx context.Context,
+	request *v1.ListDNSRecordsRequest,
+) (*v1.ListDNSRecordsResponse, error) {
+	if request.GetNamespace() != "" {
+		if _, err := api.h.GetNamespace(request.GetNamespace()); err != nil {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+	}
+
+	records, err := api.h.ListDNSRecords(request.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.DNSRecord, len(records))
+	for index, record := range records {
+		response[index] = record.toProto()
+	}
+
+	return &v1.ListDNSRecordsResponse{
+		Records:  response,
+		MagicDns: api.h.cfg.DNSConfig != nil && api.h.cfg.DNSConfig.Proxied,
+	}, nil
+}
+
+func (api headscaleV1APIServer) GetVersion(
+	ctx context.Context,
+	request *v1.GetVersionRequest,
+) (*v1.GetVersionResponse, error) {
+	return &v1.GetVersionResponse{
+		Version:      api.h.Version,
+		Capabilities: Capabilities(),
+	}, nil
+}
+
+// Capabilities returns the names of the RPCs of the API, sorted. The CLI
+// compares the ones it was built with to the ones of the server.
+func Capabilities() []string {
+	methods := v1.HeadscaleService_ServiceDesc.Methods
+	capabilities := make([]string, 0, len(methods))
+	for _, method := range methods {
+		capabilities = append(capabilities, method.MethodName)
+	}
+	sort.Strings(capabilities)
+
+	return capabilities
+}
+
+func (api headscaleV1APIServer) GetNodeStats(
+	ctx context.Context,
+	request *v1.GetNodeStatsRequest,
+) (*v1.GetNodeStatsResponse, error) {
+	if request.GetSince() == nil || request.GetBucket() == nil {
+		return nil, status.Error(codes.InvalidArgument, "since and bucket are required")
+	}
+
+	buckets, err := api.h.GetNodeCountTrend(
+		request.GetSince().AsTime(),
+		request.GetBucket().AsDuration(),
+		request.GetNamespace(),
+		request.GetPerNamespace(),
+	)
+	if errors.Is(err, errInvalidStatsBucket) || errors.Is(err, errTooManyStatsBucket) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.NodeCountBucket, len(buckets))
+	for index, bucket := range buckets {
+		response[index] = bucket.toProto()
+	}
+
+	return &v1.GetNodeStatsResponse{Buckets: response}, nil
+}
+
+func (api headscaleV1APIServer) CreateNodeTemplate(
+	ctx context.Context,
+	request *v1.CreateNodeTemplateRequest,
+) (*v1.CreateNodeTemplateResponse, error) {
+	template := NodeTemplate{
+		Name:   request.GetName(),
+		Tags:   request.GetTags(),
+		Routes: request.GetRoutes(),
+		Labels: request.GetLabels(),
+	}
+	if request.GetExpiry() != nil {
+		template.Expiry = request.GetExpiry().AsDuration()
+	}
+
+	created, err := api.h.CreateNodeTemplate(template)
+	switch {
+	case errors.Is(err, errInvalidNodeTemplate):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errNodeTemplateExists):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return &v1.CreateNodeTemplateResponse{Template: created.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) ListNodeTemplates(
+	ctx context.Context,
+	request *v1.ListNodeTemplatesRequest,
+) (*v1.ListNodeTemplatesResponse, error) {
+	templates, err := api.h.ListNodeTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.NodeTemplate, len(templates))
+	for index, template := range templates {
+		response[index] = template.toProto()
+	}
+
+	return &v1.ListNodeTemplatesResponse{Templates: response}, nil
+}
+
+func (api headscaleV1APIServer) DeleteNodeTemplate(
+	ctx context.Context,
+	request *v1.DeleteNodeTemplateRequest,
+) (*v1.DeleteNodeTemplateResponse, error) {
+	err := api.h.DeleteNodeTemplate(request.GetName())
+	if errors.Is(err, errNodeTemplateNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.DeleteNodeTemplateResponse{}, nil
+}
+
+func (api headscaleV1APIServer) SetAutoTag(
+	ctx context.Context,
+	request *v1.SetAutoTagRequest,
+) (*v1.SetAutoTagResponse, error) {
+	rule, err := api.h.SetAutoTag(request.GetPattern(), request.GetTags())
+	if errors.Is(err, errInvalidAutoTag) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.SetAutoTagResponse{AutoTag: rule.toProto()}, nil
+}
+
+func (api headscaleV1APIServer) DeleteAutoTag(
+	ctx context.Context,
+	request *v1.DeleteAutoTagRequest,
+) (*v1.DeleteAutoTagResponse, error) {
+	err := api.h.DeleteAutoTag(request.GetPattern())
+	if errors.Is(err, errAutoTagNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.DeleteAutoTagResponse{}, nil
+}
+
+func (api headscaleV1APIServer) ListAutoTags(
+	ctx context.Context,
+	request *v1.ListAutoTagsRequest,
+) (*v1.ListAutoTagsResponse, error) {
+	rules, err := api.h.ListAutoTags()
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.AutoTag, len(rules))
+	for index, rule := range rules {
+		response[index] = rule.toProto()
+	}
+
+	return &v1.ListAutoTagsResponse{AutoTags: response}, nil
+}
+
+func (api headscaleV1APIServer) ApplyAutoTags(
+	ctx context.Context,
+	request *v1.ApplyAutoTagsRequest,
+) (*v1.ApplyAutoTagsResponse, error) {
+	changes, err := api.h.ApplyAutoTags(request.GetDryRun())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]*v1.AutoTagChange, len(changes))
+	for index, change := range changes {
+		response[index] = change.toProto()
+	}
+
+	return &v1.ApplyAutoTagsResponse{Changes: response}, nil
+}
+
 func (api headscaleV1APIServer) mustEmbedUnimplementedHeadscaleServiceServer() {}
@@ -618,14 +618,14 @@ func (s *IntegrationCLITestSuite) TestNodeTagCommand() {
 		},
 		[]string{},
 	)
-	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), err)
 	type errOutput struct {
 		Error string `json:"error"`
 	}
 	var errorOutput errOutput
 	err = json.Unmarshal([]byte(wrongTagResult), &errorOutput)
 	assert.Nil(s.T(), err)
-	assert.Contains(s.T(), errorOutput.Error, "Invalid tag detected")
+	assert.Contains(s.T(), errorOutput.Error, "invalid tag")
 
 	// Test list all nodes after added seconds
 	listAllResult, err := ExecuteCommand(
@@ -1188,7 +1188,7 @@ func (s *IntegrationCLITestSuite) TestNodeRenameCommand() {
 		},
 		[]string{},
 	)
-	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), err)
 	assert.Contains(s.T(), result, "not be over 63 chars")
 
 	listAllAfterRenameAttemptResult, err := ExecuteCommand(
@@ -1375,7 +1375,7 @@ func (s *IntegrationCLITestSuite) TestRouteCommand() {
 		},
 		[]string{},
 	)
-	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), err)
 
 	assert.Contains(
 		s.T(),
@@ -1621,6 +1621,7 @@ func (s *IntegrationCLITestSuite) TestNodeMoveCommand() {
 			"move",
 			"--identifier",
 			machineId,
+			"--force",
 			"--namespace",
 			newNamespace.Name,
 			"--output",
@@ -1666,6 +1667,7 @@ func (s *IntegrationCLITestSuite) TestNodeMoveCommand() {
 			"move",
 			"--identifier",
 			machineId,
+			"--force",
 			"--namespace",
 			"non-existing-namespace",
 			"--output",
@@ -1673,7 +1675,7 @@ func (s *IntegrationCLITestSuite) TestNodeMoveCommand() {
 		},
 		[]string{},
 	)
-	assert.Nil(s.T(), err)
+	assert.NotNil(s.T(), err)
 
 	assert.Contains(
 		s.T(),
@@ -1690,6 +1692,7 @@ func (s *IntegrationCLITestSuite) TestNodeMoveCommand() {
 			"move",
 			"--identifier",
 			machineId,
+			"--force",
 			"--namespace",
 			oldNamespace.Name,
 			"--output",
@@ -1712,6 +1715,7 @@ func (s *IntegrationCLITestSuite) TestNodeMoveCommand() {
 			"move",
 			"--identifier",
 			machineId,
+			"--force",
 			"--namespace",
 			oldNamespace.Name,
 			"--output",
@@ -113,7 +113,11 @@ func ExecuteCommand(
 			fmt.Println("stdout: ", stdout.String())
 			fmt.Println("stderr: ", stderr.String())
 
-			return "", fmt.Errorf("command failed with: %s", stderr.String())
+			return stdout.String(), fmt.Errorf(
+				"command failed with exit code %d: %s",
+				res.exitCode,
+				stderr.String(),
+			)
 		}
 
 		return stdout.String(), nil
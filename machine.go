@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -11,7 +12,9 @@ import (
 
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
 	"inet.af/netaddr"
 	"tailscale.com/tailcfg"
 	"tailscale.com/types/key"
@@ -26,12 +29,27 @@ const (
 	)
 	errCouldNotConvertMachineInterface = Error("failed to convert machine interface")
 	errHostnameTooLong                 = Error("Hostname too long")
+	errInvalidMachineName              = Error("invalid machine name")
+	errInvalidLabel                    = Error("invalid label")
+	errMachineNotDeleted               = Error("machine is not deleted")
+	errMachineNotRestorable            = Error("machine was deleted before the retention period")
+	errMachineKeyInUse                 = Error("machine key is used by another machine")
+	errInvalidMachineKey               = Error("invalid machine key")
+	errMachineNameInUse                = Error("name is used by another machine of the namespace")
+	errInvalidPageToken                = Error("invalid page token")
 	MachineGivenNameHashLength         = 8
 	MachineGivenNameTrimSize           = 2
 )
 
 const (
 	maxHostnameLength = 255
+	maxLabelLength    = 63
+)
+
+var (
+	repeatedHyphensRegex = regexp.MustCompile("-{2,}")
+	labelKeyRegex        = regexp.MustCompile("^[a-zA-Z0-9]([a-zA-Z0-9._/-]*[a-zA-Z0-9])?$")
+	labelValueRegex      = regexp.MustCompile("^[a-zA-Z0-9._/-]*$")
 )
 
 // Machine is a Headscale client.
@@ -60,21 +78,64 @@ type Machine struct {
 
 	ForcedTags StringList
 
+	// Quarantined machines stay registered and can talk to the control
+	// server, but are given no peers and are hidden from other machines.
+	Quarantined bool `gorm:"default:false"`
+
+	// ExitNodeOnly machines only accept traffic routed through them
+	// to the internet, peers cannot reach the machine itself.
+	ExitNodeOnly bool `gorm:"default:false"`
+
+	// Managed machines are maintained by nodes apply, which reverts the
+	// manual changes at the next reconcile.
+	Managed bool `gorm:"default:false"`
+
+	// ForcedDERPRegion is the DERP region the machine is asked to use as
+	// its home, 0 lets the client pick the closest one.
+	ForcedDERPRegion int `gorm:"default:0"`
+
+	// Labels are free form key/value metadata set by the administrators.
+	Labels StringMap
+
 	// TODO(kradalby): This seems like irrelevant information?
 	AuthKeyID uint
 	AuthKey   *PreAuthKey
 
+	// Ephemeral is set at registration when the client asks to be
+	// ephemeral or uses an ephemeral pre-auth key, see isEphemeral.
+	Ephemeral bool `gorm:"default:false"`
+
 	LastSeen             *time.Time
 	LastSuccessfulUpdate *time.Time
 	Expiry               *time.Time
 
+	// NetmapVersion is the version of the state (see netmapVersion) the
+	// last map successfully sent to the machine was built from.
+	NetmapVersion *time.Time
+
+	// RoutesUpdatedAt is when the routes advertised by the machine last
+	// changed, nil when it never advertised any.
+	RoutesUpdatedAt *time.Time
+
+	// KeyRefreshRequestedAt is set by RequestMachineKeyRefresh until the
+	// machine submits a new node key, and KeyRefreshExpiry is the expiry
+	// it gets back then.
+	KeyRefreshRequestedAt *time.Time
+	KeyRefreshExpiry      *time.Time
+
+	// ClockSkew is the offset of the clock of the machine from the one of
+	// the server, measured at its last registration. nil when unreported.
+	ClockSkew *time.Duration
+
 	HostInfo      HostInfo
 	Endpoints     StringList
 	EnabledRoutes IPPrefixes
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	DeletedAt *time.Time
+	// DeletedAt is set when the machine is deleted, it can be restored
+	// until it is purged after the deleted nodes retention period.
+	DeletedAt gorm.DeletedAt
 }
 
 type (
@@ -135,6 +196,13 @@ func (machine Machine) isExpired() bool {
 	return time.Now().UTC().After(*machine.Expiry)
 }
 
+// isEphemeral returns whether the machine is deleted when it stays
+// disconnected. The machines registered before Ephemeral was recorded
+// only have the setting of their pre-auth key.
+func (machine Machine) isEphemeral() bool {
+	return machine.Ephemeral || (machine.AuthKey != nil && machine.AuthKey.Ephemeral)
+}
+
 func containsAddresses(inputs []string, addrs []string) bool {
 	for _, addr := range addrs {
 		if contains(inputs, addr) {
@@ -301,13 +369,17 @@ func (h *Headscale) getPeers(machine *Machine) (Machines, error) {
 func (h *Headscale) getValidPeers(machine *Machine) (Machines, error) {
 	validPeers := make(Machines, 0)
 
+	if machine.Quarantined {
+		return validPeers, nil
+	}
+
 	peers, err := h.getPeers(machine)
 	if err != nil {
 		return Machines{}, err
 	}
 
 	for _, peer := range peers {
-		if !peer.isExpired() {
+		if !peer.isExpired() && !peer.Quarantined {
 			validPeers = append(validPeers, peer)
 		}
 	}
@@ -315,15 +387,89 @@ func (h *Headscale) getValidPeers(machine *Machine) (Machines, error) {
 	return validPeers, nil
 }
 
-func (h *Headscale) ListMachines() ([]Machine, error) {
+// ListMachines returns the machines, restricted by scopes (see
+// machinesPage).
+func (h *Headscale) ListMachines(scopes ...func(*gorm.DB) *gorm.DB) ([]Machine, error) {
 	machines := []Machine{}
-	if err := h.db.Preload("AuthKey").Preload("AuthKey.Namespace").Preload("Namespace").Find(&machines).Error; err != nil {
+	if err := h.db.Scopes(scopes...).Preload("AuthKey").Preload("AuthKey.Namespace").Preload("Namespace").Find(&machines).Error; err != nil {
 		return nil, err
 	}
 
 	return machines, nil
 }
 
+// machinesPage restricts a query to a page of at most pageSize machines,
+// ordered by ID, following the page of pageToken. The token is the ID of
+// the last machine of the previous page, empty for the first page.
+func machinesPage(pageToken string, pageSize int) (func(*gorm.DB) *gorm.DB, error) {
+	var afterID uint64
+	if pageToken != "" {
+		var err error
+		afterID, err = strconv.ParseUint(pageToken, Base10, 64)
+		if err != nil {
+			return nil, errInvalidPageToken
+		}
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("id > ?", afterID).Order("id").Limit(pageSize)
+	}, nil
+}
+
+// nextPageToken is the token of the page following machines, a page of
+// pageSize machines from machinesPage, empty when it is the last page.
+func nextPageToken(machines []Machine, pageSize int) string {
+	if pageSize == 0 || len(machines) < pageSize {
+		return ""
+	}
+
+	return strconv.FormatUint(machines[len(machines)-1].ID, Base10)
+}
+
+// listMachinesMatchingLabels returns the page of pageToken of the machines
+// of list having all the labels of selector, with the token of the next
+// page. The labels are not queryable, so the pages of list are filtered
+// one after the other until pageSize machines match. A pageSize of 0
+// returns all the matching machines.
+func listMachinesMatchingLabels(
+	list func(scopes ...func(*gorm.DB) *gorm.DB) ([]Machine, error),
+	selector map[string]string,
+	pageToken string,
+	pageSize int,
+) ([]Machine, string, error) {
+	if pageSize == 0 {
+		machines, err := list()
+		if err != nil {
+			return nil, "", err
+		}
+
+		return filterMachinesByLabels(machines, selector), "", nil
+	}
+
+	matching := []Machine{}
+	for {
+		page, err := machinesPage(pageToken, pageSize)
+		if err != nil {
+			return nil, "", err
+		}
+		machines, err := list(page)
+		if err != nil {
+			return nil, "", err
+		}
+		pageToken = nextPageToken(machines, pageSize)
+		matching = append(matching, filterMachinesByLabels(machines, selector)...)
+
+		if len(matching) > pageSize {
+			// The next page starts after the last machine returned.
+			matching = matching[:pageSize]
+			pageToken = strconv.FormatUint(matching[pageSize-1].ID, Base10)
+		}
+		if len(matching) == pageSize || pageToken == "" {
+			return matching, pageToken, nil
+		}
+	}
+}
+
 // GetMachine finds a Machine by name and namespace and returns the Machine struct.
 func (h *Headscale) GetMachine(namespace string, name string) (*Machine, error) {
 	machines, err := h.ListMachinesInNamespace(namespace)
@@ -343,7 +489,12 @@ func (h *Headscale) GetMachine(namespace string, name string) (*Machine, error)
 // GetMachineByID finds a Machine by ID and returns the Machine struct.
 func (h *Headscale) GetMachineByID(id uint64) (*Machine, error) {
 	m := Machine{}
-	if result := h.db.Preload("Namespace").Find(&Machine{ID: id}).First(&m); result.Error != nil {
+	if result := h.db.
+		Preload("AuthKey").
+		Preload("AuthKey.Namespace").
+		Preload("Namespace").
+		Find(&Machine{ID: id}).
+		First(&m); result.Error != nil {
 		return nil, result.Error
 	}
 
@@ -391,6 +542,7 @@ func (h *Headscale) SetTags(machine *Machine, tags []string) error {
 func (h *Headscale) ExpireMachine(machine *Machine) error {
 	now := time.Now()
 	machine.Expiry = &now
+	machine.cancelKeyRefresh()
 
 	h.setLastStateChangeToNow(machine.Namespace.Name)
 
@@ -401,10 +553,133 @@ func (h *Headscale) ExpireMachine(machine *Machine) error {
 	return nil
 }
 
+// RequestMachineKeyRefresh expires the node key of a machine, so its
+// client generates a new one. The new key is accepted at the next
+// registration of the machine without authentication, see
+// keyRefreshRequested, keeping its addresses, name and routes.
+func (h *Headscale) RequestMachineKeyRefresh(machine *Machine) error {
+	now := time.Now()
+	// The expiry is already the one of the refresh when requested again.
+	if !machine.keyRefreshRequested() {
+		machine.KeyRefreshExpiry = machine.Expiry
+	}
+	machine.KeyRefreshRequestedAt = &now
+	machine.Expiry = &now
+
+	h.setLastStateChangeToNow(machine.Namespace.Name)
+
+	if err := h.db.Save(machine).Error; err != nil {
+		return fmt.Errorf("failed to request the key refresh of machine in the database: %w", err)
+	}
+
+	return nil
+}
+
+// keyRefreshRequested tells if the node key of the machine was expired by
+// RequestMachineKeyRefresh and is waiting for a new one.
+func (machine *Machine) keyRefreshRequested() bool {
+	return machine.KeyRefreshRequestedAt != nil
+}
+
+// completeKeyRefresh gives back to the machine the expiry it had before
+// RequestMachineKeyRefresh, once its new node key is registered.
+func (machine *Machine) completeKeyRefresh() {
+	if !machine.keyRefreshRequested() {
+		return
+	}
+	machine.Expiry = machine.KeyRefreshExpiry
+	machine.cancelKeyRefresh()
+}
+
+// cancelKeyRefresh forgets a key refresh requested by
+// RequestMachineKeyRefresh, when the expiry of the machine is changed.
+func (machine *Machine) cancelKeyRefresh() {
+	machine.KeyRefreshRequestedAt = nil
+	machine.KeyRefreshExpiry = nil
+}
+
+// SetMachineExpiry sets when the key of a machine expires, never when
+// expiry is nil.
+func (h *Headscale) SetMachineExpiry(machine *Machine, expiry *time.Time) error {
+	machine.Expiry = expiry
+	machine.cancelKeyRefresh()
+
+	h.setLastStateChangeToNow(machine.Namespace.Name)
+
+	if err := h.db.Save(machine).Error; err != nil {
+		return fmt.Errorf("failed to set machine expiry in the database: %w", err)
+	}
+
+	return nil
+}
+
+// QuarantineMachine takes a Machine struct and isolates it from the network,
+// while keeping it registered.
+func (h *Headscale) QuarantineMachine(machine *Machine) error {
+	machine.Quarantined = true
+
+	// The machine disappears from the peer list of every namespace
+	// it can be seen from.
+	h.setLastStateChangeToNow()
+
+	if err := h.db.Save(machine).Error; err != nil {
+		return fmt.Errorf("failed to quarantine machine in the database: %w", err)
+	}
+
+	return nil
+}
+
+// UnquarantineMachine takes a Machine struct and lifts its quarantine.
+func (h *Headscale) UnquarantineMachine(machine *Machine) error {
+	machine.Quarantined = false
+
+	h.setLastStateChangeToNow()
+
+	if err := h.db.Save(machine).Error; err != nil {
+		return fmt.Errorf("failed to unquarantine machine in the database: %w", err)
+	}
+
+	return nil
+}
+
+// SetMachineExitNodeOnly takes a Machine struct and sets whether its peers
+// can only use it as an exit node.
+func (h *Headscale) SetMachineExitNodeOnly(machine *Machine, exitNodeOnly bool) error {
+	machine.ExitNodeOnly = exitNodeOnly
+
+	h.setLastStateChangeToNow(machine.Namespace.Name)
+
+	if err := h.db.Save(machine).Error; err != nil {
+		return fmt.Errorf("failed to update exit node only in the database: %w", err)
+	}
+
+	return nil
+}
+
+// SetMachineDERPRegion takes a Machine struct and forces its home DERP
+// region, 0 reverts to the automatic selection.
+func (h *Headscale) SetMachineDERPRegion(machine *Machine, regionID int) error {
+	if regionID != 0 {
+		if _, ok := h.DERPMap.Regions[regionID]; !ok {
+			return errDERPRegionNotFound
+		}
+	}
+
+	machine.ForcedDERPRegion = regionID
+
+	h.setLastStateChangeToNow(machine.Namespace.Name)
+
+	if err := h.db.Save(machine).Error; err != nil {
+		return fmt.Errorf("failed to update the DERP region in the database: %w", err)
+	}
+
+	return nil
+}
+
 // RenameMachine takes a Machine struct and a new GivenName for the machines
 // and renames it.
 func (h *Headscale) RenameMachine(machine *Machine, newName string) error {
-	err := CheckForFQDNRules(
+	err := CheckForDNSLabelRules(
 		newName,
 	)
 	if err != nil {
@@ -417,6 +692,19 @@ func (h *Headscale) RenameMachine(machine *Machine, newName string) error {
 
 		return err
 	}
+
+	// The given name is the MagicDNS name of the machine in its namespace.
+	var count int64
+	err = h.db.Model(&Machine{}).
+		Where("namespace_id = ? AND given_name = ? AND id != ?", machine.NamespaceID, newName, machine.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%q: %w", newName, errMachineNameInUse)
+	}
+
 	machine.GivenName = newName
 
 	h.setLastStateChangeToNow(machine.Namespace.Name)
@@ -461,6 +749,7 @@ func (h *Headscale) TouchMachine(machine *Machine) error {
 		ID:                   machine.ID,
 		LastSeen:             machine.LastSeen,
 		LastSuccessfulUpdate: machine.LastSuccessfulUpdate,
+		NetmapVersion:        machine.NetmapVersion,
 	}).Error
 }
 
@@ -473,6 +762,314 @@ func (h *Headscale) HardDeleteMachine(machine *Machine) error {
 	return nil
 }
 
+// ListDeletedMachines returns the machines deleted and not purged yet.
+func (h *Headscale) ListDeletedMachines(scopes ...func(*gorm.DB) *gorm.DB) ([]Machine, error) {
+	machines := []Machine{}
+	if err := h.db.Unscoped().
+		Scopes(scopes...).
+		Preload("AuthKey").
+		Preload("AuthKey.Namespace").
+		Preload("Namespace").
+		Where("deleted_at IS NOT NULL").
+		Find(&machines).Error; err != nil {
+		return nil, err
+	}
+
+	return machines, nil
+}
+
+// RestoreMachine brings back a deleted machine, as long as it was deleted
+// within the retention period. If its IP addresses were given to another
+// machine in the meantime, new ones are allocated and ipAddressesChanged
+// is true.
+func (h *Headscale) RestoreMachine(
+	machineID uint64,
+) (machine *Machine, ipAddressesChanged bool, err error) {
+	machine = &Machine{}
+	if err := h.db.Unscoped().
+		Preload("Namespace").
+		First(machine, "id = ?", machineID).Error; err != nil {
+		return nil, false, errMachineNotFound
+	}
+
+	if !machine.DeletedAt.Valid {
+		return nil, false, errMachineNotDeleted
+	}
+
+	if time.Since(machine.DeletedAt.Time) > h.cfg.DeletedNodesRetention {
+		return nil, false, errMachineNotRestorable
+	}
+
+	if machine.Namespace.ID == 0 {
+		return nil, false, errNamespaceNotFound
+	}
+
+	var count int64
+	if err := h.db.Model(&Machine{}).
+		Where("machine_key = ?", machine.MachineKey).
+		Count(&count).Error; err != nil {
+		return nil, false, err
+	}
+	if count > 0 {
+		return nil, false, errMachineKeyInUse
+	}
+
+	// Another machine of the namespace may have been given its MagicDNS
+	// name while it was deleted, see RenameMachine.
+	if err := h.db.Model(&Machine{}).
+		Where("namespace_id = ? AND given_name = ?", machine.NamespaceID, machine.GivenName).
+		Count(&count).Error; err != nil {
+		return nil, false, err
+	}
+	if count > 0 {
+		return nil, false, fmt.Errorf("%q: %w", machine.GivenName, errMachineNameInUse)
+	}
+
+	h.ipAllocationMutex.Lock()
+	defer h.ipAllocationMutex.Unlock()
+
+	usedIPs, err := h.getUsedIPs()
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, address := range machine.IPAddresses {
+		if usedIPs.Contains(address) {
+			ipAddressesChanged = true
+		}
+	}
+
+	if ipAddressesChanged {
+		ips, err := h.getAvailableIPs()
+		if err != nil {
+			return nil, false, err
+		}
+
+		log.Warn().
+			Str("machine", machine.Hostname).
+			Strs("old_addresses", machine.IPAddresses.ToStringSlice()).
+			Strs("new_addresses", ips.ToStringSlice()).
+			Msg("IP addresses of the restored machine were reallocated, assigning new ones")
+
+		machine.IPAddresses = ips
+	}
+
+	machine.DeletedAt = gorm.DeletedAt{}
+	if err := h.db.Unscoped().Save(machine).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to restore machine in the database: %w", err)
+	}
+
+	h.setLastStateChangeToNow(machine.Namespace.Name)
+
+	return machine, ipAddressesChanged, nil
+}
+
+// ImportMachine recreates a machine exported from another server in a
+// namespace, so it is recognised when it connects with its keys. If its
+// IP addresses are used by another machine, or if it has none, new ones
+// are allocated and ipAddressesChanged is true.
+func (h *Headscale) ImportMachine(
+	machine Machine,
+	namespaceName string,
+) (imported *Machine, ipAddressesChanged bool, err error) {
+	var machineKey key.MachinePublic
+	err = machineKey.UnmarshalText([]byte(MachinePublicKeyEnsurePrefix(machine.MachineKey)))
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", err, errInvalidMachineKey)
+	}
+	machine.MachineKey = MachinePublicKeyStripPrefix(machineKey)
+
+	if machine.NodeKey != "" {
+		var nodeKey key.NodePublic
+		err = nodeKey.UnmarshalText([]byte(NodePublicKeyEnsurePrefix(machine.NodeKey)))
+		if err != nil {
+			return nil, false, fmt.Errorf("node key: %s: %w", err, errInvalidMachineKey)
+		}
+		machine.NodeKey = NodePublicKeyStripPrefix(nodeKey)
+	}
+
+	if machine.DiscoKey != "" {
+		var discoKey key.DiscoPublic
+		err = discoKey.UnmarshalText([]byte(DiscoPublicKeyEnsurePrefix(machine.DiscoKey)))
+		if err != nil {
+			return nil, false, fmt.Errorf("disco key: %s: %w", err, errInvalidMachineKey)
+		}
+		machine.DiscoKey = DiscoPublicKeyStripPrefix(discoKey)
+	}
+
+	namespace, err := h.GetNamespace(namespaceName)
+	if err != nil {
+		return nil, false, err
+	}
+	machine.NamespaceID = namespace.ID
+	machine.Namespace = *namespace
+	machine.RegisterMethod = RegisterMethodCLI
+
+	var count int64
+	if err := h.db.Model(&Machine{}).
+		Where("machine_key = ?", machine.MachineKey).
+		Count(&count).Error; err != nil {
+		return nil, false, err
+	}
+	if count > 0 {
+		return nil, false, errMachineKeyInUse
+	}
+
+	if machine.GivenName == "" {
+		machine.GivenName, err = h.GenerateGivenName(machine.Hostname)
+		if err != nil {
+			return nil, false, err
+		}
+	} else {
+		if err := CheckForDNSLabelRules(machine.GivenName); err != nil {
+			return nil, false, err
+		}
+		if err := h.db.Model(&Machine{}).
+			Where("namespace_id = ? AND given_name = ?", namespace.ID, machine.GivenName).
+			Count(&count).Error; err != nil {
+			return nil, false, err
+		}
+		if count > 0 {
+			return nil, false, fmt.Errorf("%q: %w", machine.GivenName, errMachineNameInUse)
+		}
+	}
+
+	h.ipAllocationMutex.Lock()
+	defer h.ipAllocationMutex.Unlock()
+
+	usedIPs, err := h.getUsedIPs()
+	if err != nil {
+		return nil, false, err
+	}
+
+	ipAddressesChanged = len(machine.IPAddresses) == 0
+	for _, address := range machine.IPAddresses {
+		if usedIPs.Contains(address) {
+			ipAddressesChanged = true
+		}
+	}
+
+	if ipAddressesChanged {
+		ips, err := h.getAvailableIPs()
+		if err != nil {
+			return nil, false, err
+		}
+
+		log.Warn().
+			Str("machine", machine.Hostname).
+			Strs("old_addresses", machine.IPAddresses.ToStringSlice()).
+			Strs("new_addresses", ips.ToStringSlice()).
+			Msg("IP addresses of the imported machine were in use, assigning new ones")
+
+		machine.IPAddresses = ips
+	}
+
+	if err := h.db.Save(&machine).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to import machine in the database: %w", err)
+	}
+
+	if err := h.UpdateACLRules(); err != nil && !errors.Is(err, errEmptyPolicy) {
+		return nil, false, err
+	}
+	h.setLastStateChangeToNow(machine.Namespace.Name)
+
+	return &machine, ipAddressesChanged, nil
+}
+
+// purgeDeletedMachines periodically removes for good the machines deleted
+// for longer than the retention period.
+func (h *Headscale) purgeDeletedMachines(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	for range ticker.C {
+		h.purgeDeletedMachinesWorker()
+	}
+}
+
+func (h *Headscale) purgeDeletedMachinesWorker() {
+	result := h.db.Unscoped().
+		Where("deleted_at < ?", time.Now().Add(-h.cfg.DeletedNodesRetention)).
+		Delete(&Machine{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("Could not purge deleted machines")
+
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		log.Info().
+			Int64("count", result.RowsAffected).
+			Msg("Purged deleted machines")
+	}
+}
+
+// expireIdleMachines periodically expires the machines not seen for longer
+// than the idle expiry of their namespace.
+func (h *Headscale) expireIdleMachines(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	for range ticker.C {
+		h.expireIdleMachinesWorker()
+	}
+}
+
+func (h *Headscale) expireIdleMachinesWorker() {
+	namespaces, err := h.ListNamespaces()
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing namespaces")
+
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, namespace := range namespaces {
+		if namespace.IdleExpiry <= 0 {
+			continue
+		}
+
+		machines, err := h.ListMachinesInNamespace(namespace.Name)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("namespace", namespace.Name).
+				Msg("Error listing machines in namespace")
+
+			continue
+		}
+
+		for index, machine := range machines {
+			if machine.isExpired() || !machine.isIdleSince(now.Add(-namespace.IdleExpiry)) {
+				continue
+			}
+
+			err := h.ExpireMachine(&machines[index])
+			if err != nil {
+				log.Error().
+					Err(err).
+					Str("machine", machine.Hostname).
+					Msg("Could not expire idle machine")
+
+				continue
+			}
+
+			log.Info().
+				Str("machine", machine.Hostname).
+				Str("namespace", namespace.Name).
+				Dur("idle_expiry", namespace.IdleExpiry).
+				Msg("Expired idle machine")
+		}
+	}
+}
+
+// isIdleSince tells if the machine was not seen since the given time. A
+// machine never seen is idle since its registration.
+func (machine Machine) isIdleSince(since time.Time) bool {
+	lastSeen := machine.CreatedAt
+	if machine.LastSeen != nil && machine.LastSeen.After(lastSeen) {
+		lastSeen = *machine.LastSeen
+	}
+
+	return lastSeen.Before(since)
+}
+
 // GetHostInfo returns a Hostinfo struct for the machine.
 func (machine *Machine) GetHostInfo() tailcfg.Hostinfo {
 	return tailcfg.Hostinfo(machine.HostInfo)
@@ -551,6 +1148,24 @@ func (machines Machines) toNodes(
 
 // toNode converts a Machine into a Tailscale Node. includeRoutes is false for shared nodes
 // as per the expected behaviour in the official SaaS.
+// magicDNSName returns the FQDN of the machine when MagicDNS is enabled.
+func (machine Machine) magicDNSName(baseDomain string) (string, error) {
+	hostname := fmt.Sprintf(
+		"%s.%s",
+		machine.GivenName,
+		machine.Namespace.magicDNSDomain(baseDomain),
+	)
+	if len(hostname) > maxHostnameLength {
+		return "", fmt.Errorf(
+			"hostname %q is too long it cannot except 255 ASCII chars: %w",
+			hostname,
+			errHostnameTooLong,
+		)
+	}
+
+	return hostname, nil
+}
+
 func (machine Machine) toNode(
 	baseDomain string,
 	dnsConfig *tailcfg.DNSConfig,
@@ -619,18 +1234,9 @@ func (machine Machine) toNode(
 
 	var hostname string
 	if dnsConfig != nil && dnsConfig.Proxied { // MagicDNS
-		hostname = fmt.Sprintf(
-			"%s.%s.%s",
-			machine.GivenName,
-			machine.Namespace.Name,
-			baseDomain,
-		)
-		if len(hostname) > maxHostnameLength {
-			return nil, fmt.Errorf(
-				"hostname %q is too long it cannot except 255 ASCII chars: %w",
-				hostname,
-				errHostnameTooLong,
-			)
+		hostname, err = machine.magicDNSName(baseDomain)
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		hostname = machine.GivenName
@@ -683,6 +1289,12 @@ func (machine *Machine) toProto() *v1.Machine {
 		GivenName:   machine.GivenName,
 		Namespace:   machine.Namespace.toProto(),
 		ForcedTags:  machine.ForcedTags,
+		Quarantined: machine.Quarantined,
+		Labels:      machine.Labels,
+
+		ExitNodeOnly: machine.ExitNodeOnly,
+
+		ForcedDerpRegion: int32(machine.ForcedDERPRegion),
 
 		// TODO(kradalby): Implement register method enum converter
 		// RegisterMethod: ,
@@ -693,6 +1305,7 @@ func (machine *Machine) toProto() *v1.Machine {
 	if machine.AuthKey != nil {
 		machineProto.PreAuthKey = machine.AuthKey.toProto()
 	}
+	machineProto.Ephemeral = machine.isEphemeral()
 
 	if machine.LastSeen != nil {
 		machineProto.LastSeen = timestamppb.New(*machine.LastSeen)
@@ -708,6 +1321,43 @@ func (machine *Machine) toProto() *v1.Machine {
 		machineProto.Expiry = timestamppb.New(*machine.Expiry)
 	}
 
+	if machine.NetmapVersion != nil && !machine.NetmapVersion.IsZero() {
+		machineProto.NetmapVersion = timestamppb.New(*machine.NetmapVersion)
+	}
+
+	if machine.RoutesUpdatedAt != nil {
+		machineProto.RoutesUpdated = timestamppb.New(*machine.RoutesUpdatedAt)
+	}
+
+	if machine.KeyRefreshRequestedAt != nil {
+		machineProto.KeyRefreshRequested = timestamppb.New(*machine.KeyRefreshRequestedAt)
+	}
+
+	machineProto.ClientVersion = machine.HostInfo.IPNVersion
+	machineProto.Os = machine.HostInfo.OS
+	machineProto.OsVersion = machine.HostInfo.OSVersion
+	machineProto.Arch = machine.HostInfo.GoArch
+	machineProto.DeviceModel = machine.HostInfo.DeviceModel
+	machineProto.ClientPackage = machine.HostInfo.Package
+	machineProto.Endpoints = machine.Endpoints
+	machineProto.Managed = machine.Managed
+
+	for _, ip := range machine.IPAddresses {
+		machineProto.PtrNames = append(machineProto.PtrNames, reverseDNSName(ip))
+	}
+
+	if machine.ClockSkew != nil {
+		machineProto.ClockSkew = durationpb.New(*machine.ClockSkew)
+	}
+
+	if machine.HostInfo.NetInfo != nil {
+		machineProto.PreferredDerpRegion = int32(machine.HostInfo.NetInfo.PreferredDERP)
+	}
+
+	if machine.DeletedAt.Valid {
+		machineProto.DeletedAt = timestamppb.New(machine.DeletedAt.Time)
+	}
+
 	return machineProto
 }
 
@@ -759,6 +1409,8 @@ func (h *Headscale) RegisterMachineFromAuthCallback(
 	machineKeyStr string,
 	namespaceName string,
 	registrationMethod string,
+	labels map[string]string,
+	ipAddresses MachineAddresses,
 ) (*Machine, error) {
 	if machineInterface, ok := h.registrationCache.Get(machineKeyStr); ok {
 		if registrationMachine, ok := machineInterface.(Machine); ok {
@@ -769,9 +1421,21 @@ func (h *Headscale) RegisterMachineFromAuthCallback(
 					err,
 				)
 			}
+			if namespace.Locked {
+				return nil, errNamespaceLocked
+			}
+
+			err = validateLabels(labels)
+			if err != nil {
+				return nil, err
+			}
 
 			registrationMachine.NamespaceID = namespace.ID
 			registrationMachine.RegisterMethod = registrationMethod
+			if len(labels) > 0 {
+				registrationMachine.Labels = labels
+			}
+			registrationMachine.IPAddresses = ipAddresses
 
 			machine, err := h.RegisterMachine(
 				registrationMachine,
@@ -786,6 +1450,80 @@ func (h *Headscale) RegisterMachineFromAuthCallback(
 	return nil, errMachineNotFoundRegistrationCache
 }
 
+// validateLabels checks that the label keys and values are made of
+// letters, digits, dots, underscores, slashes and hyphens, and are not
+// longer than 63 characters.
+func validateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if len(key) > maxLabelLength || !labelKeyRegex.MatchString(key) {
+			return fmt.Errorf(
+				"label key %q must be 1 to %d letters, digits, '.', '_', '/' or '-': %w",
+				key,
+				maxLabelLength,
+				errInvalidLabel,
+			)
+		}
+		if len(value) > maxLabelLength || !labelValueRegex.MatchString(value) {
+			return fmt.Errorf(
+				"label value %q of %q must be at most %d letters, digits, '.', '_', '/' or '-': %w",
+				value,
+				key,
+				maxLabelLength,
+				errInvalidLabel,
+			)
+		}
+	}
+
+	return nil
+}
+
+// ParseLabelSelector parses a comma separated list of key=value
+// requirements (e.g. env=staging,team=web), all of which must be met
+// by a machine to be selected.
+func ParseLabelSelector(selector string) (map[string]string, error) {
+	requirements := make(map[string]string)
+	for _, requirement := range strings.Split(selector, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(requirement), "=")
+		if !found {
+			return nil, fmt.Errorf(
+				"requirement %q of the selector is not in the key=value format: %w",
+				requirement,
+				errInvalidLabel,
+			)
+		}
+		requirements[key] = value
+	}
+
+	if err := validateLabels(requirements); err != nil {
+		return nil, err
+	}
+
+	return requirements, nil
+}
+
+// matchesLabels reports if the machine has all the labels of selector.
+func (machine Machine) matchesLabels(selector map[string]string) bool {
+	for key, value := range selector {
+		if labelValue, ok := machine.Labels[key]; !ok || labelValue != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filterMachinesByLabels returns the machines having all the labels of selector.
+func filterMachinesByLabels(machines []Machine, selector map[string]string) []Machine {
+	filtered := []Machine{}
+	for _, machine := range machines {
+		if machine.matchesLabels(selector) {
+			filtered = append(filtered, machine)
+		}
+	}
+
+	return filtered
+}
+
 // RegisterMachine is executed from the CLI to register a new Machine using its MachineKey.
 func (h *Headscale) RegisterMachine(machine Machine,
 ) (*Machine, error) {
@@ -802,7 +1540,8 @@ func (h *Headscale) RegisterMachine(machine Machine,
 	h.ipAllocationMutex.Lock()
 	defer h.ipAllocationMutex.Unlock()
 
-	ips, err := h.getAvailableIPs()
+	// The machine may come with the addresses requested for it.
+	ips, err := h.getRequestedIPs(machine.IPAddresses)
 	if err != nil {
 		log.Error().
 			Caller().
@@ -815,6 +1554,16 @@ func (h *Headscale) RegisterMachine(machine Machine,
 
 	machine.IPAddresses = ips
 
+	autoTags, err := h.missingAutoTags(&machine)
+	if err != nil {
+		log.Error().
+			Caller().
+			Err(err).
+			Str("machine", machine.Hostname).
+			Msg("Could not apply the autotags to the new machine")
+	}
+	machine.ForcedTags = append(machine.ForcedTags, autoTags...)
+
 	if err := h.db.Save(&machine).Error; err != nil {
 		return nil, fmt.Errorf("failed register(save) machine in the database: %w", err)
 	}
@@ -828,10 +1577,122 @@ func (h *Headscale) RegisterMachine(machine Machine,
 	return &machine, nil
 }
 
+// MachineIPAssignment are the addresses given to a machine by
+// BackfillMachineIPs.
+type MachineIPAssignment struct {
+	Machine     Machine
+	AssignedIPs MachineAddresses
+}
+
+// hasIPOfFamily tells if the machine has an address of the family of
+// ipPrefix, IPv4 or IPv6.
+func (machine *Machine) hasIPOfFamily(ipPrefix netaddr.IPPrefix) bool {
+	for _, ip := range machine.IPAddresses {
+		if ip.Is4() == ipPrefix.IP().Is4() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BackfillMachineIPs gives the machines missing an IPv4 or IPv6 address
+// one from the configured prefixes, as their registration would have.
+// With dryRun, the machines are left unchanged.
+func (h *Headscale) BackfillMachineIPs(dryRun bool) ([]MachineIPAssignment, error) {
+	h.ipAllocationMutex.Lock()
+	defer h.ipAllocationMutex.Unlock()
+
+	machines, err := h.ListMachines()
+	if err != nil {
+		return nil, err
+	}
+
+	usedIPs, err := h.getUsedIPs()
+	if err != nil {
+		return nil, err
+	}
+
+	assignments := []MachineIPAssignment{}
+	for index := range machines {
+		machine := &machines[index]
+
+		assigned := MachineAddresses{}
+		for _, ipPrefix := range h.cfg.IPPrefixes {
+			if machine.hasIPOfFamily(ipPrefix) {
+				continue
+			}
+
+			ip, err := nextAvailableIP(ipPrefix, usedIPs)
+			if err != nil {
+				return nil, err
+			}
+			assigned = append(assigned, *ip)
+
+			// The addresses of the dry run are not saved, so they are
+			// kept aside for the next machines.
+			var ips netaddr.IPSetBuilder
+			ips.AddSet(usedIPs)
+			ips.Add(*ip)
+			usedIPs, err = ips.IPSet()
+			if err != nil {
+				return nil, fmt.Errorf("failed to build IP Set: %w", err)
+			}
+		}
+		if len(assigned) == 0 {
+			continue
+		}
+
+		machine.IPAddresses = append(machine.IPAddresses, assigned...)
+		if !dryRun {
+			h.setLastStateChangeToNow(machine.Namespace.Name)
+
+			if err := h.db.Save(machine).Error; err != nil {
+				return nil, fmt.Errorf("failed to save machine addresses in the database: %w", err)
+			}
+		}
+		assignments = append(assignments, MachineIPAssignment{
+			Machine:     *machine,
+			AssignedIPs: assigned,
+		})
+	}
+
+	if !dryRun && len(assignments) > 0 {
+		if err := h.UpdateACLRules(); err != nil && !errors.Is(err, errEmptyPolicy) {
+			return nil, err
+		}
+	}
+
+	return assignments, nil
+}
+
+func (assignment MachineIPAssignment) toProto() *v1.MachineIPAssignment {
+	return &v1.MachineIPAssignment{
+		Machine:     assignment.Machine.toProto(),
+		AssignedIps: assignment.AssignedIPs.ToStringSlice(),
+	}
+}
+
 func (machine *Machine) GetAdvertisedRoutes() []netaddr.IPPrefix {
 	return machine.HostInfo.RoutableIPs
 }
 
+// advertisedRoutesChanged tells if routes, in any order, differ from the
+// routes advertised by the machine.
+func (machine *Machine) advertisedRoutesChanged(routes []netaddr.IPPrefix) bool {
+	advertised := machine.GetAdvertisedRoutes()
+	if len(routes) != len(advertised) {
+		return true
+	}
+	for _, route := range routes {
+		if !contains(advertised, route) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (machine *Machine) GetEnabledRoutes() []netaddr.IPPrefix {
 	return machine.EnabledRoutes
 }
@@ -901,12 +1762,40 @@ func (h *Headscale) GenerateGivenName(suppliedName string) (string, error) {
 	// it needs to be trimmed.
 	trimmedHostnameLength := labelHostnameLength - MachineGivenNameHashLength - MachineGivenNameTrimSize
 
-	normalizedHostname, err := NormalizeToFQDNRules(
-		suppliedName,
-		h.cfg.OIDC.StripEmaildomain,
-	)
-	if err != nil {
-		return "", err
+	var normalizedHostname string
+	if h.cfg.NormalizeNames {
+		var err error
+		normalizedHostname, err = NormalizeToFQDNRules(
+			suppliedName,
+			h.cfg.OIDC.StripEmaildomain,
+		)
+		if err != nil {
+			return "", err
+		}
+
+		if h.cfg.SingleLabelNames {
+			normalizedHostname = strings.Trim(
+				repeatedHyphensRegex.ReplaceAllString(
+					strings.ReplaceAll(normalizedHostname, ".", "-"),
+					"-",
+				),
+				"-",
+			)
+		}
+	} else {
+		err := CheckForFQDNRules(suppliedName)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(suppliedName, ".") {
+			return "", fmt.Errorf(
+				"hostname %q must be a single DNS label when names normalization is disabled: %w",
+				suppliedName,
+				errInvalidMachineName,
+			)
+		}
+
+		normalizedHostname = suppliedName
 	}
 
 	postfix, err := GenerateRandomStringDNSSafe(MachineGivenNameHashLength)
@@ -1,6 +1,7 @@
 package headscale
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -8,9 +9,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/patrickmn/go-cache"
 	"gopkg.in/check.v1"
 	"inet.af/netaddr"
 	"tailscale.com/tailcfg"
+	"tailscale.com/types/key"
 )
 
 func (s *Suite) TestGetMachine(c *check.C) {
@@ -109,6 +112,135 @@ func (s *Suite) TestHardDeleteMachine(c *check.C) {
 	c.Assert(err, check.NotNil)
 }
 
+func (s *Suite) TestRestoreMachine(c *check.C) {
+	app.cfg.DeletedNodesRetention = time.Hour
+
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+	machine := Machine{
+		ID:             1,
+		MachineKey:     "foo",
+		NodeKey:        "bar",
+		DiscoKey:       "faa",
+		Hostname:       "testmachine",
+		GivenName:      "testmachine",
+		NamespaceID:    namespace.ID,
+		RegisterMethod: RegisterMethodAuthKey,
+		IPAddresses:    MachineAddresses{netaddr.MustParseIP("10.27.0.1")},
+	}
+	app.db.Save(&machine)
+
+	_, _, err = app.RestoreMachine(machine.ID)
+	c.Assert(err, check.Equals, errMachineNotDeleted)
+
+	err = app.DeleteMachine(&machine)
+	c.Assert(err, check.IsNil)
+
+	deleted, err := app.ListDeletedMachines()
+	c.Assert(err, check.IsNil)
+	c.Assert(deleted, check.HasLen, 1)
+
+	restored, ipAddressesChanged, err := app.RestoreMachine(machine.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(ipAddressesChanged, check.Equals, false)
+	c.Assert(restored.IPAddresses, check.DeepEquals, machine.IPAddresses)
+
+	_, err = app.GetMachineByID(machine.ID)
+	c.Assert(err, check.IsNil)
+
+	// The IP address is given to another machine while deleted.
+	err = app.DeleteMachine(restored)
+	c.Assert(err, check.IsNil)
+	other := Machine{
+		ID:          2,
+		MachineKey:  "other",
+		Hostname:    "other",
+		GivenName:   "other",
+		NamespaceID: namespace.ID,
+		IPAddresses: machine.IPAddresses,
+	}
+	app.db.Save(&other)
+
+	restored, ipAddressesChanged, err = app.RestoreMachine(machine.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(ipAddressesChanged, check.Equals, true)
+	c.Assert(restored.IPAddresses, check.Not(check.DeepEquals), machine.IPAddresses)
+
+	// The name is given to another machine while deleted.
+	err = app.DeleteMachine(restored)
+	c.Assert(err, check.IsNil)
+	err = app.RenameMachine(&other, machine.GivenName)
+	c.Assert(err, check.IsNil)
+
+	_, _, err = app.RestoreMachine(machine.ID)
+	c.Assert(errors.Is(err, errMachineNameInUse), check.Equals, true)
+
+	err = app.RenameMachine(&other, "other")
+	c.Assert(err, check.IsNil)
+	restored, _, err = app.RestoreMachine(machine.ID)
+	c.Assert(err, check.IsNil)
+
+	// Machines deleted before the retention period are purged.
+	err = app.DeleteMachine(restored)
+	c.Assert(err, check.IsNil)
+	app.db.Unscoped().
+		Model(&Machine{}).
+		Where("id = ?", machine.ID).
+		Update("deleted_at", time.Now().Add(-2*time.Hour))
+
+	_, _, err = app.RestoreMachine(machine.ID)
+	c.Assert(err, check.Equals, errMachineNotRestorable)
+
+	app.purgeDeletedMachinesWorker()
+
+	_, _, err = app.RestoreMachine(machine.ID)
+	c.Assert(err, check.Equals, errMachineNotFound)
+}
+
+func (s *Suite) TestSetMachineDERPRegion(c *check.C) {
+	app.DERPMap = &tailcfg.DERPMap{
+		Regions: map[int]*tailcfg.DERPRegion{
+			1:   {RegionID: 1, RegionCode: "nyc"},
+			900: {RegionID: 900, RegionCode: "headscale"},
+		},
+	}
+
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+	machine := Machine{
+		ID:          1,
+		MachineKey:  "foo",
+		Hostname:    "testmachine",
+		NamespaceID: namespace.ID,
+	}
+	app.db.Save(&machine)
+
+	err = app.SetMachineDERPRegion(&machine, 2)
+	c.Assert(err, check.Equals, errDERPRegionNotFound)
+
+	err = app.SetMachineDERPRegion(&machine, 900)
+	c.Assert(err, check.IsNil)
+
+	stored, err := app.GetMachineByID(machine.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.ForcedDERPRegion, check.Equals, 900)
+
+	derpMap := forceDERPRegion(app.DERPMap, stored.ForcedDERPRegion)
+	c.Assert(derpMap.Regions, check.HasLen, 2)
+	c.Assert(derpMap.Regions[1].Avoid, check.Equals, true)
+	c.Assert(derpMap.Regions[900].Avoid, check.Equals, false)
+	// The DERP map shared by the other machines is left untouched.
+	c.Assert(app.DERPMap.Regions[1].Avoid, check.Equals, false)
+
+	c.Assert(derpRegionCode(app.DERPMap, 1), check.Equals, "nyc")
+	c.Assert(derpRegionCode(app.DERPMap, 2), check.Equals, "")
+	c.Assert(derpRegionCode(nil, 1), check.Equals, "")
+
+	err = app.SetMachineDERPRegion(&machine, 0)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.ForcedDERPRegion, check.Equals, 0)
+}
+
 func (s *Suite) TestListPeers(c *check.C) {
 	namespace, err := app.CreateNamespace("test")
 	c.Assert(err, check.IsNil)
@@ -259,6 +391,390 @@ func (s *Suite) TestExpireMachine(c *check.C) {
 	c.Assert(machineFromDB.isExpired(), check.Equals, true)
 }
 
+func (s *Suite) TestSetMachineExpiry(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	machine := &Machine{
+		ID:          0,
+		MachineKey:  "foo",
+		Hostname:    "testmachine",
+		NamespaceID: namespace.ID,
+	}
+	app.db.Save(machine)
+
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	err = app.SetMachineExpiry(machine, &expiry)
+	c.Assert(err, check.IsNil)
+
+	machineFromDB, err := app.GetMachineByID(machine.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(machineFromDB.Expiry.Equal(expiry), check.Equals, true)
+	c.Assert(machineFromDB.isExpired(), check.Equals, false)
+
+	err = app.SetMachineExpiry(machineFromDB, nil)
+	c.Assert(err, check.IsNil)
+
+	machineFromDB, err = app.GetMachineByID(machine.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(machineFromDB.Expiry, check.IsNil)
+	c.Assert(machineFromDB.isExpired(), check.Equals, false)
+}
+
+func (s *Suite) TestRequestMachineKeyRefresh(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	machine := &Machine{
+		ID:          0,
+		MachineKey:  "foo",
+		NodeKey:     "bar",
+		Hostname:    "testmachine",
+		NamespaceID: namespace.ID,
+		Expiry:      &expiry,
+	}
+	app.db.Save(machine)
+
+	err = app.RequestMachineKeyRefresh(machine)
+	c.Assert(err, check.IsNil)
+	// Requesting it again keeps the expiry to give back.
+	err = app.RequestMachineKeyRefresh(machine)
+	c.Assert(err, check.IsNil)
+
+	machineFromDB, err := app.GetMachineByID(machine.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(machineFromDB.isExpired(), check.Equals, true)
+	c.Assert(machineFromDB.keyRefreshRequested(), check.Equals, true)
+	c.Assert(machineFromDB.toProto().GetKeyRefreshRequested(), check.NotNil)
+
+	machineFromDB.completeKeyRefresh()
+	c.Assert(machineFromDB.keyRefreshRequested(), check.Equals, false)
+	c.Assert(machineFromDB.Expiry.Equal(expiry), check.Equals, true)
+	c.Assert(machineFromDB.isExpired(), check.Equals, false)
+
+	// Expiring the machine cancels the refresh.
+	err = app.RequestMachineKeyRefresh(machineFromDB)
+	c.Assert(err, check.IsNil)
+	err = app.ExpireMachine(machineFromDB)
+	c.Assert(err, check.IsNil)
+	c.Assert(machineFromDB.keyRefreshRequested(), check.Equals, false)
+}
+
+func (s *Suite) TestBackfillMachineIPs(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	for index := 1; index <= 3; index++ {
+		machine := &Machine{
+			ID:          uint64(index),
+			MachineKey:  "foo" + strconv.Itoa(index),
+			Hostname:    "testmachine" + strconv.Itoa(index),
+			NamespaceID: namespace.ID,
+		}
+		if index == 1 {
+			machine.IPAddresses = MachineAddresses{netaddr.MustParseIP("10.27.0.1")}
+		}
+		app.db.Save(machine)
+	}
+
+	assignments, err := app.BackfillMachineIPs(true)
+	c.Assert(err, check.IsNil)
+	c.Assert(assignments, check.HasLen, 2)
+	c.Assert(assignments[0].Machine.ID, check.Equals, uint64(2))
+	c.Assert(assignments[0].AssignedIPs.ToStringSlice(), check.DeepEquals, []string{"10.27.0.2"})
+	c.Assert(assignments[1].Machine.ID, check.Equals, uint64(3))
+	c.Assert(assignments[1].AssignedIPs.ToStringSlice(), check.DeepEquals, []string{"10.27.0.3"})
+
+	machine, err := app.GetMachineByID(2)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.IPAddresses, check.HasLen, 0)
+
+	assignments, err = app.BackfillMachineIPs(false)
+	c.Assert(err, check.IsNil)
+	c.Assert(assignments, check.HasLen, 2)
+
+	machine, err = app.GetMachineByID(3)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.IPAddresses.ToStringSlice(), check.DeepEquals, []string{"10.27.0.3"})
+
+	assignments, err = app.BackfillMachineIPs(false)
+	c.Assert(err, check.IsNil)
+	c.Assert(assignments, check.HasLen, 0)
+}
+
+func (s *Suite) TestImportMachine(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	existing := &Machine{
+		ID:          1,
+		MachineKey:  "foo",
+		Hostname:    "existing",
+		GivenName:   "existing",
+		NamespaceID: namespace.ID,
+		IPAddresses: MachineAddresses{netaddr.MustParseIP("10.27.0.1")},
+	}
+	app.db.Save(existing)
+
+	machineKey := MachinePublicKeyStripPrefix(key.NewMachine().Public())
+	expiry := time.Now().Add(time.Hour).Truncate(time.Second)
+	machine, ipAddressesChanged, err := app.ImportMachine(Machine{
+		MachineKey:    machineKey,
+		Hostname:      "imported",
+		GivenName:     "imported-1",
+		IPAddresses:   MachineAddresses{netaddr.MustParseIP("10.27.0.5")},
+		ForcedTags:    StringList{"tag:web"},
+		EnabledRoutes: IPPrefixes{netaddr.MustParseIPPrefix("10.0.0.0/24")},
+		Expiry:        &expiry,
+	}, namespace.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(ipAddressesChanged, check.Equals, false)
+
+	machineFromDB, err := app.GetMachineByID(machine.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(machineFromDB.MachineKey, check.Equals, machineKey)
+	c.Assert(machineFromDB.GivenName, check.Equals, "imported-1")
+	c.Assert(machineFromDB.IPAddresses.ToStringSlice(), check.DeepEquals, []string{"10.27.0.5"})
+	c.Assert(machineFromDB.ForcedTags, check.DeepEquals, StringList{"tag:web"})
+	c.Assert(machineFromDB.Expiry.Equal(expiry), check.Equals, true)
+
+	_, _, err = app.ImportMachine(Machine{MachineKey: machineKey}, namespace.Name)
+	c.Assert(errors.Is(err, errMachineKeyInUse), check.Equals, true)
+
+	machine, ipAddressesChanged, err = app.ImportMachine(Machine{
+		MachineKey:  MachinePublicKeyStripPrefix(key.NewMachine().Public()),
+		Hostname:    "conflicting",
+		IPAddresses: MachineAddresses{netaddr.MustParseIP("10.27.0.1")},
+	}, namespace.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(ipAddressesChanged, check.Equals, true)
+	c.Assert(machine.IPAddresses.ToStringSlice(), check.Not(check.DeepEquals), []string{"10.27.0.1"})
+
+	_, _, err = app.ImportMachine(Machine{
+		MachineKey: MachinePublicKeyStripPrefix(key.NewMachine().Public()),
+		Hostname:   "orphan",
+	}, "does-not-exist")
+	c.Assert(errors.Is(err, errNamespaceNotFound), check.Equals, true)
+
+	_, _, err = app.ImportMachine(Machine{MachineKey: "not-a-key"}, namespace.Name)
+	c.Assert(errors.Is(err, errInvalidMachineKey), check.Equals, true)
+}
+
+func (s *Suite) TestExpireIdleMachines(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	pak, err := app.CreatePreAuthKey(namespace.Name, false, false, nil)
+	c.Assert(err, check.IsNil)
+
+	lastSeenIdle := time.Now().Add(-48 * time.Hour)
+	lastSeenActive := time.Now().Add(-time.Hour)
+	for index, lastSeen := range []*time.Time{&lastSeenIdle, &lastSeenActive} {
+		machine := &Machine{
+			ID:             uint64(index + 1),
+			MachineKey:     "foo" + strconv.Itoa(index),
+			NodeKey:        "bar" + strconv.Itoa(index),
+			DiscoKey:       "faa" + strconv.Itoa(index),
+			Hostname:       "testmachine" + strconv.Itoa(index),
+			NamespaceID:    namespace.ID,
+			RegisterMethod: RegisterMethodAuthKey,
+			AuthKeyID:      uint(pak.ID),
+			LastSeen:       lastSeen,
+			CreatedAt:      time.Now().Add(-72 * time.Hour),
+		}
+		app.db.Save(machine)
+	}
+
+	// Without idle expiry, nothing is expired.
+	app.expireIdleMachinesWorker()
+	idle, err := app.GetMachine("test", "testmachine0")
+	c.Assert(err, check.IsNil)
+	c.Assert(idle.isExpired(), check.Equals, false)
+
+	_, err = app.SetNamespaceIdleExpiry("test", 24*time.Hour)
+	c.Assert(err, check.IsNil)
+
+	app.expireIdleMachinesWorker()
+
+	idle, err = app.GetMachine("test", "testmachine0")
+	c.Assert(err, check.IsNil)
+	c.Assert(idle.isExpired(), check.Equals, true)
+
+	active, err := app.GetMachine("test", "testmachine1")
+	c.Assert(err, check.IsNil)
+	c.Assert(active.isExpired(), check.Equals, false)
+}
+
+func (s *Suite) TestMachineIsIdleSince(c *check.C) {
+	now := time.Now()
+	lastSeen := now.Add(-time.Hour)
+
+	machine := Machine{CreatedAt: now.Add(-48 * time.Hour)}
+	c.Assert(machine.isIdleSince(now.Add(-24*time.Hour)), check.Equals, true)
+
+	machine.LastSeen = &lastSeen
+	c.Assert(machine.isIdleSince(now.Add(-24*time.Hour)), check.Equals, false)
+	c.Assert(machine.isIdleSince(now.Add(-time.Minute)), check.Equals, true)
+}
+
+func (s *Suite) TestQuarantineMachine(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	pak, err := app.CreatePreAuthKey(namespace.Name, false, false, nil)
+	c.Assert(err, check.IsNil)
+
+	for index := 1; index <= 3; index++ {
+		machine := Machine{
+			ID:             uint64(index),
+			MachineKey:     "foo" + strconv.Itoa(index),
+			NodeKey:        "bar" + strconv.Itoa(index),
+			DiscoKey:       "faa" + strconv.Itoa(index),
+			Hostname:       "testmachine" + strconv.Itoa(index),
+			NamespaceID:    namespace.ID,
+			RegisterMethod: RegisterMethodAuthKey,
+			AuthKeyID:      uint(pak.ID),
+		}
+		app.db.Save(&machine)
+	}
+
+	machine1, err := app.GetMachineByID(1)
+	c.Assert(err, check.IsNil)
+
+	machine2, err := app.GetMachineByID(2)
+	c.Assert(err, check.IsNil)
+
+	peers, err := app.getValidPeers(machine2)
+	c.Assert(err, check.IsNil)
+	c.Assert(len(peers), check.Equals, 2)
+
+	err = app.QuarantineMachine(machine1)
+	c.Assert(err, check.IsNil)
+
+	machine1, err = app.GetMachineByID(1)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine1.Quarantined, check.Equals, true)
+
+	peers, err = app.getValidPeers(machine1)
+	c.Assert(err, check.IsNil)
+	c.Assert(len(peers), check.Equals, 0)
+
+	peers, err = app.getValidPeers(machine2)
+	c.Assert(err, check.IsNil)
+	c.Assert(len(peers), check.Equals, 1)
+	c.Assert(peers[0].Hostname, check.Equals, "testmachine3")
+
+	err = app.UnquarantineMachine(machine1)
+	c.Assert(err, check.IsNil)
+
+	peers, err = app.getValidPeers(machine1)
+	c.Assert(err, check.IsNil)
+	c.Assert(len(peers), check.Equals, 2)
+}
+
+func (s *Suite) TestRegisterMachineWithLabels(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	app.registrationCache = cache.New(
+		registerCacheExpiration,
+		registerCacheCleanup,
+	)
+	app.registrationCache.Set(
+		"foo",
+		Machine{
+			MachineKey: "foo",
+			NodeKey:    "bar",
+			DiscoKey:   "faa",
+			Hostname:   "testmachine",
+			GivenName:  "testmachine",
+		},
+		registerCacheExpiration,
+	)
+
+	_, err = app.RegisterMachineFromAuthCallback(
+		"foo",
+		namespace.Name,
+		RegisterMethodCLI,
+		map[string]string{"env prod": "yes"},
+		nil,
+	)
+	c.Assert(errors.Is(err, errInvalidLabel), check.Equals, true)
+
+	_, err = app.RegisterMachineFromAuthCallback(
+		"foo",
+		namespace.Name,
+		RegisterMethodCLI,
+		map[string]string{"env": "prod", "team": "infra"},
+		nil,
+	)
+	c.Assert(err, check.IsNil)
+
+	machine, err := app.GetMachine("test", "testmachine")
+	c.Assert(err, check.IsNil)
+	c.Assert(
+		map[string]string(machine.Labels),
+		check.DeepEquals,
+		map[string]string{"env": "prod", "team": "infra"},
+	)
+	c.Assert(machine.toProto().GetLabels()["team"], check.Equals, "infra")
+}
+
+func (s *Suite) TestMachineToProtoInventory(c *check.C) {
+	machine := Machine{
+		HostInfo: HostInfo{
+			OS:        "linux",
+			OSVersion: "Debian 11.3 (bullseye); kernel=5.10.0-14-arm64",
+			GoArch:    "arm64",
+			Package:   "tarball",
+		},
+		Endpoints: StringList{"203.0.113.7:41641", "192.168.1.10:41641"},
+	}
+
+	machineProto := machine.toProto()
+	c.Assert(machineProto.GetOs(), check.Equals, "linux")
+	c.Assert(machineProto.GetOsVersion(), check.Equals, "Debian 11.3 (bullseye); kernel=5.10.0-14-arm64")
+	c.Assert(machineProto.GetArch(), check.Equals, "arm64")
+	c.Assert(machineProto.GetDeviceModel(), check.Equals, "")
+	c.Assert(machineProto.GetClientPackage(), check.Equals, "tarball")
+	c.Assert(
+		machineProto.GetEndpoints(),
+		check.DeepEquals,
+		[]string{"203.0.113.7:41641", "192.168.1.10:41641"},
+	)
+}
+
+func (s *Suite) TestMachineToProtoEphemeral(c *check.C) {
+	machine := Machine{}
+	c.Assert(machine.toProto().GetEphemeral(), check.Equals, false)
+
+	machine.AuthKey = &PreAuthKey{Ephemeral: true}
+	c.Assert(machine.toProto().GetEphemeral(), check.Equals, true)
+}
+
+func (s *Suite) TestAdvertisedRoutesChanged(c *check.C) {
+	lan := netaddr.MustParseIPPrefix("192.168.1.0/24")
+	office := netaddr.MustParseIPPrefix("10.10.0.0/16")
+
+	machine := Machine{}
+	c.Assert(machine.advertisedRoutesChanged(nil), check.Equals, false)
+	c.Assert(machine.toProto().GetRoutesUpdated(), check.IsNil)
+
+	machine.HostInfo.RoutableIPs = []netaddr.IPPrefix{lan, office}
+	c.Assert(
+		machine.advertisedRoutesChanged([]netaddr.IPPrefix{office, lan}),
+		check.Equals,
+		false,
+	)
+	c.Assert(machine.advertisedRoutesChanged([]netaddr.IPPrefix{lan}), check.Equals, true)
+	c.Assert(machine.advertisedRoutesChanged(nil), check.Equals, true)
+
+	updated := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+	machine.RoutesUpdatedAt = &updated
+	c.Assert(machine.toProto().GetRoutesUpdated().AsTime(), check.Equals, updated)
+}
+
 func (s *Suite) TestSerdeAddressStrignSlice(c *check.C) {
 	input := MachineAddresses([]netaddr.IP{
 		netaddr.MustParseIP("192.0.2.1"),
@@ -827,6 +1343,7 @@ func TestHeadscale_GenerateGivenName(t *testing.T) {
 					OIDC: OIDCConfig{
 						StripEmaildomain: true,
 					},
+					NormalizeNames: true,
 				},
 			},
 			args: args{
@@ -842,6 +1359,7 @@ func TestHeadscale_GenerateGivenName(t *testing.T) {
 					OIDC: OIDCConfig{
 						StripEmaildomain: true,
 					},
+					NormalizeNames: true,
 				},
 			},
 			args: args{
@@ -857,6 +1375,7 @@ func TestHeadscale_GenerateGivenName(t *testing.T) {
 					OIDC: OIDCConfig{
 						StripEmaildomain: true,
 					},
+					NormalizeNames: true,
 				},
 			},
 			args: args{
@@ -872,6 +1391,7 @@ func TestHeadscale_GenerateGivenName(t *testing.T) {
 					OIDC: OIDCConfig{
 						StripEmaildomain: true,
 					},
+					NormalizeNames: true,
 				},
 			},
 			args: args{
@@ -887,6 +1407,7 @@ func TestHeadscale_GenerateGivenName(t *testing.T) {
 					OIDC: OIDCConfig{
 						StripEmaildomain: true,
 					},
+					NormalizeNames: true,
 				},
 			},
 			args: args{
@@ -902,6 +1423,7 @@ func TestHeadscale_GenerateGivenName(t *testing.T) {
 					OIDC: OIDCConfig{
 						StripEmaildomain: true,
 					},
+					NormalizeNames: true,
 				},
 			},
 			args: args{
@@ -910,6 +1432,85 @@ func TestHeadscale_GenerateGivenName(t *testing.T) {
 			want:    "",
 			wantErr: true,
 		},
+		{
+			name: "mixed case machine name",
+			h: &Headscale{
+				cfg: &Config{
+					NormalizeNames: true,
+				},
+			},
+			args: args{
+				suppliedName: "DESKTOP-AB12CD",
+			},
+			want:    "desktop-ab12cd",
+			wantErr: false,
+		},
+		{
+			name: "machine name with special chars",
+			h: &Headscale{
+				cfg: &Config{
+					NormalizeNames: true,
+				},
+			},
+			args: args{
+				suppliedName: "Jamie's MacBook Pro (2).local",
+			},
+			want:    "jamies-macbook-pro-2-.local",
+			wantErr: false,
+		},
+		{
+			name: "machine name with special chars as a single label",
+			h: &Headscale{
+				cfg: &Config{
+					NormalizeNames:   true,
+					SingleLabelNames: true,
+				},
+			},
+			args: args{
+				suppliedName: "Jamie's MacBook Pro (2).local",
+			},
+			want:    "jamies-macbook-pro-2-local",
+			wantErr: false,
+		},
+		{
+			name: "mixed case machine name without normalization",
+			h: &Headscale{
+				cfg: &Config{
+					NormalizeNames: false,
+				},
+			},
+			args: args{
+				suppliedName: "DESKTOP-AB12CD",
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "machine name with dots without normalization",
+			h: &Headscale{
+				cfg: &Config{
+					NormalizeNames: false,
+				},
+			},
+			args: args{
+				suppliedName: "testmachine.local",
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "valid machine name without normalization",
+			h: &Headscale{
+				cfg: &Config{
+					NormalizeNames: false,
+				},
+			},
+			args: args{
+				suppliedName: "testmachine",
+			},
+			want:    "testmachine",
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -924,11 +1525,11 @@ func TestHeadscale_GenerateGivenName(t *testing.T) {
 				return
 			}
 
-			if tt.want != "" && strings.Contains(tt.want, got) {
+			if tt.want != "" && !strings.HasPrefix(got, tt.want) {
 				t.Errorf(
-					"Headscale.GenerateGivenName() = %v, is not a substring of %v",
-					tt.want,
+					"Headscale.GenerateGivenName() = %v, does not start with %v",
 					got,
+					tt.want,
 				)
 			}
 
@@ -942,3 +1543,137 @@ func TestHeadscale_GenerateGivenName(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector string
+		want     map[string]string
+		wantErr  bool
+	}{
+		{
+			name:     "single requirement",
+			selector: "env=staging",
+			want:     map[string]string{"env": "staging"},
+		},
+		{
+			name:     "several requirements",
+			selector: "env=staging, team=web",
+			want:     map[string]string{"env": "staging", "team": "web"},
+		},
+		{
+			name:     "empty value",
+			selector: "env=",
+			want:     map[string]string{"env": ""},
+		},
+		{
+			name:     "missing value",
+			selector: "env",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid key",
+			selector: "=staging",
+			wantErr:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseLabelSelector(test.selector)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseLabelSelector() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if !test.wantErr && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseLabelSelector() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func Test_filterMachinesByLabels(t *testing.T) {
+	machines := []Machine{
+		{ID: 1, Labels: StringMap{"env": "staging", "team": "web"}},
+		{ID: 2, Labels: StringMap{"env": "staging"}},
+		{ID: 3, Labels: StringMap{"env": "prod", "team": "web"}},
+		{ID: 4},
+	}
+
+	ids := func(machines []Machine) []uint64 {
+		result := []uint64{}
+		for _, machine := range machines {
+			result = append(result, machine.ID)
+		}
+
+		return result
+	}
+
+	got := ids(filterMachinesByLabels(machines, map[string]string{"env": "staging"}))
+	if !reflect.DeepEqual(got, []uint64{1, 2}) {
+		t.Errorf("filterMachinesByLabels(env=staging) = %v, want [1 2]", got)
+	}
+
+	got = ids(filterMachinesByLabels(
+		machines,
+		map[string]string{"env": "staging", "team": "web"},
+	))
+	if !reflect.DeepEqual(got, []uint64{1}) {
+		t.Errorf("filterMachinesByLabels(env=staging,team=web) = %v, want [1]", got)
+	}
+
+	got = ids(filterMachinesByLabels(machines, map[string]string{"env": "dev"}))
+	if len(got) != 0 {
+		t.Errorf("filterMachinesByLabels(env=dev) = %v, want none", got)
+	}
+}
+
+func (s *Suite) TestMachineNetmapUpToDate(c *check.C) {
+	machine := Machine{ID: 1, Hostname: "testmachine"}
+	c.Assert(app.machineNetmapUpToDate(&machine), check.Equals, false)
+
+	// Nothing changed since the start.
+	version := app.netmapVersion()
+	c.Assert(version.IsZero(), check.Equals, true)
+	machine.NetmapVersion = &version
+	c.Assert(app.machineNetmapUpToDate(&machine), check.Equals, true)
+
+	app.setLastStateChangeToNow("test")
+	c.Assert(app.machineNetmapUpToDate(&machine), check.Equals, false)
+
+	latest := app.netmapVersion()
+	machine.NetmapVersion = &latest
+	c.Assert(app.machineNetmapUpToDate(&machine), check.Equals, true)
+}
+
+func (s *Suite) TestRenameMachine(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	for index, name := range []string{"web-1", "web-2"} {
+		machine := Machine{
+			ID:          uint64(index + 1),
+			MachineKey:  "foo" + name,
+			NodeKey:     "bar" + name,
+			DiscoKey:    "faa" + name,
+			Hostname:    name,
+			GivenName:   name,
+			NamespaceID: namespace.ID,
+		}
+		app.db.Save(&machine)
+	}
+
+	machine, err := app.GetMachineByID(2)
+	c.Assert(err, check.IsNil)
+
+	err = app.RenameMachine(machine, "web.2")
+	c.Assert(errors.Is(err, errInvalidDNSLabel), check.Equals, true)
+
+	err = app.RenameMachine(machine, "web-1")
+	c.Assert(errors.Is(err, errMachineNameInUse), check.Equals, true)
+
+	err = app.RenameMachine(machine, "web-2")
+	c.Assert(err, check.IsNil)
+
+	err = app.RenameMachine(machine, "api")
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.GivenName, check.Equals, "api")
+}
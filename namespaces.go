@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"gorm.io/gorm"
 	"tailscale.com/tailcfg"
@@ -20,14 +22,30 @@ const (
 	errNamespaceNotFound        = Error("Namespace not found")
 	errNamespaceNotEmptyOfNodes = Error("Namespace not empty: node(s) found")
 	errInvalidNamespaceName     = Error("Invalid namespace name")
+	errInvalidDNSDomain         = Error("Invalid DNS domain")
+	errInvalidDNSLabel          = Error("Invalid DNS label")
+	errInvalidIdleExpiry        = Error("Idle expiry must not be negative")
+	errInvalidMTU               = Error("MTU must be between 1280 and 9000, or 0 to unset it")
+	errNamespaceLocked          = Error("Namespace locked: new nodes cannot register")
+)
+
+const (
+	// 1280 is the minimum MTU of IPv6, and the default of the Tailscale
+	// clients.
+	minNamespaceMTU = 1280
+	maxNamespaceMTU = 9000
 )
 
 const (
 	// value related to RFC 1123 and 952.
 	labelHostnameLength = 63
+	domainNameLength    = 253
 )
 
-var invalidCharsInNamespaceRegex = regexp.MustCompile("[^a-z0-9-.]+")
+var (
+	invalidCharsInNamespaceRegex = regexp.MustCompile("[^a-z0-9-.]+")
+	dnsLabelRegex                = regexp.MustCompile("^[a-z0-9]([a-z0-9-]*[a-z0-9])?$")
+)
 
 // Namespace is the way Headscale implements the concept of users in Tailscale
 //
@@ -36,6 +54,22 @@ var invalidCharsInNamespaceRegex = regexp.MustCompile("[^a-z0-9-.]+")
 type Namespace struct {
 	gorm.Model
 	Name string `gorm:"unique"`
+
+	// DNSDomain overrides the MagicDNS domain ([name].[base_domain]) of
+	// the machines in this namespace when set.
+	DNSDomain string
+
+	// IdleExpiry expires the machines of the namespace not seen for this
+	// long, 0 to disable.
+	IdleExpiry time.Duration
+
+	// MTU is the MTU the machines of the namespace should use, 0 when
+	// unset.
+	MTU int
+
+	// Locked prevents new machines from registering in the namespace,
+	// the registered ones are not affected.
+	Locked bool
 }
 
 // CreateNamespace creates a new Namespace. Returns error if could not be created
@@ -78,6 +112,13 @@ func (h *Headscale) DestroyNamespace(name string) error {
 		return errNamespaceNotEmptyOfNodes
 	}
 
+	// Deleted machines waiting to be purged still reference the namespace.
+	if err := h.db.Unscoped().
+		Where("namespace_id = ?", namespace.ID).
+		Delete(&Machine{}).Error; err != nil {
+		return err
+	}
+
 	keys, err := h.ListPreAuthKeys(name)
 	if err != nil {
 		return err
@@ -96,6 +137,65 @@ func (h *Headscale) DestroyNamespace(name string) error {
 	return nil
 }
 
+// NamespaceImpact describes what would be removed along with a Namespace.
+type NamespaceImpact struct {
+	Machines    []NamespaceImpactMachine
+	PreAuthKeys []PreAuthKey
+}
+
+// NamespaceImpactMachine is a machine owned by a Namespace, along with the
+// other namespaces it is visible from.
+type NamespaceImpactMachine struct {
+	Machine    Machine
+	SharedWith []string
+}
+
+// GetNamespaceImpact lists the machines, the machines visible from other
+// namespaces, the pre auth keys and the enabled routes of a Namespace.
+func (h *Headscale) GetNamespaceImpact(name string) (*NamespaceImpact, error) {
+	_, err := h.GetNamespace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	machines, err := h.ListMachinesInNamespace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	impact := NamespaceImpact{
+		Machines: make([]NamespaceImpactMachine, len(machines)),
+	}
+
+	for index, machine := range machines {
+		machine := machine
+		peers, err := h.getPeers(&machine)
+		if err != nil {
+			return nil, err
+		}
+
+		sharedWith := []string{}
+		for _, peer := range peers {
+			if peer.Namespace.Name != name && !contains(sharedWith, peer.Namespace.Name) {
+				sharedWith = append(sharedWith, peer.Namespace.Name)
+			}
+		}
+		sort.Strings(sharedWith)
+
+		impact.Machines[index] = NamespaceImpactMachine{
+			Machine:    machine,
+			SharedWith: sharedWith,
+		}
+	}
+
+	impact.PreAuthKeys, err = h.ListPreAuthKeys(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &impact, nil
+}
+
 // RenameNamespace renames a Namespace. Returns error if the Namespace does
 // not exist or if another Namespace exists with the new name.
 func (h *Headscale) RenameNamespace(oldName, newName string) error {
@@ -125,6 +225,94 @@ func (h *Headscale) RenameNamespace(oldName, newName string) error {
 	return nil
 }
 
+// SetNamespaceDNSDomain sets the MagicDNS domain override of a Namespace.
+// An empty domain removes the override.
+func (h *Headscale) SetNamespaceDNSDomain(name string, domain string) (*Namespace, error) {
+	namespace, err := h.GetNamespace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	domain = strings.TrimSuffix(domain, ".")
+	if domain != "" {
+		err = CheckForDNSDomainRules(domain)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	namespace.DNSDomain = domain
+
+	if result := h.db.Save(&namespace); result.Error != nil {
+		return nil, result.Error
+	}
+
+	// Shared machines show up in other namespaces' netmaps with their
+	// FQDN, so every namespace needs to be updated.
+	h.setLastStateChangeToNow()
+
+	return namespace, nil
+}
+
+// SetNamespaceIdleExpiry sets the idle expiry of a Namespace, 0 disables it.
+func (h *Headscale) SetNamespaceIdleExpiry(
+	name string,
+	idleExpiry time.Duration,
+) (*Namespace, error) {
+	if idleExpiry < 0 {
+		return nil, errInvalidIdleExpiry
+	}
+
+	namespace, err := h.GetNamespace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace.IdleExpiry = idleExpiry
+
+	if result := h.db.Save(&namespace); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return namespace, nil
+}
+
+// SetNamespaceMTU sets the MTU of a Namespace, 0 unsets it.
+func (h *Headscale) SetNamespaceMTU(name string, mtu int) (*Namespace, error) {
+	if mtu != 0 && (mtu < minNamespaceMTU || mtu > maxNamespaceMTU) {
+		return nil, errInvalidMTU
+	}
+
+	namespace, err := h.GetNamespace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace.MTU = mtu
+
+	if result := h.db.Save(&namespace); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return namespace, nil
+}
+
+// SetNamespaceLocked locks or unlocks a Namespace for new registrations.
+func (h *Headscale) SetNamespaceLocked(name string, locked bool) (*Namespace, error) {
+	namespace, err := h.GetNamespace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	namespace.Locked = locked
+
+	if result := h.db.Save(&namespace); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return namespace, nil
+}
+
 // GetNamespace fetches a namespace by name.
 func (h *Headscale) GetNamespace(name string) (*Namespace, error) {
 	namespace := Namespace{}
@@ -164,7 +352,10 @@ func (h *Headscale) ListNamespacesStr() ([]string, error) {
 }
 
 // ListMachinesInNamespace gets all the nodes in a given namespace.
-func (h *Headscale) ListMachinesInNamespace(name string) ([]Machine, error) {
+func (h *Headscale) ListMachinesInNamespace(
+	name string,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]Machine, error) {
 	err := CheckForFQDNRules(name)
 	if err != nil {
 		return nil, err
@@ -175,13 +366,33 @@ func (h *Headscale) ListMachinesInNamespace(name string) ([]Machine, error) {
 	}
 
 	machines := []Machine{}
-	if err := h.db.Preload("AuthKey").Preload("AuthKey.Namespace").Preload("Namespace").Where(&Machine{NamespaceID: namespace.ID}).Find(&machines).Error; err != nil {
+	if err := h.db.Scopes(scopes...).Preload("AuthKey").Preload("AuthKey.Namespace").Preload("Namespace").Where(&Machine{NamespaceID: namespace.ID}).Find(&machines).Error; err != nil {
 		return nil, err
 	}
 
 	return machines, nil
 }
 
+// ListDeletedMachinesInNamespace gets the deleted machines of a namespace,
+// filtered in the database so that the scopes page them after the filter.
+func (h *Headscale) ListDeletedMachinesInNamespace(
+	name string,
+	scopes ...func(*gorm.DB) *gorm.DB,
+) ([]Machine, error) {
+	err := CheckForFQDNRules(name)
+	if err != nil {
+		return nil, err
+	}
+	namespace, err := h.GetNamespace(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.ListDeletedMachines(append(scopes, func(db *gorm.DB) *gorm.DB {
+		return db.Where(&Machine{NamespaceID: namespace.ID})
+	})...)
+}
+
 // SetMachineNamespace assigns a Machine to a namespace.
 func (h *Headscale) SetMachineNamespace(machine *Machine, namespaceName string) error {
 	err := CheckForFQDNRules(namespaceName)
@@ -247,11 +458,51 @@ func getMapResponseUserProfiles(machine Machine, peers Machines) []tailcfg.UserP
 }
 
 func (n *Namespace) toProto() *v1.Namespace {
-	return &v1.Namespace{
+	namespaceProto := &v1.Namespace{
 		Id:        strconv.FormatUint(uint64(n.ID), Base10),
 		Name:      n.Name,
 		CreatedAt: timestamppb.New(n.CreatedAt),
+		DnsDomain: n.DNSDomain,
+		Mtu:       uint32(n.MTU),
+		Locked:    n.Locked,
+	}
+
+	if n.IdleExpiry > 0 {
+		namespaceProto.IdleExpiry = durationpb.New(n.IdleExpiry)
+	}
+
+	return namespaceProto
+}
+
+func (impact *NamespaceImpact) toProto() *v1.GetNamespaceImpactResponse {
+	response := v1.GetNamespaceImpactResponse{
+		Machines:    make([]*v1.NamespaceImpactMachine, len(impact.Machines)),
+		PreAuthKeys: make([]*v1.PreAuthKey, len(impact.PreAuthKeys)),
 	}
+
+	for index, machine := range impact.Machines {
+		response.Machines[index] = &v1.NamespaceImpactMachine{
+			Machine:       machine.Machine.toProto(),
+			SharedWith:    machine.SharedWith,
+			EnabledRoutes: ipPrefixToString(machine.Machine.GetEnabledRoutes()),
+		}
+	}
+
+	for index, key := range impact.PreAuthKeys {
+		response.PreAuthKeys[index] = key.toProto()
+	}
+
+	return &response
+}
+
+// magicDNSDomain returns the domain under which the machines of the namespace
+// are reachable with MagicDNS.
+func (n *Namespace) magicDNSDomain(baseDomain string) string {
+	if n.DNSDomain != "" {
+		return n.DNSDomain
+	}
+
+	return fmt.Sprintf("%s.%s", n.Name, baseDomain)
 }
 
 // NormalizeToFQDNRules will replace forbidden chars in namespace
@@ -305,3 +556,59 @@ func CheckForFQDNRules(name string) error {
 
 	return nil
 }
+
+// CheckForDNSLabelRules validates that label is a single RFC 1123 label,
+// as the given names of the machines.
+func CheckForDNSLabelRules(label string) error {
+	if len(label) > labelHostnameLength {
+		return fmt.Errorf(
+			"DNS label must not be over 63 chars. %v doesn't comply with this rule: %w",
+			label,
+			errInvalidDNSLabel,
+		)
+	}
+	if !dnsLabelRegex.MatchString(label) {
+		return fmt.Errorf(
+			"DNS label should be lowercase ASCII letters, numbers or hyphens, not starting or ending with a hyphen. %q doesn't comply with these rules: %w",
+			label,
+			errInvalidDNSLabel,
+		)
+	}
+
+	return nil
+}
+
+// CheckForDNSDomainRules validates that domain is a fully qualified domain
+// name made of RFC 1123 labels.
+func CheckForDNSDomainRules(domain string) error {
+	if len(domain) > domainNameLength {
+		return fmt.Errorf(
+			"DNS domain must not be over %d chars. %v doesn't comply with this rule: %w",
+			domainNameLength,
+			domain,
+			errInvalidDNSDomain,
+		)
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < expectedTokenItems {
+		return fmt.Errorf(
+			"DNS domain should be composed of at least two labels. %v doesn't comply with this rule: %w",
+			domain,
+			errInvalidDNSDomain,
+		)
+	}
+
+	for _, label := range labels {
+		if len(label) > labelHostnameLength || !dnsLabelRegex.MatchString(label) {
+			return fmt.Errorf(
+				"DNS label %q of %v should be 1 to 63 lowercase ASCII letters, numbers or hyphens, not starting or ending with a hyphen: %w",
+				label,
+				domain,
+				errInvalidDNSDomain,
+			)
+		}
+	}
+
+	return nil
+}
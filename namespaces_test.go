@@ -1,8 +1,11 @@
 package headscale
 
 import (
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/patrickmn/go-cache"
 	"gopkg.in/check.v1"
 	"gorm.io/gorm"
 	"inet.af/netaddr"
@@ -92,6 +95,55 @@ func (s *Suite) TestRenameNamespace(c *check.C) {
 	c.Assert(err, check.Equals, errNamespaceExists)
 }
 
+func (s *Suite) TestGetNamespaceImpact(c *check.C) {
+	namespaceTest, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	namespaceOther, err := app.CreateNamespace("other")
+	c.Assert(err, check.IsNil)
+
+	pak, err := app.CreatePreAuthKey(namespaceTest.Name, false, false, nil)
+	c.Assert(err, check.IsNil)
+
+	route := netaddr.MustParseIPPrefix("10.0.0.0/24")
+	machine := Machine{
+		ID:             1,
+		MachineKey:     "foo",
+		NodeKey:        "bar",
+		DiscoKey:       "faa",
+		Hostname:       "testmachine",
+		NamespaceID:    namespaceTest.ID,
+		RegisterMethod: RegisterMethodAuthKey,
+		AuthKeyID:      uint(pak.ID),
+		EnabledRoutes:  IPPrefixes{route},
+	}
+	app.db.Save(&machine)
+
+	otherMachine := Machine{
+		ID:             2,
+		MachineKey:     "foo2",
+		NodeKey:        "bar2",
+		DiscoKey:       "faa2",
+		Hostname:       "othermachine",
+		NamespaceID:    namespaceOther.ID,
+		RegisterMethod: RegisterMethodCLI,
+	}
+	app.db.Save(&otherMachine)
+
+	impact, err := app.GetNamespaceImpact("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(impact.Machines, check.HasLen, 1)
+	c.Assert(impact.Machines[0].Machine.Hostname, check.Equals, "testmachine")
+	c.Assert(impact.Machines[0].SharedWith, check.DeepEquals, []string{"other"})
+	c.Assert(impact.PreAuthKeys, check.HasLen, 1)
+
+	response := impact.toProto()
+	c.Assert(response.Machines[0].EnabledRoutes, check.DeepEquals, []string{route.String()})
+
+	_, err = app.GetNamespaceImpact("does-not-exist")
+	c.Assert(err, check.Equals, errNamespaceNotFound)
+}
+
 func (s *Suite) TestGetMapResponseUserProfiles(c *check.C) {
 	namespaceShared1, err := app.CreateNamespace("shared1")
 	c.Assert(err, check.IsNil)
@@ -373,6 +425,154 @@ func TestCheckForFQDNRules(t *testing.T) {
 	}
 }
 
+func TestCheckForDNSDomainRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		domain  string
+		wantErr bool
+	}{
+		{
+			name:    "valid: domain",
+			domain:  "tenant1.example.com",
+			wantErr: false,
+		},
+		{
+			name:    "invalid: single label",
+			domain:  "example",
+			wantErr: true,
+		},
+		{
+			name:    "invalid: empty label",
+			domain:  "tenant1..example.com",
+			wantErr: true,
+		},
+		{
+			name:    "invalid: capitalized domain",
+			domain:  "Tenant1.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "invalid: label starting with hyphen",
+			domain:  "-tenant1.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "invalid: chars in domain",
+			domain:  "tenant_1.example.com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckForDNSDomainRules(tt.domain); (err != nil) != tt.wantErr {
+				t.Errorf("CheckForDNSDomainRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func (s *Suite) TestSetNamespaceDNSDomain(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.magicDNSDomain("example.com"), check.Equals, "test.example.com")
+
+	namespace, err = app.SetNamespaceDNSDomain("test", "tenant1.example.com.")
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.DNSDomain, check.Equals, "tenant1.example.com")
+
+	namespace, err = app.GetNamespace("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.magicDNSDomain("example.com"), check.Equals, "tenant1.example.com")
+
+	_, err = app.SetNamespaceDNSDomain("test", "not a domain")
+	c.Assert(errors.Is(err, errInvalidDNSDomain), check.Equals, true)
+
+	_, err = app.SetNamespaceDNSDomain("does-not-exist", "tenant1.example.com")
+	c.Assert(err, check.Equals, errNamespaceNotFound)
+
+	namespace, err = app.SetNamespaceDNSDomain("test", "")
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.magicDNSDomain("example.com"), check.Equals, "test.example.com")
+}
+
+func (s *Suite) TestSetNamespaceIdleExpiry(c *check.C) {
+	_, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	namespace, err := app.SetNamespaceIdleExpiry("test", 30*24*time.Hour)
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.IdleExpiry, check.Equals, 30*24*time.Hour)
+
+	namespace, err = app.GetNamespace("test")
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.IdleExpiry, check.Equals, 30*24*time.Hour)
+
+	_, err = app.SetNamespaceIdleExpiry("test", -time.Hour)
+	c.Assert(err, check.Equals, errInvalidIdleExpiry)
+
+	_, err = app.SetNamespaceIdleExpiry("does-not-exist", time.Hour)
+	c.Assert(err, check.Equals, errNamespaceNotFound)
+
+	namespace, err = app.SetNamespaceIdleExpiry("test", 0)
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.IdleExpiry, check.Equals, time.Duration(0))
+}
+
+func (s *Suite) TestSetNamespaceMTU(c *check.C) {
+	_, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	namespace, err := app.SetNamespaceMTU("test", 1280)
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.MTU, check.Equals, 1280)
+	c.Assert(namespace.toProto().GetMtu(), check.Equals, uint32(1280))
+
+	_, err = app.SetNamespaceMTU("test", 576)
+	c.Assert(err, check.Equals, errInvalidMTU)
+
+	_, err = app.SetNamespaceMTU("test", 9001)
+	c.Assert(err, check.Equals, errInvalidMTU)
+
+	namespace, err = app.SetNamespaceMTU("test", 0)
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.MTU, check.Equals, 0)
+}
+
+func (s *Suite) TestSetNamespaceLocked(c *check.C) {
+	_, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	app.registrationCache = cache.New(
+		registerCacheExpiration,
+		registerCacheCleanup,
+	)
+	app.registrationCache.Set(
+		"foo",
+		Machine{
+			MachineKey: "foo",
+			NodeKey:    "bar",
+			DiscoKey:   "faa",
+			Hostname:   "testmachine",
+			GivenName:  "testmachine",
+		},
+		registerCacheExpiration,
+	)
+
+	namespace, err := app.SetNamespaceLocked("test", true)
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.toProto().GetLocked(), check.Equals, true)
+
+	_, err = app.RegisterMachineFromAuthCallback("foo", "test", RegisterMethodCLI, nil, nil)
+	c.Assert(err, check.Equals, errNamespaceLocked)
+
+	namespace, err = app.SetNamespaceLocked("test", false)
+	c.Assert(err, check.IsNil)
+	c.Assert(namespace.Locked, check.Equals, false)
+
+	_, err = app.RegisterMachineFromAuthCallback("foo", "test", RegisterMethodCLI, nil, nil)
+	c.Assert(err, check.IsNil)
+}
+
 func (s *Suite) TestSetMachineNamespace(c *check.C) {
 	oldNamespace, err := app.CreateNamespace("old")
 	c.Assert(err, check.IsNil)
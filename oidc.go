@@ -116,6 +116,18 @@ type oidcCallbackTemplateConfig struct {
 	Verb string
 }
 
+var oidcApprovalTemplate = template.Must(
+	template.New("oidcapproval").Parse(`<html>
+	<body>
+	<h1>headscale</h1>
+	<p>
+			Authenticated as {{.User}}, the machine will be connected once an administrator approves it.
+			You can now close this window.
+	</p>
+	</body>
+	</html>`),
+)
+
 var oidcCallbackTemplate = template.Must(
 	template.New("oidccallback").Parse(`<html>
 	<body>
@@ -457,11 +469,32 @@ func (h *Headscale) OIDCCallback(
 
 	machineKeyStr := MachinePublicKeyStripPrefix(machineKey)
 
+	if h.cfg.OIDC.RequireApproval {
+		h.requestApprovalFromOIDCCallback(writer, machineKeyStr, claims.Email, namespace.Name)
+
+		return
+	}
+
 	_, err = h.RegisterMachineFromAuthCallback(
 		machineKeyStr,
 		namespace.Name,
 		RegisterMethodOIDC,
+		nil,
+		nil,
 	)
+	if errors.Is(err, errNamespaceLocked) {
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		writer.WriteHeader(http.StatusForbidden)
+		_, err := writer.Write([]byte(errNamespaceLocked.Error()))
+		if err != nil {
+			log.Error().
+				Caller().
+				Err(err).
+				Msg("Failed to write response")
+		}
+
+		return
+	}
 	if err != nil {
 		log.Error().
 			Caller().
@@ -514,3 +547,69 @@ func (h *Headscale) OIDCCallback(
 			Msg("Failed to write response")
 	}
 }
+
+// requestApprovalFromOIDCCallback puts the machine on hold until an
+// administrator approves its registration.
+func (h *Headscale) requestApprovalFromOIDCCallback(
+	writer http.ResponseWriter,
+	machineKeyStr string,
+	email string,
+	namespaceName string,
+) {
+	err := h.requestRegistrationApproval(machineKeyStr, email, namespaceName)
+	if err != nil {
+		log.Error().
+			Caller().
+			Err(err).
+			Msg("could not request the approval of the machine")
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		writer.WriteHeader(http.StatusInternalServerError)
+		_, err := writer.Write([]byte("could not request the approval of the machine"))
+		if err != nil {
+			log.Error().
+				Caller().
+				Err(err).
+				Msg("Failed to write response")
+		}
+
+		return
+	}
+
+	log.Info().
+		Str("machine_key", machineKeyStr).
+		Str("email", email).
+		Msg("Machine registration waiting for approval")
+
+	var content bytes.Buffer
+	if err := oidcApprovalTemplate.Execute(&content, oidcCallbackTemplateConfig{
+		User: email,
+	}); err != nil {
+		log.Error().
+			Str("func", "OIDCCallback").
+			Str("type", "approval").
+			Err(err).
+			Msg("Could not render OIDC approval template")
+
+		writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		writer.WriteHeader(http.StatusInternalServerError)
+		_, err := writer.Write([]byte("Could not render OIDC approval template"))
+		if err != nil {
+			log.Error().
+				Caller().
+				Err(err).
+				Msg("Failed to write response")
+		}
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	writer.WriteHeader(http.StatusOK)
+	_, err = writer.Write(content.Bytes())
+	if err != nil {
+		log.Error().
+			Caller().
+			Err(err).
+			Msg("Failed to write response")
+	}
+}
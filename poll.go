@@ -100,10 +100,14 @@ func (h *Headscale) PollNetMapHandler(
 		Str("machine", machine.Hostname).
 		Msg("Found machine in database")
 
+	now := time.Now().UTC()
+	if machine.advertisedRoutesChanged(mapRequest.Hostinfo.RoutableIPs) {
+		machine.RoutesUpdatedAt = &now
+	}
+
 	machine.Hostname = mapRequest.Hostinfo.Hostname
 	machine.HostInfo = HostInfo(*mapRequest.Hostinfo)
 	machine.DiscoKey = DiscoPublicKeyStripPrefix(mapRequest.DiscoKey)
-	now := time.Now().UTC()
 
 	// update ACLRules with peer informations (to update server tags if necessary)
 	if h.aclPolicy != nil {
@@ -143,6 +147,7 @@ func (h *Headscale) PollNetMapHandler(
 		}
 	}
 
+	netmapVersion := h.netmapVersion()
 	data, err := h.getMapResponse(machineKey, mapRequest, machine)
 	if err != nil {
 		log.Error().
@@ -246,6 +251,7 @@ func (h *Headscale) PollNetMapHandler(
 		Str("handler", "PollNetMap").
 		Str("machine", machine.Hostname).
 		Msg("Client is ready to access the tailnet")
+	h.recordConnectionEvent(machine, ConnectionEventConnect, req.RemoteAddr, time.Now().UTC())
 	log.Info().
 		Str("handler", "PollNetMap").
 		Str("machine", machine.Hostname).
@@ -266,6 +272,7 @@ func (h *Headscale) PollNetMapHandler(
 		machine,
 		mapRequest,
 		machineKey,
+		netmapVersion,
 		pollDataChan,
 		keepAliveChan,
 		updateChan,
@@ -286,6 +293,7 @@ func (h *Headscale) PollNetMapStream(
 	machine *Machine,
 	mapRequest tailcfg.MapRequest,
 	machineKey key.MachinePublic,
+	netmapVersion time.Time,
 	pollDataChan chan []byte,
 	keepAliveChan chan []byte,
 	updateChan chan struct{},
@@ -378,6 +386,7 @@ func (h *Headscale) PollNetMapStream(
 			lastStateUpdate.WithLabelValues(machine.Namespace.Name, machine.Hostname).
 				Set(float64(now.Unix()))
 			machine.LastSuccessfulUpdate = &now
+			machine.NetmapVersion = &netmapVersion
 
 			err = h.TouchMachine(machine)
 			if err != nil {
@@ -491,6 +500,7 @@ func (h *Headscale) PollNetMapStream(
 					Time("last_successful_update", lastUpdate).
 					Time("last_state_change", h.getLastStateChange(machine.Namespace.Name)).
 					Msgf("There has been updates since the last successful update to %s", machine.Hostname)
+				mapVersion := h.netmapVersion()
 				data, err := h.getMapResponse(machineKey, mapRequest, machine)
 				if err != nil {
 					log.Error().
@@ -561,6 +571,7 @@ func (h *Headscale) PollNetMapStream(
 				lastStateUpdate.WithLabelValues(machine.Namespace.Name, machine.Hostname).
 					Set(float64(now.Unix()))
 				machine.LastSuccessfulUpdate = &now
+				machine.NetmapVersion = &mapVersion
 
 				err = h.TouchMachine(machine)
 				if err != nil {
@@ -618,6 +629,8 @@ func (h *Headscale) PollNetMapStream(
 					Err(err).
 					Msg("Cannot update machine LastSeen")
 			}
+			h.flaps.recordDisconnect(machine.ID, now)
+			h.recordConnectionEvent(machine, ConnectionEventDisconnect, req.RemoteAddr, now)
 
 			// The connection has been closed, so we can stop polling.
 			return
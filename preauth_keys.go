@@ -17,6 +17,7 @@ const (
 	errPreAuthKeyNotFound          = Error("AuthKey not found")
 	errPreAuthKeyExpired           = Error("AuthKey expired")
 	errSingleUseAuthKeyHasBeenUsed = Error("AuthKey has already been used")
+	errPreAuthKeyNamespaceConflict = Error("the namespace is not the one of the AuthKey")
 	errNamespaceMismatch           = Error("namespace mismatch")
 )
 
@@ -158,6 +159,70 @@ func (h *Headscale) checkKeyValidity(k string) (*PreAuthKey, error) {
 	return &pak, nil
 }
 
+// RegistrationPreAuthKey checks a PreAuthKey given to register a machine
+// from the CLI, which is then registered in the namespace of the key.
+// namespaceName may be empty, or must be the namespace of the key.
+func (h *Headscale) RegistrationPreAuthKey(key string, namespaceName string) (*PreAuthKey, error) {
+	pak, err := h.checkKeyValidity(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if namespaceName != "" && namespaceName != pak.Namespace.Name {
+		return nil, fmt.Errorf(
+			"%q, the AuthKey is in %q: %w",
+			namespaceName,
+			pak.Namespace.Name,
+			errPreAuthKeyNamespaceConflict,
+		)
+	}
+
+	return pak, nil
+}
+
+// UsePreAuthKeyForMachine records that a machine registered with a
+// PreAuthKey, like the machines logging in with it, and marks the key as
+// used. The machine inherits the ephemeral setting of the key.
+// Both are saved in one transaction, which fails with
+// errSingleUseAuthKeyHasBeenUsed if a single use key was used by another
+// machine since it was checked.
+func (h *Headscale) UsePreAuthKeyForMachine(machine *Machine, pak *PreAuthKey) error {
+	ephemeral := machine.Ephemeral || pak.Ephemeral
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&PreAuthKey{}).Where("id = ?", pak.ID)
+		if !pak.Reusable && !pak.Ephemeral {
+			query = query.Where("used = ?", false)
+		}
+		result := query.Update("used", true)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update key used status in the database: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return errSingleUseAuthKeyHasBeenUsed
+		}
+
+		err := tx.Model(machine).Updates(map[string]interface{}{
+			"auth_key_id": uint(pak.ID),
+			"ephemeral":   ephemeral,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to save the AuthKey of the machine in the database: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	pak.Used = true
+	machine.AuthKeyID = uint(pak.ID)
+	machine.AuthKey = pak
+	machine.Ephemeral = ephemeral
+
+	return nil
+}
+
 func (h *Headscale) generateKey() (string, error) {
 	size := 24
 	bytes := make([]byte, size)
@@ -1,8 +1,13 @@
 package headscale
 
 import (
+	"context"
 	"time"
 
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/patrickmn/go-cache"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/check.v1"
 )
 
@@ -161,6 +166,31 @@ func (*Suite) TestEphemeralKey(c *check.C) {
 	c.Assert(err, check.NotNil)
 }
 
+func (*Suite) TestEphemeralMachineWithoutKey(c *check.C) {
+	namespace, err := app.CreateNamespace("test8")
+	c.Assert(err, check.IsNil)
+
+	now := time.Now()
+	machine := Machine{
+		MachineKey:     "foo",
+		NodeKey:        "bar",
+		DiscoKey:       "faa",
+		Hostname:       "testest",
+		NamespaceID:    namespace.ID,
+		RegisterMethod: RegisterMethodCLI,
+		LastSeen:       &now,
+		Ephemeral:      true,
+	}
+	app.db.Save(&machine)
+
+	c.Assert(machine.toProto().GetEphemeral(), check.Equals, true)
+
+	app.expireEphemeralNodesWorker()
+
+	_, err = app.GetMachine("test8", "testest")
+	c.Assert(err, check.NotNil)
+}
+
 func (*Suite) TestExpirePreauthKey(c *check.C) {
 	namespace, err := app.CreateNamespace("test3")
 	c.Assert(err, check.IsNil)
@@ -190,3 +220,109 @@ func (*Suite) TestNotReusableMarkedAsUsed(c *check.C) {
 	_, err = app.checkKeyValidity(pak.Key)
 	c.Assert(err, check.Equals, errSingleUseAuthKeyHasBeenUsed)
 }
+
+func (*Suite) TestGetMachineByIDLoadsPreAuthKey(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	pak, err := app.CreatePreAuthKey(namespace.Name, false, false, nil)
+	c.Assert(err, check.IsNil)
+
+	machine := Machine{
+		ID:             1,
+		MachineKey:     "foo",
+		NodeKey:        "bar",
+		DiscoKey:       "faa",
+		Hostname:       "testmachine",
+		NamespaceID:    namespace.ID,
+		RegisterMethod: RegisterMethodAuthKey,
+		AuthKeyID:      uint(pak.ID),
+	}
+	app.db.Save(&machine)
+
+	loaded, err := app.GetMachineByID(machine.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(loaded.AuthKey, check.NotNil)
+	c.Assert(loaded.toProto().GetPreAuthKey().GetKey(), check.Equals, pak.Key)
+}
+
+func (*Suite) TestRegisterMachineWithPreAuthKey(c *check.C) {
+	namespace, err := app.CreateNamespace("test5")
+	c.Assert(err, check.IsNil)
+	_, err = app.CreateNamespace("other")
+	c.Assert(err, check.IsNil)
+
+	pak, err := app.CreatePreAuthKey(namespace.Name, false, true, nil)
+	c.Assert(err, check.IsNil)
+
+	app.registrationCache = cache.New(registerCacheExpiration, registerCacheCleanup)
+	app.registrationCache.Set(
+		"foo",
+		Machine{
+			MachineKey: "foo",
+			NodeKey:    "bar",
+			DiscoKey:   "faa",
+			Hostname:   "web",
+			GivenName:  "web",
+		},
+		registerCacheExpiration,
+	)
+
+	api := newHeadscaleV1APIServer(&app)
+	_, err = api.RegisterMachine(context.Background(), &v1.RegisterMachineRequest{
+		Key:        "foo",
+		Namespace:  "other",
+		PreAuthKey: pak.Key,
+	})
+	c.Assert(status.Code(err), check.Equals, codes.InvalidArgument)
+
+	_, err = api.RegisterMachine(context.Background(), &v1.RegisterMachineRequest{
+		Key:        "foo",
+		PreAuthKey: "potatoKey",
+	})
+	c.Assert(status.Code(err), check.Equals, codes.NotFound)
+
+	response, err := api.RegisterMachine(context.Background(), &v1.RegisterMachineRequest{
+		Key:        "foo",
+		PreAuthKey: pak.Key,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(response.GetMachine().GetNamespace().GetName(), check.Equals, namespace.Name)
+	c.Assert(response.GetMachine().GetEphemeral(), check.Equals, true)
+	c.Assert(response.GetMachine().GetPreAuthKey().GetKey(), check.Equals, pak.Key)
+
+	machine, err := app.GetMachineByID(response.GetMachine().GetId())
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.AuthKey, check.NotNil)
+	c.Assert(machine.AuthKey.ID, check.Equals, pak.ID)
+	c.Assert(machine.AuthKey.Used, check.Equals, true)
+	c.Assert(machine.Ephemeral, check.Equals, true)
+}
+
+func (*Suite) TestUsePreAuthKeyForMachineUsedMeanwhile(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	pak, err := app.CreatePreAuthKey(namespace.Name, false, false, nil)
+	c.Assert(err, check.IsNil)
+	checked, err := app.checkKeyValidity(pak.Key)
+	c.Assert(err, check.IsNil)
+
+	machine := Machine{
+		ID:          1,
+		MachineKey:  "foo",
+		Hostname:    "testmachine",
+		NamespaceID: namespace.ID,
+	}
+	app.db.Save(&machine)
+
+	// Another machine registers with the key after it was checked.
+	app.db.Model(&PreAuthKey{}).Where("id = ?", pak.ID).Update("used", true)
+
+	err = app.UsePreAuthKeyForMachine(&machine, checked)
+	c.Assert(err, check.Equals, errSingleUseAuthKeyHasBeenUsed)
+
+	saved, err := app.GetMachineByID(machine.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(saved.AuthKeyID, check.Equals, uint(0))
+}
@@ -10,6 +10,54 @@ const (
 	errRouteIsNotAvailable = Error("route is not available")
 )
 
+var (
+	exitRouteV4 = netaddr.MustParseIPPrefix("0.0.0.0/0")
+	exitRouteV6 = netaddr.MustParseIPPrefix("::/0")
+)
+
+// ExitNode is a machine advertising both default routes, approved when
+// both are enabled.
+type ExitNode struct {
+	Machine  Machine
+	Approved bool
+}
+
+// isExitNode tells if the machine advertises both default routes.
+func (machine *Machine) isExitNode() bool {
+	return contains(machine.GetAdvertisedRoutes(), exitRouteV4) &&
+		contains(machine.GetAdvertisedRoutes(), exitRouteV6)
+}
+
+// ListExitNodes returns the exit nodes of a namespace, or of every
+// namespace when it is empty.
+func (h *Headscale) ListExitNodes(namespace string) ([]ExitNode, error) {
+	var machines []Machine
+	var err error
+	if namespace != "" {
+		machines, err = h.ListMachinesInNamespace(namespace)
+	} else {
+		machines, err = h.ListMachines()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	exitNodes := []ExitNode{}
+	for _, machine := range machines {
+		if !machine.isExitNode() {
+			continue
+		}
+
+		exitNodes = append(exitNodes, ExitNode{
+			Machine: machine,
+			Approved: contains(machine.GetEnabledRoutes(), exitRouteV4) &&
+				contains(machine.GetEnabledRoutes(), exitRouteV6),
+		})
+	}
+
+	return exitNodes, nil
+}
+
 // Deprecated: use machine function instead
 // GetAdvertisedNodeRoutes returns the subnet routes advertised by a node (identified by
 // namespace and node name).
@@ -132,3 +132,52 @@ func (s *Suite) TestGetEnableRoutes(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(len(enabledRoutesWithAdditionalRoute), check.Equals, 2)
 }
+
+func (s *Suite) TestListExitNodes(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	exitRoutes := []netaddr.IPPrefix{
+		netaddr.MustParseIPPrefix("0.0.0.0/0"),
+		netaddr.MustParseIPPrefix("::/0"),
+	}
+	machines := []Machine{
+		{
+			ID:            1,
+			MachineKey:    "approved",
+			Hostname:      "approved",
+			NamespaceID:   namespace.ID,
+			HostInfo:      HostInfo{RoutableIPs: exitRoutes},
+			EnabledRoutes: exitRoutes,
+		},
+		{
+			ID:            2,
+			MachineKey:    "pending",
+			Hostname:      "pending",
+			NamespaceID:   namespace.ID,
+			HostInfo:      HostInfo{RoutableIPs: exitRoutes},
+			EnabledRoutes: exitRoutes[:1],
+		},
+		{
+			ID:          3,
+			MachineKey:  "ipv4-only",
+			Hostname:    "ipv4-only",
+			NamespaceID: namespace.ID,
+			HostInfo:    HostInfo{RoutableIPs: exitRoutes[:1]},
+		},
+	}
+	for index := range machines {
+		app.db.Save(&machines[index])
+	}
+
+	exitNodes, err := app.ListExitNodes("")
+	c.Assert(err, check.IsNil)
+	c.Assert(exitNodes, check.HasLen, 2)
+	c.Assert(exitNodes[0].Machine.Hostname, check.Equals, "approved")
+	c.Assert(exitNodes[0].Approved, check.Equals, true)
+	c.Assert(exitNodes[1].Machine.Hostname, check.Equals, "pending")
+	c.Assert(exitNodes[1].Approved, check.Equals, false)
+
+	_, err = app.ListExitNodes("unknown")
+	c.Assert(err, check.Equals, errNamespaceNotFound)
+}
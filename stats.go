@@ -0,0 +1,208 @@
+package headscale
+
+import (
+	"sort"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	errInvalidStatsBucket = Error("bucket must be a positive duration")
+	errTooManyStatsBucket = Error("too many buckets, use a larger bucket or a shorter period")
+
+	nodeCountSnapshotInterval = 15 * time.Minute
+	maxNodeCountBuckets       = 1000
+)
+
+// NodeCountSnapshot records the number of machines of a namespace at a
+// point in time, so trends can be computed later on.
+type NodeCountSnapshot struct {
+	ID uint64 `gorm:"primary_key"`
+	// Namespace is the name of the namespace when the snapshot was taken,
+	// it is kept as is if the namespace is renamed or destroyed.
+	Namespace  string `gorm:"index"`
+	Registered int
+	Active     int
+	CreatedAt  time.Time `gorm:"index"`
+}
+
+// NodeCountBucket is the number of machines over an interval, using the
+// last snapshot taken in the interval.
+type NodeCountBucket struct {
+	Start time.Time
+	// Namespace is empty when the counts of all the namespaces are summed up.
+	Namespace  string
+	Registered int
+	Active     int
+}
+
+// snapshotNodeCounts periodically records the number of machines of
+// every namespace.
+func (h *Headscale) snapshotNodeCounts(interval time.Duration) {
+	h.snapshotNodeCountsWorker()
+
+	ticker := time.NewTicker(interval)
+	for range ticker.C {
+		h.snapshotNodeCountsWorker()
+	}
+}
+
+func (h *Headscale) snapshotNodeCountsWorker() {
+	namespaces, err := h.ListNamespaces()
+	if err != nil {
+		log.Error().Err(err).Msg("Error listing namespaces")
+
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, namespace := range namespaces {
+		machines, err := h.ListMachinesInNamespace(namespace.Name)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("namespace", namespace.Name).
+				Msg("Error listing machines in namespace")
+
+			continue
+		}
+
+		snapshot := NodeCountSnapshot{
+			Namespace:  namespace.Name,
+			Registered: len(machines),
+			CreatedAt:  now,
+		}
+		for _, machine := range machines {
+			if machine.isActive() {
+				snapshot.Active++
+			}
+		}
+
+		if err := h.db.Create(&snapshot).Error; err != nil {
+			log.Error().
+				Err(err).
+				Str("namespace", namespace.Name).
+				Msg("Could not save node count snapshot")
+		}
+	}
+
+	h.purgeNodeCountSnapshots(now)
+}
+
+// purgeNodeCountSnapshots deletes the snapshots older than the retention
+// window, none when it is 0.
+func (h *Headscale) purgeNodeCountSnapshots(now time.Time) {
+	if h.cfg.NodeStatsRetention <= 0 {
+		return
+	}
+
+	result := h.db.
+		Where("created_at < ?", now.Add(-h.cfg.NodeStatsRetention)).
+		Delete(&NodeCountSnapshot{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("Could not purge node count snapshots")
+
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		log.Debug().
+			Int64("count", result.RowsAffected).
+			Msg("Purged node count snapshots")
+	}
+}
+
+// isActive reports if the machine is connected to the control server
+// and not expired.
+func (machine Machine) isActive() bool {
+	return machine.LastSeen != nil &&
+		machine.LastSeen.After(time.Now().Add(-keepAliveInterval)) &&
+		!machine.isExpired()
+}
+
+// GetNodeCountTrend returns the number of registered and active machines
+// since a point in time, grouped in buckets of the given duration.
+// An empty namespace covers all the namespaces, which are summed up unless
+// perNamespace is set. Buckets without any snapshot are omitted.
+func (h *Headscale) GetNodeCountTrend(
+	since time.Time,
+	bucket time.Duration,
+	namespace string,
+	perNamespace bool,
+) ([]NodeCountBucket, error) {
+	if bucket <= 0 {
+		return nil, errInvalidStatsBucket
+	}
+	if time.Since(since)/bucket > maxNodeCountBuckets {
+		return nil, errTooManyStatsBucket
+	}
+
+	query := h.db.Where("created_at >= ?", since.UTC())
+	if namespace != "" {
+		query = query.Where("namespace = ?", namespace)
+	}
+
+	snapshots := []NodeCountSnapshot{}
+	if err := query.Order("created_at").Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	type bucketKey struct {
+		index     int64
+		namespace string
+	}
+
+	// Snapshots are sorted, the last one of a bucket wins.
+	latest := make(map[bucketKey]NodeCountSnapshot)
+	for _, snapshot := range snapshots {
+		key := bucketKey{
+			index:     int64(snapshot.CreatedAt.Sub(since) / bucket),
+			namespace: snapshot.Namespace,
+		}
+		latest[key] = snapshot
+	}
+
+	summed := make(map[bucketKey]*NodeCountBucket)
+	for key, snapshot := range latest {
+		if !perNamespace {
+			key.namespace = ""
+		}
+
+		counts, ok := summed[key]
+		if !ok {
+			counts = &NodeCountBucket{
+				Start:     since.Add(time.Duration(key.index) * bucket).UTC(),
+				Namespace: key.namespace,
+			}
+			summed[key] = counts
+		}
+		counts.Registered += snapshot.Registered
+		counts.Active += snapshot.Active
+	}
+
+	buckets := make([]NodeCountBucket, 0, len(summed))
+	for _, counts := range summed {
+		buckets = append(buckets, *counts)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Start.Equal(buckets[j].Start) {
+			return buckets[i].Namespace < buckets[j].Namespace
+		}
+
+		return buckets[i].Start.Before(buckets[j].Start)
+	})
+
+	return buckets, nil
+}
+
+func (bucket NodeCountBucket) toProto() *v1.NodeCountBucket {
+	return &v1.NodeCountBucket{
+		Start:      timestamppb.New(bucket.Start),
+		Namespace:  bucket.Namespace,
+		Registered: uint32(bucket.Registered),
+		Active:     uint32(bucket.Active),
+	}
+}
@@ -0,0 +1,95 @@
+package headscale
+
+import (
+	"time"
+
+	"gopkg.in/check.v1"
+)
+
+func (s *Suite) TestSnapshotNodeCounts(c *check.C) {
+	namespace, err := app.CreateNamespace("test")
+	c.Assert(err, check.IsNil)
+
+	now := time.Now()
+	machines := []Machine{
+		{ID: 1, Hostname: "online", NamespaceID: namespace.ID, LastSeen: &now},
+		{ID: 2, Hostname: "offline", NamespaceID: namespace.ID},
+	}
+	for index := range machines {
+		c.Assert(app.db.Save(&machines[index]).Error, check.IsNil)
+	}
+
+	app.snapshotNodeCountsWorker()
+
+	buckets, err := app.GetNodeCountTrend(now.Add(-time.Hour), time.Hour, "", false)
+	c.Assert(err, check.IsNil)
+	c.Assert(buckets, check.HasLen, 1)
+	c.Assert(buckets[0].Registered, check.Equals, 2)
+	c.Assert(buckets[0].Active, check.Equals, 1)
+}
+
+func (s *Suite) TestPurgeNodeCountSnapshots(c *check.C) {
+	retention := app.cfg.NodeStatsRetention
+	app.cfg.NodeStatsRetention = 24 * time.Hour
+	defer func() { app.cfg.NodeStatsRetention = retention }()
+
+	now := time.Now().UTC()
+	snapshots := []NodeCountSnapshot{
+		{Namespace: "old", CreatedAt: now.Add(-25 * time.Hour)},
+		{Namespace: "recent", CreatedAt: now.Add(-time.Hour)},
+	}
+	for index := range snapshots {
+		c.Assert(app.db.Create(&snapshots[index]).Error, check.IsNil)
+	}
+
+	app.snapshotNodeCountsWorker()
+
+	var kept []NodeCountSnapshot
+	c.Assert(app.db.Find(&kept).Error, check.IsNil)
+	c.Assert(kept, check.HasLen, 1)
+	c.Assert(kept[0].Namespace, check.Equals, "recent")
+}
+
+func (s *Suite) TestGetNodeCountTrend(c *check.C) {
+	since := time.Now().Add(-3 * time.Hour).UTC()
+	snapshots := []NodeCountSnapshot{
+		// The last snapshot of a bucket is used.
+		{Namespace: "foo", Registered: 1, Active: 1, CreatedAt: since.Add(time.Minute)},
+		{Namespace: "foo", Registered: 2, Active: 1, CreatedAt: since.Add(30 * time.Minute)},
+		{Namespace: "bar", Registered: 3, Active: 0, CreatedAt: since.Add(40 * time.Minute)},
+		{Namespace: "foo", Registered: 4, Active: 4, CreatedAt: since.Add(2 * time.Hour)},
+		// Too old to be included.
+		{Namespace: "foo", Registered: 10, Active: 10, CreatedAt: since.Add(-time.Minute)},
+	}
+	for index := range snapshots {
+		c.Assert(app.db.Create(&snapshots[index]).Error, check.IsNil)
+	}
+
+	buckets, err := app.GetNodeCountTrend(since, time.Hour, "", false)
+	c.Assert(err, check.IsNil)
+	c.Assert(buckets, check.HasLen, 2)
+	c.Assert(buckets[0].Start.Equal(since), check.Equals, true)
+	c.Assert(buckets[0].Namespace, check.Equals, "")
+	c.Assert(buckets[0].Registered, check.Equals, 5)
+	c.Assert(buckets[0].Active, check.Equals, 1)
+	c.Assert(buckets[1].Start.Equal(since.Add(2*time.Hour)), check.Equals, true)
+	c.Assert(buckets[1].Registered, check.Equals, 4)
+
+	buckets, err = app.GetNodeCountTrend(since, time.Hour, "", true)
+	c.Assert(err, check.IsNil)
+	c.Assert(buckets, check.HasLen, 3)
+	c.Assert(buckets[0].Namespace, check.Equals, "bar")
+	c.Assert(buckets[1].Namespace, check.Equals, "foo")
+	c.Assert(buckets[1].Registered, check.Equals, 2)
+
+	buckets, err = app.GetNodeCountTrend(since, time.Hour, "bar", false)
+	c.Assert(err, check.IsNil)
+	c.Assert(buckets, check.HasLen, 1)
+	c.Assert(buckets[0].Registered, check.Equals, 3)
+
+	_, err = app.GetNodeCountTrend(since, 0, "", false)
+	c.Assert(err, check.Equals, errInvalidStatsBucket)
+
+	_, err = app.GetNodeCountTrend(since, time.Second, "", false)
+	c.Assert(err, check.Equals, errTooManyStatsBucket)
+}
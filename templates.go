@@ -0,0 +1,347 @@
+package headscale
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/juanfont/headscale/gen/go/headscale/v1"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+	"inet.af/netaddr"
+	"tailscale.com/types/key"
+)
+
+const (
+	errNodeTemplateExists   = Error("node template already exists")
+	errNodeTemplateNotFound = Error("node template not found")
+	errInvalidNodeTemplate  = Error("invalid node template")
+)
+
+// NodeTemplate is a named set of settings applied to the machines when
+// they are registered, to provision a class of devices consistently.
+type NodeTemplate struct {
+	ID     uint64 `gorm:"primary_key"`
+	Name   string `gorm:"unique"`
+	Tags   StringList
+	Routes StringList
+	Labels StringMap
+	// Expiry is the validity of the registration, 0 for no expiry.
+	Expiry time.Duration
+
+	CreatedAt *time.Time
+}
+
+func (template NodeTemplate) validate() error {
+	for _, tag := range template.Tags {
+		if !strings.HasPrefix(tag, "tag:") {
+			return fmt.Errorf(
+				"tag %q must start with 'tag:': %w",
+				tag,
+				errInvalidNodeTemplate,
+			)
+		}
+	}
+	for _, route := range template.Routes {
+		if _, err := netaddr.ParseIPPrefix(route); err != nil {
+			return fmt.Errorf("route %q: %s: %w", route, err, errInvalidNodeTemplate)
+		}
+	}
+	if err := validateLabels(template.Labels); err != nil {
+		return fmt.Errorf("%s: %w", err, errInvalidNodeTemplate)
+	}
+	if template.Expiry < 0 {
+		return fmt.Errorf("expiry must not be negative: %w", errInvalidNodeTemplate)
+	}
+
+	return nil
+}
+
+// CreateNodeTemplate saves a new node template.
+func (h *Headscale) CreateNodeTemplate(template NodeTemplate) (*NodeTemplate, error) {
+	if err := CheckForFQDNRules(template.Name); err != nil {
+		return nil, fmt.Errorf("name %q: %s: %w", template.Name, err, errInvalidNodeTemplate)
+	}
+	if err := template.validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := h.GetNodeTemplate(template.Name); err == nil {
+		return nil, errNodeTemplateExists
+	}
+
+	template.ID = 0
+	if err := h.db.Create(&template).Error; err != nil {
+		return nil, fmt.Errorf("failed to save node template to database: %w", err)
+	}
+
+	return &template, nil
+}
+
+// ListNodeTemplates returns the node templates, sorted by name.
+func (h *Headscale) ListNodeTemplates() ([]NodeTemplate, error) {
+	templates := []NodeTemplate{}
+	if err := h.db.Order("name").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// GetNodeTemplate returns the node template with the given name.
+func (h *Headscale) GetNodeTemplate(name string) (*NodeTemplate, error) {
+	template := NodeTemplate{}
+	err := h.db.Where("name = ?", name).First(&template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errNodeTemplateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &template, nil
+}
+
+// DeleteNodeTemplate deletes a node template. The machines registered
+// with it keep their settings.
+func (h *Headscale) DeleteNodeTemplate(name string) error {
+	template, err := h.GetNodeTemplate(name)
+	if err != nil {
+		return err
+	}
+
+	return h.db.Delete(template).Error
+}
+
+// RegisterMachineWithTemplate registers a machine waiting in the
+// registration cache, then applies the settings of the template. The
+// overrides take precedence: their labels are merged with the ones of the
+// template, and their tags, routes and expiry replace the template ones
+// when set. templateName may be empty to only apply the overrides.
+// ipAddresses are the addresses requested for the machine, the other ones
+// are allocated.
+func (h *Headscale) RegisterMachineWithTemplate(
+	machineKeyStr string,
+	namespaceName string,
+	templateName string,
+	overrides NodeTemplate,
+	ipAddresses MachineAddresses,
+) (*Machine, error) {
+	settings, err := h.registrationSettings(templateName, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	registered, err := h.RegisterMachineFromAuthCallback(
+		machineKeyStr,
+		namespaceName,
+		RegisterMethodCLI,
+		settings.Labels,
+		ipAddresses,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload the machine along with its namespace.
+	machine, err := h.GetMachineByID(registered.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.applyNodeTemplate(machine, settings); err != nil {
+		return nil, err
+	}
+
+	return machine, nil
+}
+
+// PlanMachineRegistration runs the validations of
+// RegisterMachineWithTemplate and returns the machine it would register,
+// without saving anything. The IP addresses are the ones the machine would
+// get if no other machine is registered before.
+func (h *Headscale) PlanMachineRegistration(
+	machineKeyStr string,
+	namespaceName string,
+	templateName string,
+	overrides NodeTemplate,
+	ipAddresses MachineAddresses,
+) (*Machine, error) {
+	var machineKey key.MachinePublic
+	err := machineKey.UnmarshalText([]byte(MachinePublicKeyEnsurePrefix(machineKeyStr)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", err, errInvalidMachineKey)
+	}
+
+	settings, err := h.registrationSettings(templateName, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	machineInterface, ok := h.registrationCache.Get(machineKeyStr)
+	if !ok {
+		return nil, errMachineNotFoundRegistrationCache
+	}
+	machine, ok := machineInterface.(Machine)
+	if !ok {
+		return nil, errCouldNotConvertMachineInterface
+	}
+
+	namespace, err := h.GetNamespace(namespaceName)
+	if err != nil {
+		return nil, err
+	}
+	if namespace.Locked {
+		return nil, errNamespaceLocked
+	}
+	machine.NamespaceID = namespace.ID
+	machine.Namespace = *namespace
+	machine.RegisterMethod = RegisterMethodCLI
+	if len(settings.Labels) > 0 {
+		machine.Labels = settings.Labels
+	}
+
+	h.ipAllocationMutex.Lock()
+	ips, err := h.getRequestedIPs(ipAddresses)
+	h.ipAllocationMutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	machine.IPAddresses = ips
+
+	// The tags of the template replace the autotags, as in
+	// applyNodeTemplate.
+	if len(settings.Tags) > 0 {
+		machine.ForcedTags = settings.Tags
+	} else {
+		autoTags, err := h.missingAutoTags(&machine)
+		if err != nil {
+			return nil, err
+		}
+		machine.ForcedTags = append(machine.ForcedTags, autoTags...)
+	}
+
+	for _, routeStr := range advertisedTemplateRoutes(&machine, settings.Routes) {
+		route, _ := netaddr.ParseIPPrefix(routeStr)
+		machine.EnabledRoutes = append(machine.EnabledRoutes, route)
+	}
+
+	if settings.Expiry > 0 {
+		expiry := time.Now().UTC().Add(settings.Expiry)
+		machine.Expiry = &expiry
+	}
+
+	return &machine, nil
+}
+
+// registrationSettings merges the overrides of a registration with the
+// settings of the template.
+func (h *Headscale) registrationSettings(
+	templateName string,
+	overrides NodeTemplate,
+) (NodeTemplate, error) {
+	if err := overrides.validate(); err != nil {
+		return NodeTemplate{}, err
+	}
+
+	settings := NodeTemplate{}
+	if templateName != "" {
+		template, err := h.GetNodeTemplate(templateName)
+		if err != nil {
+			return NodeTemplate{}, err
+		}
+		settings = *template
+	}
+
+	labels := map[string]string{}
+	for key, value := range settings.Labels {
+		labels[key] = value
+	}
+	for key, value := range overrides.Labels {
+		labels[key] = value
+	}
+	settings.Labels = labels
+	if len(overrides.Tags) > 0 {
+		settings.Tags = overrides.Tags
+	}
+	if len(overrides.Routes) > 0 {
+		settings.Routes = overrides.Routes
+	}
+	if overrides.Expiry > 0 {
+		settings.Expiry = overrides.Expiry
+	}
+
+	return settings, nil
+}
+
+// advertisedTemplateRoutes returns the routes of a template advertised by
+// the machine, the other ones are skipped with a warning.
+func advertisedTemplateRoutes(machine *Machine, templateRoutes []string) []string {
+	routes := []string{}
+	for _, routeStr := range templateRoutes {
+		route, _ := netaddr.ParseIPPrefix(routeStr)
+		if !contains(machine.GetAdvertisedRoutes(), route) {
+			log.Warn().
+				Str("machine", machine.Hostname).
+				Str("route", routeStr).
+				Msg("Route of the node template is not advertised by the machine, skipping")
+
+			continue
+		}
+		routes = append(routes, routeStr)
+	}
+
+	return routes
+}
+
+// applyNodeTemplate sets the tags, routes and expiry of the template on a
+// newly registered machine. The routes the machine does not advertise are
+// skipped.
+func (h *Headscale) applyNodeTemplate(machine *Machine, template NodeTemplate) error {
+	if len(template.Tags) > 0 {
+		if err := h.SetTags(machine, template.Tags); err != nil {
+			return err
+		}
+	}
+
+	routes := advertisedTemplateRoutes(machine, template.Routes)
+	if len(routes) > 0 {
+		if err := h.EnableRoutes(machine, routes...); err != nil {
+			return err
+		}
+	}
+
+	if template.Expiry > 0 {
+		expiry := time.Now().UTC().Add(template.Expiry)
+		machine.Expiry = &expiry
+
+		if err := h.db.Save(machine).Error; err != nil {
+			return fmt.Errorf("failed to set the expiry of the machine in the database: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (template *NodeTemplate) toProto() *v1.NodeTemplate {
+	templateProto := &v1.NodeTemplate{
+		Id:     template.ID,
+		Name:   template.Name,
+		Tags:   template.Tags,
+		Routes: template.Routes,
+		Labels: template.Labels,
+	}
+
+	if template.Expiry > 0 {
+		templateProto.Expiry = durationpb.New(template.Expiry)
+	}
+
+	if template.CreatedAt != nil {
+		templateProto.CreatedAt = timestamppb.New(*template.CreatedAt)
+	}
+
+	return templateProto
+}
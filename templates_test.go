@@ -0,0 +1,180 @@
+package headscale
+
+import (
+	"errors"
+	"time"
+
+	"github.com/patrickmn/go-cache"
+	"gopkg.in/check.v1"
+	"inet.af/netaddr"
+)
+
+func (s *Suite) TestNodeTemplates(c *check.C) {
+	_, err := app.CreateNodeTemplate(NodeTemplate{
+		Name: "web",
+		Tags: []string{"web"},
+	})
+	c.Assert(err, check.ErrorMatches, ".*invalid node template")
+
+	template, err := app.CreateNodeTemplate(NodeTemplate{
+		Name:   "web",
+		Tags:   []string{"tag:web"},
+		Routes: []string{"10.0.0.0/24", "10.1.0.0/24"},
+		Labels: map[string]string{"env": "production", "team": "web"},
+		Expiry: 90 * 24 * time.Hour,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(template.Name, check.Equals, "web")
+
+	_, err = app.CreateNodeTemplate(NodeTemplate{Name: "web"})
+	c.Assert(err, check.Equals, errNodeTemplateExists)
+
+	templates, err := app.ListNodeTemplates()
+	c.Assert(err, check.IsNil)
+	c.Assert(templates, check.HasLen, 1)
+
+	namespace, err := app.CreateNamespace("servers")
+	c.Assert(err, check.IsNil)
+
+	app.registrationCache = cache.New(
+		registerCacheExpiration,
+		registerCacheCleanup,
+	)
+	app.registrationCache.Set(
+		"foo",
+		Machine{
+			MachineKey: "foo",
+			NodeKey:    "bar",
+			DiscoKey:   "faa",
+			Hostname:   "web",
+			GivenName:  "web",
+			HostInfo: HostInfo{
+				RoutableIPs: []netaddr.IPPrefix{netaddr.MustParseIPPrefix("10.0.0.0/24")},
+			},
+		},
+		registerCacheExpiration,
+	)
+
+	_, err = app.RegisterMachineWithTemplate("foo", namespace.Name, "unknown", NodeTemplate{}, nil)
+	c.Assert(err, check.Equals, errNodeTemplateNotFound)
+
+	machine, err := app.RegisterMachineWithTemplate(
+		"foo",
+		namespace.Name,
+		"web",
+		NodeTemplate{Labels: map[string]string{"env": "staging"}},
+		nil,
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.ForcedTags, check.DeepEquals, StringList{"tag:web"})
+	// The route not advertised by the machine is skipped.
+	c.Assert(machine.EnabledRoutes, check.DeepEquals, IPPrefixes{netaddr.MustParseIPPrefix("10.0.0.0/24")})
+	c.Assert(machine.Labels, check.DeepEquals, StringMap{"env": "staging", "team": "web"})
+	c.Assert(machine.Expiry, check.NotNil)
+	c.Assert(machine.Expiry.After(time.Now().Add(89*24*time.Hour)), check.Equals, true)
+
+	err = app.DeleteNodeTemplate("web")
+	c.Assert(err, check.IsNil)
+	err = app.DeleteNodeTemplate("web")
+	c.Assert(err, check.Equals, errNodeTemplateNotFound)
+}
+
+func (s *Suite) TestPlanMachineRegistration(c *check.C) {
+	machineKey := "7a3b1c2d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f9"
+
+	namespace, err := app.CreateNamespace("servers")
+	c.Assert(err, check.IsNil)
+
+	app.registrationCache = cache.New(
+		registerCacheExpiration,
+		registerCacheCleanup,
+	)
+	app.registrationCache.Set(
+		machineKey,
+		Machine{
+			MachineKey: machineKey,
+			NodeKey:    "bar",
+			DiscoKey:   "faa",
+			Hostname:   "web",
+			GivenName:  "web",
+			HostInfo: HostInfo{
+				RoutableIPs: []netaddr.IPPrefix{netaddr.MustParseIPPrefix("10.0.0.0/24")},
+			},
+		},
+		registerCacheExpiration,
+	)
+
+	_, err = app.PlanMachineRegistration("not-a-key", namespace.Name, "", NodeTemplate{}, nil)
+	c.Assert(errors.Is(err, errInvalidMachineKey), check.Equals, true)
+
+	_, err = app.PlanMachineRegistration(machineKey, "unknown", "", NodeTemplate{}, nil)
+	c.Assert(err, check.Equals, errNamespaceNotFound)
+
+	_, err = app.PlanMachineRegistration(
+		machineKey,
+		namespace.Name,
+		"",
+		NodeTemplate{Tags: []string{"web"}},
+		nil,
+	)
+	c.Assert(errors.Is(err, errInvalidNodeTemplate), check.Equals, true)
+
+	machine, err := app.PlanMachineRegistration(
+		machineKey,
+		namespace.Name,
+		"",
+		NodeTemplate{
+			Tags:   []string{"tag:web"},
+			Routes: []string{"10.0.0.0/24", "10.1.0.0/24"},
+			Expiry: time.Hour,
+		},
+		nil,
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.ID, check.Equals, uint64(0))
+	c.Assert(machine.Namespace.Name, check.Equals, "servers")
+	c.Assert(machine.IPAddresses, check.HasLen, 1)
+	c.Assert(machine.ForcedTags, check.DeepEquals, StringList{"tag:web"})
+	c.Assert(machine.EnabledRoutes, check.DeepEquals, IPPrefixes{netaddr.MustParseIPPrefix("10.0.0.0/24")})
+	c.Assert(machine.Expiry, check.NotNil)
+
+	// Nothing was saved.
+	machines, err := app.ListMachines()
+	c.Assert(err, check.IsNil)
+	c.Assert(machines, check.HasLen, 0)
+
+	machine, err = app.PlanMachineRegistration(
+		machineKey,
+		namespace.Name,
+		"",
+		NodeTemplate{},
+		MachineAddresses{netaddr.MustParseIP("10.27.0.42")},
+	)
+	c.Assert(err, check.IsNil)
+	c.Assert(machine.IPAddresses.ToStringSlice(), check.DeepEquals, []string{"10.27.0.42"})
+
+	_, err = app.PlanMachineRegistration(
+		machineKey,
+		namespace.Name,
+		"",
+		NodeTemplate{},
+		MachineAddresses{netaddr.MustParseIP("192.168.0.1")},
+	)
+	c.Assert(errors.Is(err, errIPNotInPrefixes), check.Equals, true)
+
+	app.db.Save(&Machine{
+		ID:          1,
+		MachineKey:  "foo",
+		Hostname:    "existing",
+		NamespaceID: namespace.ID,
+		IPAddresses: MachineAddresses{netaddr.MustParseIP("10.27.0.42")},
+	})
+	_, err = app.PlanMachineRegistration(
+		machineKey,
+		namespace.Name,
+		"",
+		NodeTemplate{},
+		MachineAddresses{netaddr.MustParseIP("10.27.0.42")},
+	)
+	c.Assert(errors.Is(err, errIPAlreadyAllocated), check.Equals, true)
+}
@@ -29,6 +29,8 @@ import (
 const (
 	errCannotDecryptReponse = Error("cannot decrypt response")
 	errCouldNotAllocateIP   = Error("could not find any suitable IP")
+	errIPNotInPrefixes      = Error("IP address is not usable in the configured prefixes")
+	errIPAlreadyAllocated   = Error("IP address is allocated to another machine")
 
 	// These constants are copied from the upstream tailscale.com/types/key
 	// library, because they are not exported.
@@ -159,6 +161,61 @@ func (h *Headscale) getAvailableIPs() (MachineAddresses, error) {
 	return ips, err
 }
 
+// getRequestedIPs returns the addresses of a new machine: the requested
+// ones, checked to be usable and free, and an available one of each
+// configured prefix without a requested address. With no requested
+// address, it is getAvailableIPs.
+func (h *Headscale) getRequestedIPs(requested MachineAddresses) (MachineAddresses, error) {
+	usedIps, err := h.getUsedIPs()
+	if err != nil {
+		return nil, err
+	}
+
+	var ips MachineAddresses
+	for _, ipPrefix := range h.cfg.IPPrefixes {
+		var ip *netaddr.IP
+		for index := range requested {
+			if !ipPrefix.Contains(requested[index]) {
+				continue
+			}
+			if ip != nil {
+				return nil, fmt.Errorf(
+					"%s and %s are both in %s: %w",
+					ip,
+					requested[index],
+					ipPrefix,
+					errIPNotInPrefixes,
+				)
+			}
+			ip = &requested[index]
+		}
+
+		if ip == nil {
+			ip, err = nextAvailableIP(ipPrefix, usedIps)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			network, broadcast := GetIPPrefixEndpoints(ipPrefix)
+			if *ip == network || *ip == broadcast {
+				return nil, fmt.Errorf("%s: %w", ip, errIPNotInPrefixes)
+			}
+			if usedIps.Contains(*ip) {
+				return nil, fmt.Errorf("%s: %w", ip, errIPAlreadyAllocated)
+			}
+		}
+		ips = append(ips, *ip)
+	}
+
+	for _, ip := range requested {
+		if !contains(ips, ip) {
+			return nil, fmt.Errorf("%s: %w", ip, errIPNotInPrefixes)
+		}
+	}
+
+	return ips, nil
+}
+
 func GetIPPrefixEndpoints(na netaddr.IPPrefix) (netaddr.IP, netaddr.IP) {
 	var network, broadcast netaddr.IP
 	ipRange := na.Range()
@@ -174,6 +231,11 @@ func (h *Headscale) getAvailableIP(ipPrefix netaddr.IPPrefix) (*netaddr.IP, erro
 		return nil, err
 	}
 
+	return nextAvailableIP(ipPrefix, usedIps)
+}
+
+// nextAvailableIP is the first address of ipPrefix which is not in usedIps.
+func nextAvailableIP(ipPrefix netaddr.IPPrefix, usedIps *netaddr.IPSet) (*netaddr.IP, error) {
 	ipPrefixNetworkAddress, ipPrefixBroadcastAddress := GetIPPrefixEndpoints(ipPrefix)
 
 	// Get the first IP in our prefix
@@ -283,7 +345,7 @@ func stringToIPPrefix(prefixes []string) ([]netaddr.IPPrefix, error) {
 	return result, nil
 }
 
-func contains[T string | netaddr.IPPrefix](ts []T, t T) bool {
+func contains[T string | netaddr.IPPrefix | netaddr.IP](ts []T, t T) bool {
 	for _, v := range ts {
 		if reflect.DeepEqual(v, t) {
 			return true